@@ -7,20 +7,23 @@ import (
 	"os"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/megakuul/zen/internal/model/email"
 	leaderboardmodel "github.com/megakuul/zen/internal/model/leaderboard"
-	"github.com/megakuul/zen/internal/model/rating"
+	"github.com/megakuul/zen/internal/model/user"
 	"github.com/megakuul/zen/internal/server/v1/leaderboard"
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
 
 type Config struct {
-	LeaderboardQueue        string `env:"LEADERBOARD_QUEUE"`
+	Table                   string `env:"TABLE" env-default:"zen"`
+	LeaderboardBackend      string `env:"LEADERBOARD_BACKEND" env-default:"s3"`
 	LeaderboardBucket       string `env:"LEADERBOARD_BUCKET"`
 	LeaderboardBucketPrefix string `env:"LEADERBOARD_BUCKET_PREFIX"`
+	LeaderboardDir          string `env:"LEADERBOARD_DIR" env-default:"leaderboard"`
 }
 
 func main() {
@@ -39,11 +42,24 @@ func main() {
 		os.Exit(1)
 	}
 	s3Client := s3.NewFromConfig(awsCfg)
-	sqsClient := sqs.NewFromConfig(awsCfg)
+	dynamoClient := dynamodb.NewFromConfig(awsCfg)
 
-	boardModel := leaderboardmodel.New(s3Client, cfg.LeaderboardBucket, cfg.LeaderboardBucketPrefix)
-	ratingModel := rating.New(sqsClient, cfg.LeaderboardQueue)
-	service := leaderboard.New(logger, boardModel, ratingModel)
+	var boardModel leaderboardmodel.BoardStore
+	switch cfg.LeaderboardBackend {
+	case "s3":
+		boardModel = leaderboardmodel.New(s3Client, cfg.LeaderboardBucket, cfg.LeaderboardBucketPrefix)
+	case "file":
+		boardModel, err = leaderboardmodel.NewFile(cfg.LeaderboardDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot open leaderboard directory: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid leaderboard backend '%s'; expected 's3' or 'file'", cfg.LeaderboardBackend)
+		os.Exit(1)
+	}
+	userModel := user.New(dynamoClient, cfg.Table)
+	service := leaderboard.New(logger, boardModel, userModel, userModel, email.New(dynamoClient, cfg.Table))
 
 	lambda.Start(service.Process)
 }
@@ -6,21 +6,27 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/dchest/captcha"
+	dchest "github.com/dchest/captcha"
 	"github.com/ilyakaznacheev/cleanenv"
-	"github.com/matelang/jwt-go-aws-kms/v2/jwtkms"
-	"github.com/megakuul/zen/internal/auth"
-	captchastore "github.com/megakuul/zen/internal/captcha"
+	"github.com/megakuul/zen/internal/captcha"
 	"github.com/megakuul/zen/internal/httplambda"
+	"github.com/megakuul/zen/internal/httpserver"
+	"github.com/megakuul/zen/internal/mail"
+	"github.com/megakuul/zen/internal/model/account"
+	"github.com/megakuul/zen/internal/model/bolt"
+	"github.com/megakuul/zen/internal/model/ceremony"
 	"github.com/megakuul/zen/internal/model/email"
+	leaderboardmodel "github.com/megakuul/zen/internal/model/leaderboard"
+	"github.com/megakuul/zen/internal/model/ratelimit"
+	"github.com/megakuul/zen/internal/model/rating"
 	"github.com/megakuul/zen/internal/model/user"
-	"github.com/megakuul/zen/internal/server/v1/manager/authentication"
-	"github.com/megakuul/zen/internal/server/v1/manager/management"
+	"github.com/megakuul/zen/internal/server"
+	"github.com/megakuul/zen/internal/server/v1/leaderboard"
 	"github.com/megakuul/zen/internal/token"
-	"github.com/megakuul/zen/pkg/api/v1/manager/authentication/authenticationconnect"
-	"github.com/megakuul/zen/pkg/api/v1/manager/management/managementconnect"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -29,15 +35,58 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
 
 type Config struct {
-	Table               string `env:"TABLE"`
-	TokenIssuer         string `env:"TOKEN_ISSUER"`
-	TokenKmsKeyId       string `env:"TOKEN_KMS_KEY_ID"`
-	AuthMailSender      string `env:"AUTH_MAIL_SENDER"`
-	CaptchaBucket       string `env:"CAPTCHA_BUCKET"`
-	CaptchaBucketPrefix string `env:"CAPTCHA_BUCKET_PREFIX"`
+	Mode                 string        `env:"MODE" env-default:"lambda"`
+	ServeAddr            string        `env:"SERVE_ADDR" env-default:":8080"`
+	ServeTlsCert         string        `env:"SERVE_TLS_CERT"`
+	ServeTlsKey          string        `env:"SERVE_TLS_KEY"`
+	ServeCorsOrigins     []string      `env:"SERVE_CORS_ORIGINS"`
+	ServeShutdownTimeout time.Duration `env:"SERVE_SHUTDOWN_TIMEOUT" env-default:"15s"`
+	StorageBackend       string        `env:"STORAGE_BACKEND" env-default:"dynamodb"`
+	StoragePath          string        `env:"STORAGE_PATH" env-default:"zen.db"`
+	Table                string        `env:"TABLE" env-default:"zen"`
+	LeaderboardQueue     string        `env:"LEADERBOARD_QUEUE"`
+	LeaderboardBackend   string        `env:"LEADERBOARD_BACKEND" env-default:"s3"`
+	LeaderboardBucket    string        `env:"LEADERBOARD_BUCKET"`
+	LeaderboardPrefix    string        `env:"LEADERBOARD_BUCKET_PREFIX"`
+	LeaderboardDir       string        `env:"LEADERBOARD_DIR" env-default:"leaderboard"`
+	RatingBackend        string        `env:"RATING_BACKEND"`
+	RatingSpoolDir       string        `env:"RATING_SPOOL_DIR" env-default:"rating-spool"`
+	RatingBatchSize      int           `env:"RATING_BATCH_SIZE" env-default:"10000"`
+	RatingBatchWindow    time.Duration `env:"RATING_BATCH_WINDOW" env-default:"10s"`
+	RateLimitBackend     string        `env:"RATELIMIT_BACKEND" env-default:"dynamodb"`
+	RateLimitTrustProxy  bool          `env:"RATELIMIT_TRUST_PROXY" env-default:"false"`
+	TokenIssuer          string        `env:"TOKEN_ISSUER"`
+	TokenProvider        string        `env:"TOKEN_PROVIDER" env-default:"kms"`
+	TokenKmsKeyId        string        `env:"TOKEN_KMS_KEY_ID"`
+	TokenKmsVerifyKeyIds []string      `env:"TOKEN_KMS_VERIFY_KEY_IDS"`
+	TokenKeyFile         string        `env:"TOKEN_KEY_FILE"`
+	TokenKeyAlgorithm    string        `env:"TOKEN_KEY_ALGORITHM" env-default:"ES256"`
+	AuthMailSender       string        `env:"AUTH_MAIL_SENDER"`
+	MailTransport        string        `env:"MAIL_TRANSPORT" env-default:"ses"`
+	MailSmtpAddr         string        `env:"MAIL_SMTP_ADDR" env-default:"localhost:587"`
+	MailSmtpSecurity     string        `env:"MAIL_SMTP_SECURITY" env-default:"starttls"`
+	MailSmtpUsername     string        `env:"MAIL_SMTP_USERNAME"`
+	MailSmtpPassword     string        `env:"MAIL_SMTP_PASSWORD"`
+	MailMaildir          string        `env:"MAIL_MAILDIR" env-default:"maildir"`
+	CaptchaBackend       string        `env:"CAPTCHA_BACKEND" env-default:"s3"`
+	CaptchaBucket        string        `env:"CAPTCHA_BUCKET"`
+	CaptchaBucketPrefix  string        `env:"CAPTCHA_BUCKET_PREFIX"`
+	CaptchaDir           string        `env:"CAPTCHA_DIR" env-default:"captcha"`
+	CaptchaExpiration    time.Duration `env:"CAPTCHA_EXPIRATION" env-default:"10m"`
+	PasskeyRpId          string        `env:"PASSKEY_RP_ID" env-default:"localhost"`
+	PasskeyRpName        string        `env:"PASSKEY_RP_NAME" env-default:"Zen"`
+	PasskeyRpOrigins     []string      `env:"PASSKEY_RP_ORIGINS" env-default:"http://localhost:5173"`
+	TotpIssuer           string        `env:"TOTP_ISSUER" env-default:"Zen"`
+	OidcIssuer           string        `env:"OIDC_ISSUER"`
+	OidcClientId         string        `env:"OIDC_CLIENT_ID"`
+	OidcClientSecret     string        `env:"OIDC_CLIENT_SECRET"`
+	OidcRedirectUrl      string        `env:"OIDC_REDIRECT_URL" env-default:"http://localhost:5173/login/oidc"`
+	LoginLinkUrl         string        `env:"LOGIN_LINK_URL" env-default:"http://localhost:5173/login/link"`
+	SupportSubjects      []string      `env:"SUPPORT_SUBJECTS"`
 }
 
 func main() {
@@ -59,28 +108,207 @@ func main() {
 	kmsClient := kms.NewFromConfig(awsCfg)
 	s3Client := s3.NewFromConfig(awsCfg)
 	sesClient := ses.NewFromConfig(awsCfg)
+	sqsClient := sqs.NewFromConfig(awsCfg)
 
-	emailModel := email.New(dynamoClient, cfg.Table)
-	userModel := user.New(dynamoClient, cfg.Table)
-	tokenCtrl := token.New(cfg.TokenIssuer, jwtkms.NewKMSConfig(kmsClient, cfg.TokenKmsKeyId, false))
-	authCtrl := auth.New(emailModel, sesClient, cfg.AuthMailSender)
+	var accountModel account.Store
+	var emailModel email.Store
+	var userModel user.Store
+	var ceremonyModel ceremony.Store
+	switch cfg.StorageBackend {
+	case "dynamodb":
+		accountModel = account.New(dynamoClient, cfg.Table)
+		emailModel = email.New(dynamoClient, cfg.Table)
+		userModel = user.New(dynamoClient, cfg.Table)
+		ceremonyModel = ceremony.New(dynamoClient, cfg.Table)
+	case "bolt":
+		table, err := bolt.Open(cfg.StoragePath, cfg.Table)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot open bolt storage: %v", err)
+			os.Exit(1)
+		}
+		defer table.Close()
+		accountModel = account.NewBolt(table)
+		emailModel = email.NewBolt(table)
+		userModel = user.NewBolt(table)
+		ceremonyModel = ceremony.NewBolt(table)
+	default:
+		fmt.Fprintf(os.Stderr, "invalid storage backend '%s'; expected 'dynamodb' or 'bolt'", cfg.StorageBackend)
+		os.Exit(1)
+	}
+	var boardModel leaderboardmodel.BoardStore
+	switch cfg.LeaderboardBackend {
+	case "s3":
+		boardModel = leaderboardmodel.New(s3Client, cfg.LeaderboardBucket, cfg.LeaderboardPrefix)
+	case "file":
+		boardModel, err = leaderboardmodel.NewFile(cfg.LeaderboardDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot open leaderboard directory: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid leaderboard backend '%s'; expected 's3' or 'file'", cfg.LeaderboardBackend)
+		os.Exit(1)
+	}
+	// account deletions are queued as rating updates, the standalone storage has no leaderboard lambda to process them.
+	if cfg.RatingBackend == "" && cfg.StorageBackend == "bolt" {
+		cfg.RatingBackend = "bus"
+	} else if cfg.RatingBackend == "" {
+		cfg.RatingBackend = "sqs"
+	}
+	var ratingModel rating.Sender
+	switch cfg.RatingBackend {
+	case "sqs":
+		ratingModel = rating.New(sqsClient, cfg.LeaderboardQueue)
+	case "bus":
+		bus, err := rating.NewBus(logger, cfg.RatingSpoolDir,
+			rating.WithBatchSize(cfg.RatingBatchSize),
+			rating.WithBatchWindow(cfg.RatingBatchWindow),
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create rating bus: %v", err)
+			os.Exit(1)
+		}
+		go func() {
+			if err := bus.Run(context.Background(), leaderboard.New(logger, boardModel, userModel, userModel, emailModel).Process); err != nil {
+				logger.Error(fmt.Sprintf("rating bus terminated: %v", err))
+			}
+		}()
+		ratingModel = bus
+	default:
+		fmt.Fprintf(os.Stderr, "invalid rating backend '%s'; expected 'sqs' or 'bus'", cfg.RatingBackend)
+		os.Exit(1)
+	}
+	var tokenProvider token.Provider
+	switch cfg.TokenProvider {
+	case "kms":
+		// the additional keys are only used for verification (next key before it is activated, previous key before it is retired).
+		verifyProviders := []token.Provider{}
+		for _, keyId := range cfg.TokenKmsVerifyKeyIds {
+			if keyId != "" {
+				verifyProviders = append(verifyProviders, token.NewKms(kmsClient, keyId))
+			}
+		}
+		tokenProvider = token.NewKeyRing(token.NewKms(kmsClient, cfg.TokenKmsKeyId), verifyProviders...)
+	case "local":
+		// without key file the key is ephemeral, tokens are invalidated on restart and not shared across processes.
+		if cfg.TokenKeyFile != "" {
+			tokenProvider, err = token.LoadLocal(cfg.TokenKeyFile, cfg.TokenKeyAlgorithm)
+		} else {
+			tokenProvider, err = token.GenerateLocal(cfg.TokenKeyAlgorithm)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load local token key: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid token provider '%s'; expected 'kms' or 'local'", cfg.TokenProvider)
+		os.Exit(1)
+	}
+	var mailer mail.Mailer
+	switch cfg.MailTransport {
+	case "ses":
+		mailer = mail.NewSes(sesClient, cfg.AuthMailSender)
+	case "smtp":
+		mailer, err = mail.NewSmtp(cfg.MailSmtpAddr, cfg.AuthMailSender, cfg.MailSmtpSecurity, cfg.MailSmtpUsername, cfg.MailSmtpPassword)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create smtp mailer: %v", err)
+			os.Exit(1)
+		}
+	case "log":
+		mailer = mail.NewLog(logger)
+	case "maildir":
+		mailer, err = mail.NewMaildir(cfg.MailMaildir, cfg.AuthMailSender)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create maildir mailer: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid mail transport '%s'; expected 'ses', 'smtp', 'log' or 'maildir'", cfg.MailTransport)
+		os.Exit(1)
+	}
+	var captchaStore dchest.Store
+	switch cfg.CaptchaBackend {
+	case "s3":
+		captchaStore = captcha.NewS3(s3Client, logger, 2*time.Second, cfg.CaptchaBucket, cfg.CaptchaBucketPrefix)
+	case "memory":
+		captchaStore = captcha.NewMemory(cfg.CaptchaExpiration)
+	case "file":
+		fileStore, err := captcha.NewFile(logger, cfg.CaptchaDir, cfg.CaptchaExpiration)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create captcha directory: %v", err)
+			os.Exit(1)
+		}
+		go fileStore.Sweep(context.Background(), cfg.CaptchaExpiration)
+		captchaStore = fileStore
+	default:
+		fmt.Fprintf(os.Stderr, "invalid captcha backend '%s'; expected 's3', 'memory' or 'file'", cfg.CaptchaBackend)
+		os.Exit(1)
+	}
+	var rateLimitModel ratelimit.Store
+	switch cfg.RateLimitBackend {
+	case "dynamodb":
+		rateLimitModel = ratelimit.New(dynamoClient, cfg.Table)
+	case "memory":
+		// buckets are local to the process, the limits are not shared across instances.
+		rateLimitModel = ratelimit.NewMemory()
+	default:
+		fmt.Fprintf(os.Stderr, "invalid rate limit backend '%s'; expected 'dynamodb' or 'memory'", cfg.RateLimitBackend)
+		os.Exit(1)
+	}
 
+	srv := server.New(logger, &server.Config{
+		TokenIssuer:      cfg.TokenIssuer,
+		TrustProxy:       cfg.RateLimitTrustProxy,
+		LoginLinkUrl:     cfg.LoginLinkUrl,
+		SupportSubjects:  cfg.SupportSubjects,
+		PasskeyRpId:      cfg.PasskeyRpId,
+		PasskeyRpName:    cfg.PasskeyRpName,
+		PasskeyRpOrigins: cfg.PasskeyRpOrigins,
+		TotpIssuer:       cfg.TotpIssuer,
+		OidcIssuer:       cfg.OidcIssuer,
+		OidcClientId:     cfg.OidcClientId,
+		OidcClientSecret: cfg.OidcClientSecret,
+		OidcRedirectUrl:  cfg.OidcRedirectUrl,
+	}, &server.Models{
+		Accounts:   accountModel,
+		Emails:     emailModel,
+		Users:      userModel,
+		Ceremonies: ceremonyModel,
+		RateLimits: rateLimitModel,
+		Boards:     boardModel,
+		Ratings:    ratingModel,
+	}, tokenProvider)
 	mux := http.NewServeMux()
-	mux.Handle(
-		authenticationconnect.NewAuthenticationServiceHandler(authentication.New(logger, tokenCtrl, authCtrl, emailModel)),
-	)
-	mux.Handle(
-		managementconnect.NewManagementServiceHandler(management.New(logger, tokenCtrl, authCtrl, userModel, emailModel)),
-	)
+	if err := srv.RegisterManager(context.Background(), mux, mailer, captchaStore); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create manager api: %v", err)
+		os.Exit(1)
+	}
 
-	// no I'm not responsible for this global setCustomStore mess :<
-	captcha.SetCustomStore(captchastore.New(
-		s3Client,
-		logger, 2*time.Second,
-		cfg.CaptchaBucket, cfg.CaptchaBucketPrefix,
-	))
+	switch cfg.Mode {
+	case "lambda":
+		lambda.Start(createHandler(mux))
+	case "serve":
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		if err := createServer(logger, cfg, mux).Serve(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "server terminated: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid mode '%s'; expected 'lambda' or 'serve'", cfg.Mode)
+		os.Exit(1)
+	}
+}
 
-	lambda.Start(createHandler(mux))
+func createServer(logger *slog.Logger, cfg *Config, mux *http.ServeMux) *httpserver.Server {
+	opts := []httpserver.Option{
+		httpserver.WithCors(cfg.ServeCorsOrigins),
+		httpserver.WithShutdownTimeout(cfg.ServeShutdownTimeout),
+	}
+	if cfg.ServeTlsCert != "" {
+		opts = append(opts, httpserver.WithTLS(cfg.ServeTlsCert, cfg.ServeTlsKey))
+	}
+	return httpserver.New(logger, cfg.ServeAddr, mux, opts...)
 }
 
 func createHandler(mux *http.ServeMux) func(ctx context.Context, r events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/kms/types"
 	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
@@ -21,6 +22,8 @@ import (
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/megakuul/zen/cmd/monk/launch"
 	"github.com/megakuul/zen/cmd/monk/nuke"
+	"github.com/megakuul/zen/cmd/monk/reconcile"
+	"github.com/megakuul/zen/cmd/monk/rotate"
 	"github.com/megakuul/zen/internal/deploy"
 	"github.com/pterm/pterm"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto"
@@ -55,11 +58,12 @@ func main() {
 // Useful for updates where only certain parameters have to be changed.
 // (pulumis IgnoreChange option is not sufficiently versatile for this use case).
 type appConfig struct {
-	Project          string   `json:"project"`
-	Domains          []string `json:"domains"`
-	AutoDns          bool     `json:"auto_dns"`
-	DeleteProtection bool     `json:"delete_protection"`
-	StateKey         string   `json:"state_key"`
+	Project          string       `json:"project"`
+	Domains          []string     `json:"domains"`
+	AutoDns          bool         `json:"auto_dns"`
+	DeleteProtection bool         `json:"delete_protection"`
+	StateKey         string       `json:"state_key"`
+	TokenKeys        *rotate.Ring `json:"token_keys,omitempty"`
 }
 
 func run(ctx context.Context) error {
@@ -102,6 +106,9 @@ func run(ctx context.Context) error {
 			return fmt.Errorf("failed to parse app config: %v", err)
 		}
 	}
+	if config.TokenKeys == nil {
+		config.TokenKeys = rotate.DefaultRing()
+	}
 
 	if ok, _ := pterm.DefaultInteractiveConfirm.
 		WithDefaultValue(config.Project == "").Show("Customize project name?"); ok {
@@ -155,6 +162,7 @@ func run(ctx context.Context) error {
 	}
 	operatorOptions = append(operatorOptions, deploy.WithBuildPath("."))
 	operatorOptions = append(operatorOptions, deploy.WithDomain(config.Domains))
+	operatorOptions = append(operatorOptions, deploy.WithTokenKeys(config.TokenKeys.Active, config.TokenKeys.Verify()))
 	operator := deploy.New(cfg.Region, operatorOptions...)
 
 	ws, err := auto.NewLocalWorkspace(ctx, auto.Project(workspace.Project{
@@ -180,17 +188,8 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	rawConfig, err := json.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("failed to serialize app config: %v", err)
-	}
-	_, err = s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(configKey),
-		Body:   bytes.NewReader(rawConfig),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to upload app config: %v", err)
+	if err := saveConfig(ctx, s3Client, bucket, configKey, &config); err != nil {
+		return err
 	}
 
 	spinner, _ := pterm.DefaultSpinner.WithRemoveWhenDone(true).
@@ -205,10 +204,29 @@ func run(ctx context.Context) error {
 		return launch.Launch(ctx, ws)
 	} else {
 		action, _ := pterm.DefaultInteractiveSelect.
-			WithOptions([]string{"launch", "nuke"}).Show("Select action")
+			WithOptions([]string{"launch", "rotate", "reconcile", "nuke"}).Show("Select action")
 		switch action {
 		case "launch":
 			return launch.Launch(ctx, ws)
+		case "rotate":
+			config.TokenKeys, err = rotate.Rotate(config.TokenKeys)
+			if err != nil {
+				return err
+			}
+			// the operator program is evaluated on launch, so the updated ring is deployed.
+			deploy.WithTokenKeys(config.TokenKeys.Active, config.TokenKeys.Verify())(operator)
+			if err := launch.Launch(ctx, ws); err != nil {
+				return err
+			}
+			return saveConfig(ctx, s3Client, bucket, configKey, &config)
+		case "reconcile":
+			for _, stack := range stacks {
+				err = reconcile.Reconcile(ctx, ws, stack.Name, dynamodb.NewFromConfig(cfg))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
 		case "nuke":
 			for _, stack := range stacks {
 				err = nuke.Nuke(ctx, ws, stack.Name)
@@ -225,6 +243,22 @@ func run(ctx context.Context) error {
 	}
 }
 
+func saveConfig(ctx context.Context, client *s3.Client, bucket, key string, config *appConfig) error {
+	rawConfig, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to serialize app config: %v", err)
+	}
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(rawConfig),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload app config: %v", err)
+	}
+	return nil
+}
+
 func setupKey(ctx context.Context, client *kms.Client) (string, error) {
 	name, _ := pterm.DefaultInteractiveTextInput.
 		WithDefaultValue("zen-state-key").Show("Enter key alias name")
@@ -0,0 +1,66 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/megakuul/zen/internal/model/account"
+	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/model/user"
+	"github.com/megakuul/zen/internal/reconcile"
+	"github.com/pterm/pterm"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+)
+
+// Reconcile performs an interactive process to remove orphaned accounts from the table of a running application.
+func Reconcile(ctx context.Context, ws auto.Workspace, stackName string, client *dynamodb.Client) error {
+	stack, err := auto.SelectStack(ctx, stackName, ws)
+	if err != nil {
+		return fmt.Errorf("failed to load stack: %v", err)
+	}
+	outputs, err := stack.Outputs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load stack outputs: %v", err)
+	}
+	table, ok := outputs["TABLE"].Value.(string)
+	if !ok {
+		return fmt.Errorf("stack '%s' has no table output (launch the stack to update it)", stackName)
+	}
+	userModel := user.New(client, table)
+	emailModel := email.New(client, table)
+	controller := reconcile.New(account.New(client, table), userModel, userModel, emailModel)
+
+	spinner, _ := pterm.DefaultSpinner.WithRemoveWhenDone(true).
+		Start(fmt.Sprintf("Searching for orphaned accounts (%s)...", stackName))
+	defer spinner.Stop()
+	orphans, err := controller.Find(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find orphans: %v", err)
+	}
+	spinner.Stop()
+	if len(orphans.Profiles) < 1 && len(orphans.Registrations) < 1 {
+		pterm.DefaultBasicText.Println("No orphaned accounts found ☯️")
+		return nil
+	}
+	for _, sub := range orphans.Profiles {
+		pterm.DefaultBasicText.Printf(" - profile without registration: %s\n", sub)
+	}
+	for addr, sub := range orphans.Registrations {
+		pterm.DefaultBasicText.Printf(" - registration without profile: %s (%s)\n", addr, sub)
+	}
+	ok, _ = pterm.DefaultInteractiveConfirm.
+		WithDefaultValue(false).Show("Remove the orphaned accounts (including their data)?")
+	if !ok {
+		return fmt.Errorf("process cancelled")
+	}
+
+	spinner, _ = pterm.DefaultSpinner.WithRemoveWhenDone(true).
+		Start(fmt.Sprintf("Removing orphaned accounts (%s)...", stackName))
+	defer spinner.Stop()
+	if err := controller.Clean(ctx, orphans); err != nil {
+		return fmt.Errorf("failed to remove orphans: %v", err)
+	}
+	pterm.DefaultBasicText.Println("Success 🎉 Orphaned accounts were removed 🧹")
+	return nil
+}
@@ -0,0 +1,86 @@
+package rotate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/megakuul/zen/internal/server/v1/manager/authentication"
+	"github.com/megakuul/zen/internal/server/v1/manager/management"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// stageDelay is the time the next key is published before it signs tokens (jwks cache of other verifiers).
+	stageDelay = 10 * time.Minute
+	// retireDelay is the time the previous key is accepted after the switch (until all tokens signed by it are expired).
+	retireDelay = max(authentication.RefreshTokenTTL, management.PersonalTokenMaxTTL)
+)
+
+// Ring is the rotation state of the token signing keys (kms key resource names), it is stored in the app config.
+type Ring struct {
+	Active string `json:"active"`
+	// Next is the staged key, it is published and accepted but does not sign tokens yet.
+	Next string `json:"next,omitempty"`
+	// Previous is the retiring key, it is accepted until the tokens signed by it are expired.
+	Previous string `json:"previous,omitempty"`
+	// Since is the unix time of the last rotation step.
+	Since int64 `json:"since,omitempty"`
+}
+
+// DefaultRing returns the ring of deployments that were never rotated (single key named "kms").
+func DefaultRing() *Ring {
+	return &Ring{Active: "kms"}
+}
+
+// Verify returns the keys that are only used to verify tokens.
+func (r *Ring) Verify() []string {
+	keys := []string{}
+	if r.Next != "" {
+		keys = append(keys, r.Next)
+	}
+	if r.Previous != "" {
+		keys = append(keys, r.Previous)
+	}
+	return keys
+}
+
+// Rotate performs an interactive process to advance the staged rotation by one step:
+// stage a new key, switch signing to it, retire the previous key. The returned ring must be deployed.
+func Rotate(ring *Ring) (*Ring, error) {
+	now := time.Now()
+	since := time.Unix(ring.Since, 0)
+	next := *ring
+	switch {
+	case ring.Previous != "":
+		if retireAt := since.Add(retireDelay); now.Before(retireAt) {
+			return nil, fmt.Errorf("the previous key (%s) is accepted until %s", ring.Previous, retireAt.Format(time.RFC1123))
+		}
+		ok, _ := pterm.DefaultInteractiveConfirm.WithDefaultValue(false).
+			Show(fmt.Sprintf("Retire the previous key (%s)?", ring.Previous))
+		if !ok {
+			return nil, fmt.Errorf("process cancelled")
+		}
+		next.Previous = ""
+	case ring.Next != "":
+		if activateAt := since.Add(stageDelay); now.Before(activateAt) {
+			return nil, fmt.Errorf("the next key (%s) can sign tokens after %s", ring.Next, activateAt.Format(time.RFC1123))
+		}
+		ok, _ := pterm.DefaultInteractiveConfirm.WithDefaultValue(false).
+			Show(fmt.Sprintf("Switch signing to the next key (%s)?", ring.Next))
+		if !ok {
+			return nil, fmt.Errorf("process cancelled")
+		}
+		next.Previous = ring.Active
+		next.Active = ring.Next
+		next.Next = ""
+	default:
+		ok, _ := pterm.DefaultInteractiveConfirm.WithDefaultValue(false).
+			Show("Stage a new signing key?")
+		if !ok {
+			return nil, fmt.Errorf("process cancelled")
+		}
+		next.Next = fmt.Sprintf("kms-%d", now.Unix())
+	}
+	next.Since = now.Unix()
+	return &next, nil
+}
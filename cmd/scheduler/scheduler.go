@@ -6,18 +6,20 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
-	"github.com/matelang/jwt-go-aws-kms/v2/jwtkms"
 	"github.com/megakuul/zen/internal/httplambda"
+	"github.com/megakuul/zen/internal/httpserver"
+	"github.com/megakuul/zen/internal/model/bolt"
+	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/model/ratelimit"
 	"github.com/megakuul/zen/internal/model/rating"
 	"github.com/megakuul/zen/internal/model/user"
-	"github.com/megakuul/zen/internal/server/v1/scheduler/planning"
-	"github.com/megakuul/zen/internal/server/v1/scheduler/timing"
+	"github.com/megakuul/zen/internal/server"
 	"github.com/megakuul/zen/internal/token"
-	"github.com/megakuul/zen/pkg/api/v1/scheduler/planning/planningconnect"
-	"github.com/megakuul/zen/pkg/api/v1/scheduler/timing/timingconnect"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -28,11 +30,25 @@ import (
 )
 
 type Config struct {
-	Table            string        `env:"TABLE"`
-	TokenIssuer      string        `env:"TOKEN_ISSUER"`
-	TokenKmsKeyId    string        `env:"TOKEN_KMS_KEY_ID"`
-	LeaderboardQueue string        `env:"LEADERBOARD_QUEUE"`
-	RatingAnchor     time.Duration `env:"RATING_ANCHOR" env-default:"2m"`
+	Mode                 string        `env:"MODE" env-default:"lambda"`
+	ServeAddr            string        `env:"SERVE_ADDR" env-default:":8080"`
+	ServeTlsCert         string        `env:"SERVE_TLS_CERT"`
+	ServeTlsKey          string        `env:"SERVE_TLS_KEY"`
+	ServeCorsOrigins     []string      `env:"SERVE_CORS_ORIGINS"`
+	ServeShutdownTimeout time.Duration `env:"SERVE_SHUTDOWN_TIMEOUT" env-default:"15s"`
+	StorageBackend       string        `env:"STORAGE_BACKEND" env-default:"dynamodb"`
+	StoragePath          string        `env:"STORAGE_PATH" env-default:"zen.db"`
+	Table                string        `env:"TABLE" env-default:"zen"`
+	RateLimitBackend     string        `env:"RATELIMIT_BACKEND" env-default:"dynamodb"`
+	RateLimitTrustProxy  bool          `env:"RATELIMIT_TRUST_PROXY" env-default:"false"`
+	TokenIssuer          string        `env:"TOKEN_ISSUER"`
+	TokenProvider        string        `env:"TOKEN_PROVIDER" env-default:"kms"`
+	TokenKmsKeyId        string        `env:"TOKEN_KMS_KEY_ID"`
+	TokenKmsVerifyKeyIds []string      `env:"TOKEN_KMS_VERIFY_KEY_IDS"`
+	TokenKeyFile         string        `env:"TOKEN_KEY_FILE"`
+	TokenKeyAlgorithm    string        `env:"TOKEN_KEY_ALGORITHM" env-default:"ES256"`
+	LeaderboardQueue     string        `env:"LEADERBOARD_QUEUE"`
+	RatingAnchor         time.Duration `env:"RATING_ANCHOR" env-default:"2m"`
 }
 
 func main() {
@@ -54,18 +70,103 @@ func main() {
 	sqsClient := sqs.NewFromConfig(awsCfg)
 	kmsClient := kms.NewFromConfig(awsCfg)
 
-	userModel := user.New(dynamoClient, cfg.Table)
+	var userModel user.Store
+	var emailModel email.Store
+	switch cfg.StorageBackend {
+	case "dynamodb":
+		userModel = user.New(dynamoClient, cfg.Table)
+		emailModel = email.New(dynamoClient, cfg.Table)
+	case "bolt":
+		table, err := bolt.Open(cfg.StoragePath, cfg.Table)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot open bolt storage: %v", err)
+			os.Exit(1)
+		}
+		defer table.Close()
+		userModel = user.NewBolt(table)
+		emailModel = email.NewBolt(table)
+	default:
+		fmt.Fprintf(os.Stderr, "invalid storage backend '%s'; expected 'dynamodb' or 'bolt'", cfg.StorageBackend)
+		os.Exit(1)
+	}
 	ratingModel := rating.New(sqsClient, cfg.LeaderboardQueue)
-	tokenCtrl := token.New(cfg.TokenIssuer, jwtkms.NewKMSConfig(kmsClient, cfg.TokenKmsKeyId, false))
+	var tokenProvider token.Provider
+	switch cfg.TokenProvider {
+	case "kms":
+		// the additional keys are only used for verification (next key before it is activated, previous key before it is retired).
+		verifyProviders := []token.Provider{}
+		for _, keyId := range cfg.TokenKmsVerifyKeyIds {
+			if keyId != "" {
+				verifyProviders = append(verifyProviders, token.NewKms(kmsClient, keyId))
+			}
+		}
+		tokenProvider = token.NewKeyRing(token.NewKms(kmsClient, cfg.TokenKmsKeyId), verifyProviders...)
+	case "local":
+		// without key file the key is ephemeral, tokens are invalidated on restart and not shared across processes.
+		if cfg.TokenKeyFile != "" {
+			tokenProvider, err = token.LoadLocal(cfg.TokenKeyFile, cfg.TokenKeyAlgorithm)
+		} else {
+			tokenProvider, err = token.GenerateLocal(cfg.TokenKeyAlgorithm)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load local token key: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid token provider '%s'; expected 'kms' or 'local'", cfg.TokenProvider)
+		os.Exit(1)
+	}
 
+	var rateLimitModel ratelimit.Store
+	switch cfg.RateLimitBackend {
+	case "dynamodb":
+		rateLimitModel = ratelimit.New(dynamoClient, cfg.Table)
+	case "memory":
+		// buckets are local to the process, the limits are not shared across instances.
+		rateLimitModel = ratelimit.NewMemory()
+	default:
+		fmt.Fprintf(os.Stderr, "invalid rate limit backend '%s'; expected 'dynamodb' or 'memory'", cfg.RateLimitBackend)
+		os.Exit(1)
+	}
+
+	srv := server.New(logger, &server.Config{
+		TokenIssuer:  cfg.TokenIssuer,
+		TrustProxy:   cfg.RateLimitTrustProxy,
+		RatingAnchor: cfg.RatingAnchor,
+	}, &server.Models{
+		Emails:     emailModel,
+		Users:      userModel,
+		RateLimits: rateLimitModel,
+		Ratings:    ratingModel,
+	}, tokenProvider)
 	mux := http.NewServeMux()
-	mux.Handle(
-		planningconnect.NewPlanningServiceHandler(planning.New(logger, tokenCtrl, userModel)),
-	)
-	mux.Handle(
-		timingconnect.NewTimingServiceHandler(timing.New(logger, tokenCtrl, userModel, ratingModel, cfg.RatingAnchor)),
-	)
-	lambda.Start(createHandler(mux))
+	srv.RegisterScheduler(mux)
+
+	switch cfg.Mode {
+	case "lambda":
+		lambda.Start(createHandler(mux))
+	case "serve":
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		if err := createServer(logger, cfg, mux).Serve(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "server terminated: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid mode '%s'; expected 'lambda' or 'serve'", cfg.Mode)
+		os.Exit(1)
+	}
+}
+
+func createServer(logger *slog.Logger, cfg *Config, mux *http.ServeMux) *httpserver.Server {
+	opts := []httpserver.Option{
+		httpserver.WithCors(cfg.ServeCorsOrigins),
+		httpserver.WithShutdownTimeout(cfg.ServeShutdownTimeout),
+	}
+	if cfg.ServeTlsCert != "" {
+		opts = append(opts, httpserver.WithTLS(cfg.ServeTlsCert, cfg.ServeTlsKey))
+	}
+	return httpserver.New(logger, cfg.ServeAddr, mux, opts...)
 }
 
 func createHandler(mux *http.ServeMux) func(ctx context.Context, r events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	dchest "github.com/dchest/captcha"
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/megakuul/zen/internal/captcha"
+	"github.com/megakuul/zen/internal/httpserver"
+	"github.com/megakuul/zen/internal/mail"
+	"github.com/megakuul/zen/internal/model/account"
+	"github.com/megakuul/zen/internal/model/bolt"
+	"github.com/megakuul/zen/internal/model/ceremony"
+	"github.com/megakuul/zen/internal/model/email"
+	leaderboardmodel "github.com/megakuul/zen/internal/model/leaderboard"
+	"github.com/megakuul/zen/internal/model/ratelimit"
+	"github.com/megakuul/zen/internal/model/rating"
+	"github.com/megakuul/zen/internal/model/user"
+	"github.com/megakuul/zen/internal/server"
+	"github.com/megakuul/zen/internal/server/v1/leaderboard"
+	"github.com/megakuul/zen/internal/token"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// Config combines the scheduler and manager configuration, as zen serves all apis from one process.
+type Config struct {
+	ServeAddr            string        `env:"SERVE_ADDR" env-default:":8080"`
+	ServeTlsCert         string        `env:"SERVE_TLS_CERT"`
+	ServeTlsKey          string        `env:"SERVE_TLS_KEY"`
+	ServeCorsOrigins     []string      `env:"SERVE_CORS_ORIGINS"`
+	ServeShutdownTimeout time.Duration `env:"SERVE_SHUTDOWN_TIMEOUT" env-default:"15s"`
+	StorageBackend       string        `env:"STORAGE_BACKEND" env-default:"dynamodb"`
+	StoragePath          string        `env:"STORAGE_PATH" env-default:"zen.db"`
+	Table                string        `env:"TABLE" env-default:"zen"`
+	RateLimitBackend     string        `env:"RATELIMIT_BACKEND" env-default:"memory"`
+	RateLimitTrustProxy  bool          `env:"RATELIMIT_TRUST_PROXY" env-default:"false"`
+	TokenIssuer          string        `env:"TOKEN_ISSUER"`
+	TokenProvider        string        `env:"TOKEN_PROVIDER" env-default:"kms"`
+	TokenKmsKeyId        string        `env:"TOKEN_KMS_KEY_ID"`
+	TokenKmsVerifyKeyIds []string      `env:"TOKEN_KMS_VERIFY_KEY_IDS"`
+	TokenKeyFile         string        `env:"TOKEN_KEY_FILE"`
+	TokenKeyAlgorithm    string        `env:"TOKEN_KEY_ALGORITHM" env-default:"ES256"`
+	AuthMailSender       string        `env:"AUTH_MAIL_SENDER"`
+	MailTransport        string        `env:"MAIL_TRANSPORT" env-default:"ses"`
+	MailSmtpAddr         string        `env:"MAIL_SMTP_ADDR" env-default:"localhost:587"`
+	MailSmtpSecurity     string        `env:"MAIL_SMTP_SECURITY" env-default:"starttls"`
+	MailSmtpUsername     string        `env:"MAIL_SMTP_USERNAME"`
+	MailSmtpPassword     string        `env:"MAIL_SMTP_PASSWORD"`
+	MailMaildir          string        `env:"MAIL_MAILDIR" env-default:"maildir"`
+	CaptchaBackend       string        `env:"CAPTCHA_BACKEND" env-default:"s3"`
+	CaptchaBucket        string        `env:"CAPTCHA_BUCKET"`
+	CaptchaBucketPrefix  string        `env:"CAPTCHA_BUCKET_PREFIX"`
+	CaptchaDir           string        `env:"CAPTCHA_DIR" env-default:"captcha"`
+	CaptchaExpiration    time.Duration `env:"CAPTCHA_EXPIRATION" env-default:"10m"`
+	PasskeyRpId          string        `env:"PASSKEY_RP_ID" env-default:"localhost"`
+	PasskeyRpName        string        `env:"PASSKEY_RP_NAME" env-default:"Zen"`
+	PasskeyRpOrigins     []string      `env:"PASSKEY_RP_ORIGINS" env-default:"http://localhost:5173"`
+	TotpIssuer           string        `env:"TOTP_ISSUER" env-default:"Zen"`
+	OidcIssuer           string        `env:"OIDC_ISSUER"`
+	OidcClientId         string        `env:"OIDC_CLIENT_ID"`
+	OidcClientSecret     string        `env:"OIDC_CLIENT_SECRET"`
+	OidcRedirectUrl      string        `env:"OIDC_REDIRECT_URL" env-default:"http://localhost:5173/login/oidc"`
+	LoginLinkUrl         string        `env:"LOGIN_LINK_URL" env-default:"http://localhost:5173/login/link"`
+	SupportSubjects      []string      `env:"SUPPORT_SUBJECTS"`
+	LeaderboardQueue     string        `env:"LEADERBOARD_QUEUE"`
+	LeaderboardBucket    string        `env:"LEADERBOARD_BUCKET"`
+	LeaderboardPrefix    string        `env:"LEADERBOARD_BUCKET_PREFIX"`
+	LeaderboardDir       string        `env:"LEADERBOARD_DIR"`
+	RatingAnchor         time.Duration `env:"RATING_ANCHOR" env-default:"2m"`
+	RatingBackend        string        `env:"RATING_BACKEND"`
+	RatingSpoolDir       string        `env:"RATING_SPOOL_DIR" env-default:"rating-spool"`
+	RatingBatchSize      int           `env:"RATING_BATCH_SIZE" env-default:"10000"`
+	RatingBatchWindow    time.Duration `env:"RATING_BATCH_WINDOW" env-default:"10s"`
+}
+
+func main() {
+	cfg := &Config{}
+	if err := cleanenv.ReadEnv(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot acquire env config: %v", err)
+		os.Exit(1)
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		AddSource: true,
+	}))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	awsCfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot load aws config: %v", err)
+		os.Exit(1)
+	}
+	dynamoClient := dynamodb.NewFromConfig(awsCfg)
+	kmsClient := kms.NewFromConfig(awsCfg)
+	s3Client := s3.NewFromConfig(awsCfg)
+	sesClient := ses.NewFromConfig(awsCfg)
+	sqsClient := sqs.NewFromConfig(awsCfg)
+
+	var accountModel account.Store
+	var emailModel email.Store
+	var userModel user.Store
+	var ceremonyModel ceremony.Store
+	switch cfg.StorageBackend {
+	case "dynamodb":
+		accountModel = account.New(dynamoClient, cfg.Table)
+		emailModel = email.New(dynamoClient, cfg.Table)
+		userModel = user.New(dynamoClient, cfg.Table)
+		ceremonyModel = ceremony.New(dynamoClient, cfg.Table)
+	case "bolt":
+		table, err := bolt.Open(cfg.StoragePath, cfg.Table)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot open bolt storage: %v", err)
+			os.Exit(1)
+		}
+		defer table.Close()
+		accountModel = account.NewBolt(table)
+		emailModel = email.NewBolt(table)
+		userModel = user.NewBolt(table)
+		ceremonyModel = ceremony.NewBolt(table)
+	default:
+		fmt.Fprintf(os.Stderr, "invalid storage backend '%s'; expected 'dynamodb' or 'bolt'", cfg.StorageBackend)
+		os.Exit(1)
+	}
+	var boardModel leaderboardmodel.BoardStore
+	if cfg.LeaderboardDir != "" {
+		boardModel, err = leaderboardmodel.NewFile(cfg.LeaderboardDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot open leaderboard directory: %v", err)
+			os.Exit(1)
+		}
+	} else {
+		boardModel = leaderboardmodel.New(s3Client, cfg.LeaderboardBucket, cfg.LeaderboardPrefix)
+	}
+	// the standalone storage has no leaderboard lambda, updates (and account deletions) are processed in-process.
+	if cfg.RatingBackend == "" && cfg.StorageBackend == "bolt" {
+		cfg.RatingBackend = "bus"
+	} else if cfg.RatingBackend == "" {
+		cfg.RatingBackend = "sqs"
+	}
+	var ratingModel rating.Sender
+	switch cfg.RatingBackend {
+	case "sqs":
+		ratingModel = rating.New(sqsClient, cfg.LeaderboardQueue)
+	case "bus":
+		// the bus replaces the queue and the leaderboard lambda, updates are processed inside this process.
+		bus, err := rating.NewBus(logger, cfg.RatingSpoolDir,
+			rating.WithBatchSize(cfg.RatingBatchSize),
+			rating.WithBatchWindow(cfg.RatingBatchWindow),
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create rating bus: %v", err)
+			os.Exit(1)
+		}
+		go func() {
+			if err := bus.Run(ctx, leaderboard.New(logger, boardModel, userModel, userModel, emailModel).Process); err != nil {
+				logger.Error(fmt.Sprintf("rating bus terminated: %v", err))
+			}
+		}()
+		ratingModel = bus
+	default:
+		fmt.Fprintf(os.Stderr, "invalid rating backend '%s'; expected 'sqs' or 'bus'", cfg.RatingBackend)
+		os.Exit(1)
+	}
+	var tokenProvider token.Provider
+	switch cfg.TokenProvider {
+	case "kms":
+		// the additional keys are only used for verification (next key before it is activated, previous key before it is retired).
+		verifyProviders := []token.Provider{}
+		for _, keyId := range cfg.TokenKmsVerifyKeyIds {
+			if keyId != "" {
+				verifyProviders = append(verifyProviders, token.NewKms(kmsClient, keyId))
+			}
+		}
+		tokenProvider = token.NewKeyRing(token.NewKms(kmsClient, cfg.TokenKmsKeyId), verifyProviders...)
+	case "local":
+		// without key file the key is ephemeral, tokens are invalidated on restart and not shared across processes.
+		if cfg.TokenKeyFile != "" {
+			tokenProvider, err = token.LoadLocal(cfg.TokenKeyFile, cfg.TokenKeyAlgorithm)
+		} else {
+			tokenProvider, err = token.GenerateLocal(cfg.TokenKeyAlgorithm)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load local token key: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid token provider '%s'; expected 'kms' or 'local'", cfg.TokenProvider)
+		os.Exit(1)
+	}
+	var mailer mail.Mailer
+	switch cfg.MailTransport {
+	case "ses":
+		mailer = mail.NewSes(sesClient, cfg.AuthMailSender)
+	case "smtp":
+		mailer, err = mail.NewSmtp(cfg.MailSmtpAddr, cfg.AuthMailSender, cfg.MailSmtpSecurity, cfg.MailSmtpUsername, cfg.MailSmtpPassword)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create smtp mailer: %v", err)
+			os.Exit(1)
+		}
+	case "log":
+		mailer = mail.NewLog(logger)
+	case "maildir":
+		mailer, err = mail.NewMaildir(cfg.MailMaildir, cfg.AuthMailSender)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create maildir mailer: %v", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid mail transport '%s'; expected 'ses', 'smtp', 'log' or 'maildir'", cfg.MailTransport)
+		os.Exit(1)
+	}
+	var captchaStore dchest.Store
+	switch cfg.CaptchaBackend {
+	case "s3":
+		captchaStore = captcha.NewS3(s3Client, logger, 2*time.Second, cfg.CaptchaBucket, cfg.CaptchaBucketPrefix)
+	case "memory":
+		captchaStore = captcha.NewMemory(cfg.CaptchaExpiration)
+	case "file":
+		fileStore, err := captcha.NewFile(logger, cfg.CaptchaDir, cfg.CaptchaExpiration)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create captcha directory: %v", err)
+			os.Exit(1)
+		}
+		go fileStore.Sweep(ctx, cfg.CaptchaExpiration)
+		captchaStore = fileStore
+	default:
+		fmt.Fprintf(os.Stderr, "invalid captcha backend '%s'; expected 's3', 'memory' or 'file'", cfg.CaptchaBackend)
+		os.Exit(1)
+	}
+	var rateLimitModel ratelimit.Store
+	switch cfg.RateLimitBackend {
+	case "dynamodb":
+		rateLimitModel = ratelimit.New(dynamoClient, cfg.Table)
+	case "memory":
+		// buckets are local to the process, the limits are not shared across instances.
+		rateLimitModel = ratelimit.NewMemory()
+	default:
+		fmt.Fprintf(os.Stderr, "invalid rate limit backend '%s'; expected 'dynamodb' or 'memory'", cfg.RateLimitBackend)
+		os.Exit(1)
+	}
+
+	srv := server.New(logger, &server.Config{
+		TokenIssuer:      cfg.TokenIssuer,
+		TrustProxy:       cfg.RateLimitTrustProxy,
+		LoginLinkUrl:     cfg.LoginLinkUrl,
+		SupportSubjects:  cfg.SupportSubjects,
+		PasskeyRpId:      cfg.PasskeyRpId,
+		PasskeyRpName:    cfg.PasskeyRpName,
+		PasskeyRpOrigins: cfg.PasskeyRpOrigins,
+		TotpIssuer:       cfg.TotpIssuer,
+		OidcIssuer:       cfg.OidcIssuer,
+		OidcClientId:     cfg.OidcClientId,
+		OidcClientSecret: cfg.OidcClientSecret,
+		OidcRedirectUrl:  cfg.OidcRedirectUrl,
+		RatingAnchor:     cfg.RatingAnchor,
+	}, &server.Models{
+		Accounts:   accountModel,
+		Emails:     emailModel,
+		Users:      userModel,
+		Ceremonies: ceremonyModel,
+		RateLimits: rateLimitModel,
+		Boards:     boardModel,
+		Ratings:    ratingModel,
+	}, tokenProvider)
+	mux := http.NewServeMux()
+	if err := srv.RegisterManager(ctx, mux, mailer, captchaStore); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create manager api: %v", err)
+		os.Exit(1)
+	}
+	srv.RegisterScheduler(mux)
+
+	// boards written by a leaderboard processor with the file backend are exposed like the s3 route of the cdn.
+	if cfg.LeaderboardDir != "" {
+		srv.RegisterBoards(mux)
+	}
+
+	opts := []httpserver.Option{
+		httpserver.WithCors(cfg.ServeCorsOrigins),
+		httpserver.WithShutdownTimeout(cfg.ServeShutdownTimeout),
+	}
+	if cfg.ServeTlsCert != "" {
+		opts = append(opts, httpserver.WithTLS(cfg.ServeTlsCert, cfg.ServeTlsKey))
+	}
+	if err := httpserver.New(logger, cfg.ServeAddr, mux, opts...).Serve(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "server terminated: %v", err)
+		os.Exit(1)
+	}
+}
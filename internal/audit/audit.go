@@ -0,0 +1,100 @@
+// package audit records the security relevant account events in the audit trail of the user.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/megakuul/zen/internal/model/user"
+)
+
+// retention specifies how long audit events are kept (removed by the table ttl).
+const retention = 180 * 24 * time.Hour
+
+const (
+	TypeLogin               = "login"
+	TypeRefresh             = "refresh"
+	TypeRefreshReuse        = "refresh_reuse"
+	TypeLogout              = "logout"
+	TypeCodeSent            = "code_sent"
+	TypeProfileUpdate       = "profile_update"
+	TypeEmailChange         = "email_change"
+	TypeDataExport          = "data_export"
+	TypeSessionRevoke       = "session_revoke"
+	TypePasskeyAdd          = "passkey_add"
+	TypePasskeyDelete       = "passkey_delete"
+	TypeTotpEnable          = "totp_enable"
+	TypeTotpDisable         = "totp_disable"
+	TypePersonalTokenCreate = "personal_token_create"
+	TypePersonalTokenRevoke = "personal_token_revoke"
+)
+
+type Controller struct {
+	auditModel user.AuditStore
+	// SupportSubjects are allowed to read the audit trail of every user.
+	SupportSubjects []string
+	// TrustProxy reads the origin from the x-forwarded-for header (only set behind cloudfront).
+	TrustProxy bool
+}
+
+func New(audits user.AuditStore) *Controller {
+	return &Controller{
+		auditModel: audits,
+	}
+}
+
+// Record appends the event to the audit trail of the user. The request is optional,
+// if provided the event contains the user agent and origin of the request.
+func (c *Controller) Record(ctx context.Context, sub, kind, detail string, r connect.AnyRequest) error {
+	// v7 ids are monotonic, events of the same millisecond are listed in the order they were recorded.
+	id, err := uuid.NewV7()
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	event := &user.AuditEvent{
+		Id:        id.String(),
+		Type:      kind,
+		Detail:    detail,
+		CreatedAt: time.Now().UnixMilli(),
+		ExpiresAt: time.Now().Add(retention).Unix(),
+	}
+	if r != nil {
+		event.UserAgent = r.Header().Get("User-Agent")
+		event.Origin = c.Origin(r)
+	}
+	return c.auditModel.PutAuditEvent(ctx, sub, event)
+}
+
+// List returns the audit trail of the user. The caller must be the user itself or one of the support subjects.
+func (c *Controller) List(ctx context.Context, caller, sub string, since, until time.Time) ([]*user.AuditEvent, error) {
+	if caller != sub && !slices.Contains(c.SupportSubjects, caller) {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("audit trail of other users is restricted to support staff"))
+	}
+	return c.auditModel.ListAuditEvents(ctx, sub, since, until)
+}
+
+// Origin returns the origin address of the request as recorded in the audit trail and the sessions.
+func (c *Controller) Origin(r connect.AnyRequest) string {
+	return RequestOrigin(r, c.TrustProxy)
+}
+
+// RequestOrigin returns the address of the client (shared with the rate limiter).
+// If trustProxy is set, the last x-forwarded-for entry is used, it is the viewer address appended by the proxy (cloudfront).
+// Earlier entries are controlled by the client and therefore ignored; without proxy the header is ignored entirely.
+func RequestOrigin(r connect.AnyRequest, trustProxy bool) string {
+	if forwarded := r.Header().Get("X-Forwarded-For"); trustProxy && forwarded != "" {
+		addrs := strings.Split(forwarded, ",")
+		return strings.TrimSpace(addrs[len(addrs)-1])
+	}
+	host, _, err := net.SplitHostPort(r.Peer().Addr)
+	if err != nil {
+		return r.Peer().Addr
+	}
+	return host
+}
@@ -2,33 +2,59 @@
 package auth
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"net/url"
 	"strings"
 	"time"
 
 	"connectrpc.com/connect"
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/ses"
-	sestypes "github.com/aws/aws-sdk-go-v2/service/ses/types"
+	"github.com/megakuul/zen/internal/audit"
+	"github.com/megakuul/zen/internal/lockout"
+	"github.com/megakuul/zen/internal/mail"
+	"github.com/megakuul/zen/internal/model/ceremony"
 	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/token"
 	"github.com/megakuul/zen/pkg/api/v1/manager"
 )
 
+const (
+	codeTTL = 15 * time.Minute
+	// failed attempts are forgotten if no further attempt fails within this period.
+	attemptsTTL = 24 * time.Hour
+	// number of reads before a reservation that keeps losing against concurrent attempts is rejected.
+	reserveRetries = 5
+
+	linkCeremony = "MAGIC_LINK"
+	linkPurpose  = "login"
+)
+
 type Controller struct {
-	emailCtrl *email.Model
+	emailCtrl     email.Store
+	ceremonyModel ceremony.Store
+	tokenCtrl     *token.Controller
+	auditCtrl     *audit.Controller
 
-	sesClient *ses.Client
-	sender    string
+	mailer  mail.Mailer
+	linkUrl string
 }
 
-func New(emailCtrl *email.Model, sesClient *ses.Client, sender string) *Controller {
+// New creates the auth controller, linkUrl is the page that completes the login with the token of a sign-in link.
+func New(emailCtrl email.Store, ceremonies ceremony.Store, token *token.Controller, audit *audit.Controller, mailer mail.Mailer, linkUrl string) *Controller {
 	return &Controller{
-		emailCtrl: emailCtrl,
-		sesClient: sesClient,
-		sender:    sender,
+		emailCtrl:     emailCtrl,
+		ceremonyModel: ceremonies,
+		tokenCtrl:     token,
+		auditCtrl:     audit,
+		mailer:        mailer,
+		linkUrl:       linkUrl,
 	}
 }
 
@@ -49,9 +75,112 @@ func (c *Controller) Authenticate(ctx context.Context, verifier *manager.Verifie
 	}
 }
 
+// LinkState is the login state referenced by a sign-in link.
+type LinkState struct {
+	Email       string `json:"email"`
+	Code        string `json:"code"`
+	AutoRefresh bool   `json:"auto_refresh"`
+	// Device is the hash of the device secret, the link is only accepted together with the secret.
+	Device string `json:"device"`
+}
+
+// SendLink performs the email stage and additionally sends a sign-in link that is bound to the pending code.
+// Returns the device secret that must be presented together with the link token (see ConsumeLink) and its expiration.
+func (c *Controller) SendLink(ctx context.Context, emailAddr string, autoRefresh bool) (string, time.Time, error) {
+	expiresAt := time.Now().Add(codeTTL)
+	code, err := c.putCode(ctx, emailAddr, expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	device := rand.Text()
+	data, err := json.Marshal(&LinkState{
+		Email:       emailAddr,
+		Code:        code,
+		AutoRefresh: autoRefresh,
+		Device:      hashDevice(device),
+	})
+	if err != nil {
+		return "", time.Time{}, connect.NewError(connect.CodeInternal, err)
+	}
+	id := rand.Text()
+	err = c.ceremonyModel.PutCeremony(ctx, linkCeremony, id, &ceremony.Ceremony{
+		Data:      string(data),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	linkToken, err := c.tokenCtrl.IssueLink(ctx, linkPurpose, id, expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	link := fmt.Sprintf("%s?%s", c.linkUrl, url.Values{"token": {linkToken}}.Encode())
+	if err := c.sendCode(ctx, emailAddr, code, link); err != nil {
+		return "", time.Time{}, err
+	}
+	if err := c.recordCodeSent(ctx, emailAddr, "link"); err != nil {
+		return "", time.Time{}, err
+	}
+	return device, expiresAt, nil
+}
+
+// ConsumeLink verifies the link token and device secret and consumes the referenced code (single use).
+// A link opened on another device is rejected but stays valid for the requesting device.
+func (c *Controller) ConsumeLink(ctx context.Context, linkToken, device string) (*LinkState, error) {
+	id, err := c.tokenCtrl.VerifyLink(ctx, linkPurpose, linkToken)
+	if err != nil {
+		return nil, err
+	}
+	cer, found, err := c.ceremonyModel.ConsumeCeremony(ctx, linkCeremony, id)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("sign-in link was already used or has expired"))
+	}
+	state := &LinkState{}
+	if err := json.Unmarshal([]byte(cer.Data), state); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashDevice(device)), []byte(state.Device)) != 1 {
+		if putErr := c.ceremonyModel.PutCeremony(ctx, linkCeremony, id, cer); putErr != nil {
+			return nil, putErr
+		}
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("sign-in link must be opened on the device that requested it"))
+	}
+	if err := c.processCodeStage(ctx, state.Email, state.Code); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
 // processEmailStage is executed if the user provides the email but not the verification code.
 // -> generates a code, stores it in the database and sends it via email.
 func (c *Controller) processEmailStage(ctx context.Context, emailAddr string) error {
+	code, err := c.putCode(ctx, emailAddr, time.Now().Add(codeTTL))
+	if err != nil {
+		return err
+	}
+	if err := c.sendCode(ctx, emailAddr, code, ""); err != nil {
+		return err
+	}
+	return c.recordCodeSent(ctx, emailAddr, "code")
+}
+
+// recordCodeSent adds the sent mail to the audit trail of the user registered with the email.
+// Mails to unregistered emails (registration, email change) are not recorded.
+func (c *Controller) recordCodeSent(ctx context.Context, emailAddr, detail string) error {
+	sub, found, err := c.emailCtrl.RegisteredUser(ctx, emailAddr)
+	if err != nil {
+		return err
+	} else if !found {
+		return nil
+	}
+	return c.auditCtrl.Record(ctx, sub, audit.TypeCodeSent, detail, nil)
+}
+
+// putCode generates a new code and stores it (replaces the pending code).
+func (c *Controller) putCode(ctx context.Context, emailAddr string, expiresAt time.Time) (string, error) {
 	codeChars := "23456789ABCDEFGHJKLMNOPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
 	code := strings.Builder{}
 	for i := range 8 {
@@ -65,26 +194,34 @@ func (c *Controller) processEmailStage(ctx context.Context, emailAddr string) er
 
 	err := c.emailCtrl.PutCode(ctx, emailAddr, &email.Code{
 		Code:      code.String(),
-		ExpiresAt: time.Now().Add(15 * time.Minute).Unix(),
+		ExpiresAt: expiresAt.Unix(),
 	})
 	if err != nil {
-		return err
+		return "", err
+	}
+	return code.String(), nil
+}
+
+// sendCode sends the verification mail, the link is optional.
+func (c *Controller) sendCode(ctx context.Context, emailAddr, code, link string) error {
+	content := struct {
+		Code    string
+		Link    string
+		Minutes int
+	}{code, link, int(codeTTL.Minutes())}
+	text, html := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := codeTextTemplate.Execute(text, content); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	if err := codeHtmlTemplate.Execute(html, content); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
 	}
 
-	_, err = c.sesClient.SendEmail(ctx, &ses.SendEmailInput{
-		Destination: &sestypes.Destination{
-			ToAddresses: []string{emailAddr},
-		},
-		Source: aws.String(c.sender),
-		Message: &sestypes.Message{
-			Subject: &sestypes.Content{
-				Data: aws.String("Verification Code"), Charset: aws.String("UTF-8"),
-			},
-			Body: &sestypes.Body{Text: &sestypes.Content{
-				Data:    aws.String(code.String()),
-				Charset: aws.String("UTF-8"),
-			}},
-		},
+	err := c.mailer.Send(ctx, &mail.Message{
+		To:      []string{emailAddr},
+		Subject: "Verification Code",
+		Text:    text.String(),
+		Html:    html.String(),
 	})
 	if err != nil {
 		return connect.NewError(connect.CodeInternal, err)
@@ -92,17 +229,55 @@ func (c *Controller) processEmailStage(ctx context.Context, emailAddr string) er
 	return nil
 }
 
+func hashDevice(device string) string {
+	hash := sha256.Sum256([]byte(device))
+	return hex.EncodeToString(hash[:])
+}
+
 // processCodeStage is executed if the user provides the email and the verification.
-// -> reads the code from database and compares it.
+// -> consumes the code if it matches, failed attempts lock the email with an exponential backoff.
 func (c *Controller) processCodeStage(ctx context.Context, emailAddr, submittedCode string) error {
-	code, found, err := c.emailCtrl.GetCode(ctx, emailAddr)
+	attempts, err := c.reserveAttempt(ctx, emailAddr)
 	if err != nil {
-		return connect.NewError(connect.CodeInternal, err)
-	} else if !found {
-		return connect.NewError(connect.CodeNotFound, fmt.Errorf("code does not exist or has expired"))
+		return err
 	}
-	if code.Code != "" && code.Code != submittedCode {
-		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("incorrect code - permission denied"))
+	err = c.emailCtrl.ConsumeCode(ctx, emailAddr, submittedCode)
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodePermissionDenied && attempts.LockedUntil != 0 {
+			return lockout.Error(time.Unix(attempts.LockedUntil, 0))
+		}
+		return err
 	}
-	return nil
+	return c.emailCtrl.DeleteAttempts(ctx, emailAddr)
+}
+
+// reserveAttempt counts the attempt before the code is checked (compare-and-swap on the attempts), so parallel
+// guesses cannot pass the lockout. The attempt that reaches the limit locks the email in advance,
+// the lock is removed with the attempts if the code is correct.
+func (c *Controller) reserveAttempt(ctx context.Context, emailAddr string) (*email.Attempts, error) {
+	for range reserveRetries {
+		previous, found, err := c.emailCtrl.GetAttempts(ctx, emailAddr)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			previous = &email.Attempts{}
+		} else if previous.LockedUntil > time.Now().Unix() {
+			return nil, lockout.Error(time.Unix(previous.LockedUntil, 0))
+		}
+		attempts := &email.Attempts{
+			Failures:  previous.Failures + 1,
+			ExpiresAt: time.Now().Add(attemptsTTL).Unix(),
+		}
+		if lockedUntil, locked := lockout.Until(attempts.Failures); locked {
+			attempts.LockedUntil = lockedUntil.Unix()
+		}
+		err = c.emailCtrl.PutAttempts(ctx, emailAddr, previous.Failures, attempts)
+		if connect.CodeOf(err) == connect.CodeAborted {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return attempts, nil
+	}
+	return nil, connect.NewError(connect.CodeAborted, fmt.Errorf("too many concurrent attempts; try again"))
 }
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+var codeTextTemplate = texttemplate.Must(texttemplate.New("code").Parse(`Your verification code is: {{.Code}}
+{{if .Link}}
+Or sign in directly with this link (only works on the device that requested it):
+{{.Link}}
+{{end}}
+The code expires in {{.Minutes}} minutes. If you did not request it, you can ignore this email.
+`))
+
+var codeHtmlTemplate = htmltemplate.Must(htmltemplate.New("code").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<p>Your verification code is:</p>
+<p style="font-size: 24px; font-weight: bold; letter-spacing: 2px;">{{.Code}}</p>
+{{if .Link}}<p><a href="{{.Link}}">Sign in to Zen</a> (only works on the device that requested it)</p>
+{{end}}<p>The code expires in {{.Minutes}} minutes. If you did not request it, you can ignore this email.</p>
+</body>
+</html>
+`))
@@ -1,79 +1,62 @@
-// package captcha provides a store implementation for dchest/captcha.Store that uses s3.
+// package captcha provides the captcha controller and store implementations for dchest/captcha.Store.
 package captcha
 
 import (
 	"bytes"
-	"context"
-	"errors"
+	"crypto/rand"
 	"fmt"
-	"io"
-	"log/slog"
-	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	dchest "github.com/dchest/captcha"
 )
 
-type Store struct {
-	client  *s3.Client
-	logger  *slog.Logger
-	bucket  string
-	prefix  string
-	timeout time.Duration
+// Controller generates and verifies image captchas against its own store
+// (instead of the global store of dchest/captcha).
+type Controller struct {
+	store  dchest.Store
+	width  int
+	height int
 }
 
-func New(client *s3.Client, logger *slog.Logger, timeout time.Duration, bucket, prefix string) *Store {
-	return &Store{
-		client:  client,
-		logger:  logger,
-		bucket:  bucket,
-		prefix:  prefix,
-		timeout: timeout,
+func New(store dchest.Store) *Controller {
+	return &Controller{
+		store:  store,
+		width:  128,
+		height: 64,
 	}
 }
 
-func (s *Store) Get(id string, del bool) []byte {
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
-	defer cancel()
-	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(fmt.Sprint(s.prefix, id)),
-	})
-	if err != nil {
-		var nsk *types.NoSuchKey
-		if !errors.As(err, &nsk) {
-			s.logger.Error(fmt.Sprintf("failed to load captcha: %v", err))
-		}
-		return nil
-	}
-	digits, err := io.ReadAll(output.Body)
-	if err != nil {
-		s.logger.Error(fmt.Sprintf("failed to read captcha: %v", err))
-		return nil
-	}
-	if del {
-		_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
-			Bucket: aws.String(s.bucket),
-			Key:    aws.String(fmt.Sprint(s.prefix, id)),
-		})
-		if err != nil {
-			s.logger.Error(fmt.Sprintf("failed to delete captcha: %v", err))
-			return nil
-		}
+// Generate creates a new captcha and returns its id and png encoded image.
+func (c *Controller) Generate() (string, []byte, error) {
+	id := rand.Text()
+	digits := dchest.RandomDigits(dchest.DefaultLen)
+	c.store.Set(id, digits)
+
+	image := bytes.NewBuffer(nil)
+	if _, err := dchest.NewImage(id, digits, c.width, c.height).WriteTo(image); err != nil {
+		return "", nil, fmt.Errorf("failed to render captcha: %v", err)
 	}
-	return digits
+	return id, image.Bytes(), nil
 }
 
-func (s *Store) Set(id string, digits []byte) {
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
-	defer cancel()
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(fmt.Sprint(s.prefix, id)),
-		Body:   bytes.NewReader(digits),
-	})
-	if err != nil {
-		s.logger.Error(fmt.Sprintf("failed to insert captcha: %v", err))
+// Verify checks the submitted digits (spaces and commas are ignored) and removes the captcha,
+// so every captcha can only be verified once.
+func (c *Controller) Verify(id, submitted string) bool {
+	digits := make([]byte, 0, len(submitted))
+	for _, char := range []byte(submitted) {
+		switch {
+		case '0' <= char && char <= '9':
+			digits = append(digits, char-'0')
+		case char == ' ' || char == ',':
+		default:
+			return false
+		}
+	}
+	if len(digits) == 0 {
+		return false
+	}
+	expected := c.store.Get(id, true)
+	if expected == nil {
+		return false
 	}
+	return bytes.Equal(digits, expected)
 }
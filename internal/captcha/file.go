@@ -0,0 +1,111 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileStore implements the dchest/captcha.Store on a local directory.
+// Captchas older than the expiration are ignored and removed by the sweeper.
+type FileStore struct {
+	logger     *slog.Logger
+	dir        string
+	expiration time.Duration
+}
+
+func NewFile(logger *slog.Logger, dir string, expiration time.Duration) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create captcha directory: %v", err)
+	}
+	return &FileStore{
+		logger:     logger,
+		dir:        dir,
+		expiration: expiration,
+	}, nil
+}
+
+func (s *FileStore) path(id string) string {
+	// ids are generated by the controller, but Get is called with user input.
+	return filepath.Join(s.dir, filepath.Base(id))
+}
+
+func (s *FileStore) Get(id string, del bool) []byte {
+	path := s.path(id)
+	info, err := os.Stat(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			s.logger.Error(fmt.Sprintf("failed to load captcha: %v", err))
+		}
+		return nil
+	} else if time.Since(info.ModTime()) > s.expiration {
+		return nil
+	}
+	digits, err := os.ReadFile(path)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to read captcha: %v", err))
+		return nil
+	}
+	if del {
+		if err := os.Remove(path); err != nil {
+			// another request consumed the captcha concurrently.
+			if !errors.Is(err, fs.ErrNotExist) {
+				s.logger.Error(fmt.Sprintf("failed to delete captcha: %v", err))
+			}
+			return nil
+		}
+	}
+	return digits
+}
+
+func (s *FileStore) Set(id string, digits []byte) {
+	tmp, err := os.CreateTemp(s.dir, ".captcha-*")
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to insert captcha: %v", err))
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(digits)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path(id))
+	}
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to insert captcha: %v", err))
+	}
+}
+
+// Sweep removes expired captchas in the specified interval until the context is cancelled.
+func (s *FileStore) Sweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		entries, err := os.ReadDir(s.dir)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to list captchas: %v", err))
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || time.Since(info.ModTime()) <= s.expiration {
+				continue
+			}
+			// leftover temporary files are swept too.
+			if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				s.logger.Warn(fmt.Sprintf("failed to remove expired captcha '%s': %v", entry.Name(), err))
+			}
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package captcha
+
+import (
+	"time"
+
+	dchest "github.com/dchest/captcha"
+)
+
+// NewMemory creates an in-memory store that expires captchas after the expiration.
+// The store is process local, therefore it only works if captchas are generated and verified by the same instance.
+func NewMemory(expiration time.Duration) dchest.Store {
+	return dchest.NewMemoryStore(dchest.CollectNum, expiration)
+}
@@ -0,0 +1,79 @@
+package captcha
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Store implements the dchest/captcha.Store on top of s3.
+type S3Store struct {
+	client  *s3.Client
+	logger  *slog.Logger
+	bucket  string
+	prefix  string
+	timeout time.Duration
+}
+
+func NewS3(client *s3.Client, logger *slog.Logger, timeout time.Duration, bucket, prefix string) *S3Store {
+	return &S3Store{
+		client:  client,
+		logger:  logger,
+		bucket:  bucket,
+		prefix:  prefix,
+		timeout: timeout,
+	}
+}
+
+func (s *S3Store) Get(id string, del bool) []byte {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(fmt.Sprint(s.prefix, id)),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if !errors.As(err, &nsk) {
+			s.logger.Error(fmt.Sprintf("failed to load captcha: %v", err))
+		}
+		return nil
+	}
+	digits, err := io.ReadAll(output.Body)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to read captcha: %v", err))
+		return nil
+	}
+	if del {
+		_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(fmt.Sprint(s.prefix, id)),
+		})
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to delete captcha: %v", err))
+			return nil
+		}
+	}
+	return digits
+}
+
+func (s *S3Store) Set(id string, digits []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(fmt.Sprint(s.prefix, id)),
+		Body:   bytes.NewReader(digits),
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to insert captcha: %v", err))
+	}
+}
@@ -24,6 +24,7 @@ type Operator struct {
 	certificateArn   string
 	deleteProtection bool
 	buildCtxPath     string
+	tokenKeys        []kms.Key
 }
 
 type Option func(*Operator)
@@ -36,6 +37,7 @@ func New(region string, opts ...Option) *Operator {
 		certificateArn:   "",
 		deleteProtection: false,
 		buildCtxPath:     ".",
+		tokenKeys:        []kms.Key{{Name: "kms", Active: true}},
 	}
 	for _, opt := range opts {
 		opt(operator)
@@ -78,6 +80,18 @@ func WithDeleteProtection(enable bool) Option {
 	}
 }
 
+// WithTokenKeys defines the kms keys (by resource name) used for the access tokens.
+// The active key signs the tokens, the verify keys are accepted and published during a key rotation.
+// Removing a key from the list schedules its deletion. Defaults to a single key named "kms".
+func WithTokenKeys(active string, verify []string) Option {
+	return func(o *Operator) {
+		o.tokenKeys = []kms.Key{{Name: active, Active: true}}
+		for _, name := range verify {
+			o.tokenKeys = append(o.tokenKeys, kms.Key{Name: name, Active: false})
+		}
+	}
+}
+
 func (o *Operator) Deploy(ctx *pulumi.Context) error {
 	if len(o.domains) < 1 {
 		return fmt.Errorf("expected at least one domain")
@@ -124,6 +138,7 @@ func (o *Operator) Deploy(ctx *pulumi.Context) error {
 	}
 	kmsDeploy, err := kms.Deploy(ctx, &kms.DeployInput{
 		Region: o.region,
+		Keys:   o.tokenKeys,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to deploy kms: %v", err)
@@ -133,6 +148,8 @@ func (o *Operator) Deploy(ctx *pulumi.Context) error {
 		Handler:         leaderboardBuild.Handler,
 		BucketPolicyArn: storageDeploy.BucketPolicyArn,
 		BucketName:      storageDeploy.BucketName,
+		TableName:       tableDeploy.TableName,
+		TablePolicyArn:  tableDeploy.TablePolicyArn,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to deploy leaderboard system: %v", err)
@@ -144,6 +161,7 @@ func (o *Operator) Deploy(ctx *pulumi.Context) error {
 		TableName:      tableDeploy.TableName,
 		TablePolicyArn: tableDeploy.TablePolicyArn,
 		KmsName:        kmsDeploy.KmsName,
+		KmsVerifyNames: kmsDeploy.KmsVerifyNames,
 		KmsPolicyArn:   kmsDeploy.KmsPolicyArn,
 		QueueName:      leaderboardDeploy.QueueName,
 		QueuePolicyArn: leaderboardDeploy.QueuePolicyArn,
@@ -167,11 +185,14 @@ func (o *Operator) Deploy(ctx *pulumi.Context) error {
 		TableName:       tableDeploy.TableName,
 		TablePolicyArn:  tableDeploy.TablePolicyArn,
 		KmsName:         kmsDeploy.KmsName,
+		KmsVerifyNames:  kmsDeploy.KmsVerifyNames,
 		KmsPolicyArn:    kmsDeploy.KmsPolicyArn,
 		BucketName:      storageDeploy.BucketName,
 		BucketPolicyArn: storageDeploy.BucketPolicyArn,
 		EmailName:       emailDeploy.EmailName,
 		EmailPolicyArn:  emailDeploy.EmailPolicyArn,
+		QueueName:       leaderboardDeploy.QueueName,
+		QueuePolicyArn:  leaderboardDeploy.QueuePolicyArn,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to deploy manager: %v", err)
@@ -202,5 +223,6 @@ func (o *Operator) Deploy(ctx *pulumi.Context) error {
 	}
 
 	ctx.Export("ENDPOINT", proxyDeploy.ProxyDomain)
+	ctx.Export("TABLE", tableDeploy.TableName)
 	return nil
 }
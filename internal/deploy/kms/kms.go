@@ -1,52 +1,96 @@
 package kms
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/pulumi/pulumi-aws/sdk/v7/go/aws/iam"
 	"github.com/pulumi/pulumi-aws/sdk/v7/go/aws/kms"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
+// Key is a token signing key, the name is used as resource name.
+// Only the active key signs tokens, the others are used to verify them (during a key rotation).
+type Key struct {
+	Name   string
+	Active bool
+}
+
 type DeployInput struct {
 	Region string
+	Keys   []Key
 }
 
 type DeployOutput struct {
-	KmsName      pulumi.StringOutput
-	KmsPolicyArn pulumi.StringOutput
+	KmsName pulumi.StringOutput
+	// KmsVerifyNames contains the comma separated ids of the inactive keys.
+	KmsVerifyNames pulumi.StringOutput
+	KmsPolicyArn   pulumi.StringOutput
 }
 
 func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
-	kmsKey, err := kms.NewKey(ctx, "kms", &kms.KeyArgs{
-		Region:                pulumi.String(input.Region),
-		Description:           pulumi.String("key used as jwt backend for access tokens"),
-		KeyUsage:              pulumi.String("SIGN_VERIFY"),
-		CustomerMasterKeySpec: pulumi.String("ECC_NIST_P256"),
-	})
-	if err != nil {
-		return nil, err
+	var activeKey *kms.Key
+	verifyKeyIds := []any{}
+	keyArns := []any{}
+	for _, key := range input.Keys {
+		kmsKey, err := kms.NewKey(ctx, key.Name, &kms.KeyArgs{
+			Region:                pulumi.String(input.Region),
+			Description:           pulumi.String("key used as jwt backend for access tokens"),
+			KeyUsage:              pulumi.String("SIGN_VERIFY"),
+			CustomerMasterKeySpec: pulumi.String("ECC_NIST_P256"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if key.Active {
+			if activeKey != nil {
+				return nil, fmt.Errorf("expected exactly one active key; got multiple")
+			}
+			activeKey = kmsKey
+		} else {
+			verifyKeyIds = append(verifyKeyIds, kmsKey.KeyId)
+		}
+		keyArns = append(keyArns, kmsKey.Arn)
+	}
+	if activeKey == nil {
+		return nil, fmt.Errorf("expected exactly one active key; got none")
 	}
 
 	kmsPolicy, err := iam.NewPolicy(ctx, "kms", &iam.PolicyArgs{
 		Name: pulumi.String("zen-kms-rw"),
-		Policy: pulumi.Sprintf(`{
-			"Version": "2012-10-17",
-			"Statement": [{
-				"Effect": "Allow",
-				"Action": [
-					"kms:Sign",
-					"kms:Verify",
-					"kms:GetPublicKey"
-				],
-				"Resource": "%s"
-			}]
-		}`, kmsKey.Arn),
+		Policy: pulumi.All(keyArns...).ApplyT(func(args []any) (string, error) {
+			rawArns, err := json.Marshal(args)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf(`{
+				"Version": "2012-10-17",
+				"Statement": [{
+					"Effect": "Allow",
+					"Action": [
+						"kms:Sign",
+						"kms:Verify",
+						"kms:GetPublicKey"
+					],
+					"Resource": %s
+				}]
+			}`, rawArns), nil
+		}).(pulumi.StringOutput),
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &DeployOutput{
-		KmsName:      kmsKey.KeyId,
+		KmsName: activeKey.KeyId,
+		KmsVerifyNames: pulumi.All(verifyKeyIds...).ApplyT(func(args []any) string {
+			ids := []string{}
+			for _, id := range args {
+				ids = append(ids, id.(string))
+			}
+			return strings.Join(ids, ",")
+		}).(pulumi.StringOutput),
 		KmsPolicyArn: kmsPolicy.Arn,
 	}, nil
 }
@@ -61,6 +61,8 @@ type DeployInput struct {
 	Handler         pulumi.ArchiveOutput
 	BucketName      pulumi.StringOutput
 	BucketPolicyArn pulumi.StringOutput
+	TableName       pulumi.StringOutput
+	TablePolicyArn  pulumi.StringOutput
 }
 
 type DeployOutput struct {
@@ -167,6 +169,7 @@ func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
 		ManagedPolicyArns: pulumi.ToStringArrayOutput([]pulumi.StringOutput{
 			leaderboardLogPolicy.Arn,
 			input.BucketPolicyArn,
+			input.TablePolicyArn,
 			queuePullPolicy.Arn,
 		}),
 	})
@@ -194,6 +197,7 @@ func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
 				"LEADERBOARD_QUEUE":         queue.Name,
 				"LEADERBOARD_BUCKET":        input.BucketName,
 				"LEADERBOARD_BUCKET_PREFIX": pulumi.Sprintf("leaderboard/"),
+				"TABLE":                     input.TableName,
 			}),
 		},
 	})
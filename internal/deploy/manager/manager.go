@@ -65,9 +65,12 @@ type DeployInput struct {
 	TableName       pulumi.StringOutput
 	TablePolicyArn  pulumi.StringOutput
 	KmsName         pulumi.StringOutput
+	KmsVerifyNames  pulumi.StringOutput
 	KmsPolicyArn    pulumi.StringOutput
 	EmailName       pulumi.StringOutput
 	EmailPolicyArn  pulumi.StringOutput
+	QueueName       pulumi.StringOutput
+	QueuePolicyArn  pulumi.StringOutput
 }
 
 type DeployOutput struct {
@@ -124,6 +127,7 @@ func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
 			input.TablePolicyArn,
 			input.BucketPolicyArn,
 			input.EmailPolicyArn,
+			input.QueuePolicyArn,
 		}),
 	})
 	if err != nil {
@@ -146,12 +150,21 @@ func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
 		Code: input.Handler,
 		Environment: &lambda.FunctionEnvironmentArgs{
 			Variables: pulumi.ToStringMapOutput(map[string]pulumi.StringOutput{
-				"TABLE":                 input.TableName,
-				"TOKEN_ISSUER":          pulumi.Sprintf(input.Issuer),
-				"TOKEN_KMS_KEY_ID":      input.KmsName,
-				"AUTH_MAIL_SENDER":      input.EmailName,
-				"CAPTCHA_BUCKET":        input.BucketName,
-				"CAPTCHA_BUCKET_PREFIX": pulumi.Sprintf("captcha/"),
+				"TABLE":                     input.TableName,
+				"LEADERBOARD_QUEUE":         input.QueueName,
+				"RATELIMIT_TRUST_PROXY":     pulumi.Sprintf("true"), // requests are forwarded by the cdn
+				"TOKEN_ISSUER":              pulumi.Sprintf(input.Issuer),
+				"TOKEN_KMS_KEY_ID":          input.KmsName,
+				"TOKEN_KMS_VERIFY_KEY_IDS":  input.KmsVerifyNames,
+				"AUTH_MAIL_SENDER":          input.EmailName,
+				"CAPTCHA_BUCKET":            input.BucketName,
+				"CAPTCHA_BUCKET_PREFIX":     pulumi.Sprintf("captcha/"),
+				"LEADERBOARD_BUCKET":        input.BucketName,
+				"LEADERBOARD_BUCKET_PREFIX": pulumi.Sprintf("leaderboard/"),
+				"PASSKEY_RP_ID":             pulumi.Sprintf(input.Domain),
+				"PASSKEY_RP_ORIGINS":        pulumi.Sprintf("https://%s", input.Domain),
+				"OIDC_REDIRECT_URL":         pulumi.Sprintf("https://%s/login/oidc", input.Domain),
+				"LOGIN_LINK_URL":            pulumi.Sprintf("https://%s/login/link", input.Domain),
 			}),
 		},
 	})
@@ -288,6 +288,16 @@ func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
 				CachePolicyId:         apiCachePolicy.ID(),
 				OriginRequestPolicyId: apiOriginPolicy.ID(),
 			},
+			cloudfront.DistributionOrderedCacheBehaviorArgs{
+				PathPattern:           pulumi.String("/.well-known/*"),
+				AllowedMethods:        pulumi.ToStringArray([]string{"GET", "HEAD", "OPTIONS"}),
+				CachedMethods:         pulumi.ToStringArray([]string{"GET", "HEAD"}),
+				Compress:              pulumi.BoolPtr(true),
+				TargetOriginId:        pulumi.String("manager-api"),
+				ViewerProtocolPolicy:  pulumi.String("redirect-to-https"),
+				CachePolicyId:         apiCachePolicy.ID(),
+				OriginRequestPolicyId: apiOriginPolicy.ID(),
+			},
 		},
 		DefaultRootObject: pulumi.String("index.html"),
 		IsIpv6Enabled:     pulumi.BoolPtr(true),
@@ -64,6 +64,7 @@ type DeployInput struct {
 	QueueName      pulumi.StringOutput
 	QueuePolicyArn pulumi.StringOutput
 	KmsName        pulumi.StringOutput
+	KmsVerifyNames pulumi.StringOutput
 	KmsPolicyArn   pulumi.StringOutput
 }
 
@@ -142,11 +143,13 @@ func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
 		Code: input.Handler,
 		Environment: &lambda.FunctionEnvironmentArgs{
 			Variables: pulumi.ToStringMapOutput(map[string]pulumi.StringOutput{
-				"TABLE":             input.TableName,
-				"TOKEN_ISSUER":      pulumi.Sprintf(input.Issuer),
-				"TOKEN_KMS_KEY_ID":  input.KmsName,
-				"LEADERBOARD_QUEUE": input.QueueName,
-				"RATING_ANCHOR":     pulumi.Sprintf("10m"),
+				"TABLE":                    input.TableName,
+				"RATELIMIT_TRUST_PROXY":    pulumi.Sprintf("true"), // requests are forwarded by the cdn
+				"TOKEN_ISSUER":             pulumi.Sprintf(input.Issuer),
+				"TOKEN_KMS_KEY_ID":         input.KmsName,
+				"TOKEN_KMS_VERIFY_KEY_IDS": input.KmsVerifyNames,
+				"LEADERBOARD_QUEUE":        input.QueueName,
+				"RATING_ANCHOR":            pulumi.Sprintf("10m"),
 			}),
 		},
 	})
@@ -43,6 +43,7 @@ func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
 
 	// for simplicity there is only one rw policy
 	// (there is no use for a seperate readonly policy right now)
+	// transactions are authorized with the actions of their items, the reconcile action scans the table.
 	tablePolicy, err := iam.NewPolicy(ctx, "table", &iam.PolicyArgs{
 		Name: pulumi.String("zen-table-rw"),
 		Policy: pulumi.Sprintf(`{
@@ -54,7 +55,10 @@ func Deploy(ctx *pulumi.Context, input *DeployInput) (*DeployOutput, error) {
 					"dynamodb:Query",
 					"dynamodb:PutItem",
 					"dynamodb:UpdateItem",
-					"dynamodb:DeleteItem"
+					"dynamodb:DeleteItem",
+					"dynamodb:BatchWriteItem",
+					"dynamodb:ConditionCheckItem",
+					"dynamodb:Scan"
 				],
 				"Resource": "%s"
 			}]
@@ -0,0 +1,264 @@
+// package export builds the personal data archive (takeout) of a user.
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/leaderboard"
+	"github.com/megakuul/zen/internal/model/user"
+)
+
+// eventHorizon is the upper bound of the exported events (events can be planned in the future).
+const eventHorizon = 100 * 365 * 24 * time.Hour
+
+type Controller struct {
+	profileModel user.ProfileStore
+	eventModel   user.EventStore
+	boardModel   leaderboard.BoardStore
+}
+
+func New(profiles user.ProfileStore, events user.EventStore, boards leaderboard.BoardStore) *Controller {
+	return &Controller{
+		profileModel: profiles,
+		eventModel:   events,
+		boardModel:   boards,
+	}
+}
+
+type Profile struct {
+	Id          string  `json:"id"`
+	Email       string  `json:"email"`
+	Username    string  `json:"username"`
+	Description string  `json:"description"`
+	Leaderboard bool    `json:"leaderboard"`
+	CreatedAt   int64   `json:"created_at"`
+	Score       float64 `json:"score"`
+	Streak      int64   `json:"streak"`
+	MaxStreak   int64   `json:"max_streak"`
+}
+
+type Event struct {
+	Id              string  `json:"id"`
+	Type            int64   `json:"type"`
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	MusicUrl        string  `json:"music_url"`
+	StartTime       int64   `json:"start_time"`
+	StopTime        int64   `json:"stop_time"`
+	TimerStartTime  int64   `json:"timer_start_time"`
+	TimerStopTime   int64   `json:"timer_stop_time"`
+	RatingChange    float64 `json:"rating_change"`
+	RatingAlgorithm string  `json:"rating_algorithm"`
+	Immutable       bool    `json:"immutable"`
+}
+
+// BoardEntry is the entry of the user on one weekly board.
+type BoardEntry struct {
+	Year     string        `json:"year"`
+	Week     string        `json:"week"`
+	Username string        `json:"username"`
+	Streak   int64         `json:"streak"`
+	Ratings  []BoardRating `json:"ratings"`
+}
+
+type BoardRating struct {
+	Time         int64   `json:"time"`
+	RatingChange float64 `json:"rating_change"`
+}
+
+// Archive collects the personal data of the user and returns it as zip archive.
+// Every dataset is contained as json and as csv file (timestamps are unix seconds).
+func (c *Controller) Archive(ctx context.Context, sub, email string) ([]byte, error) {
+	profile, found, err := c.profileModel.GetProfile(ctx, sub)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("user not found"))
+	}
+	events, err := c.events(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := c.boardEntries(ctx, sub, time.Unix(profile.CreatedAt, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &bytes.Buffer{}
+	w := zip.NewWriter(archive)
+	files := []struct {
+		name string
+		fn   func() ([]byte, error)
+	}{
+		{"profile.json", func() ([]byte, error) {
+			return json.MarshalIndent(&Profile{
+				Id:          sub,
+				Email:       email,
+				Username:    profile.Username,
+				Description: profile.Description,
+				Leaderboard: profile.Leaderboard,
+				CreatedAt:   profile.CreatedAt,
+				Score:       profile.Score,
+				Streak:      profile.Streak,
+				MaxStreak:   profile.MaxStreak,
+			}, "", "  ")
+		}},
+		{"profile.csv", func() ([]byte, error) {
+			return encodeCsv([]string{"id", "email", "username", "description", "leaderboard", "created_at", "score", "streak", "max_streak"},
+				[][]string{{
+					sub, email, profile.Username, profile.Description, strconv.FormatBool(profile.Leaderboard),
+					strconv.FormatInt(profile.CreatedAt, 10), formatFloat(profile.Score),
+					strconv.FormatInt(profile.Streak, 10), strconv.FormatInt(profile.MaxStreak, 10),
+				}})
+		}},
+		{"events.json", func() ([]byte, error) {
+			return json.MarshalIndent(events, "", "  ")
+		}},
+		{"events.csv", func() ([]byte, error) {
+			records := [][]string{}
+			for _, event := range events {
+				records = append(records, []string{
+					event.Id, strconv.FormatInt(event.Type, 10), event.Name, event.Description, event.MusicUrl,
+					strconv.FormatInt(event.StartTime, 10), strconv.FormatInt(event.StopTime, 10),
+					strconv.FormatInt(event.TimerStartTime, 10), strconv.FormatInt(event.TimerStopTime, 10),
+					formatFloat(event.RatingChange), event.RatingAlgorithm, strconv.FormatBool(event.Immutable),
+				})
+			}
+			return encodeCsv([]string{
+				"id", "type", "name", "description", "music_url", "start_time", "stop_time",
+				"timer_start_time", "timer_stop_time", "rating_change", "rating_algorithm", "immutable",
+			}, records)
+		}},
+		{"leaderboard.json", func() ([]byte, error) {
+			return json.MarshalIndent(entries, "", "  ")
+		}},
+		{"leaderboard.csv", func() ([]byte, error) {
+			records := [][]string{}
+			for _, entry := range entries {
+				for _, rating := range entry.Ratings {
+					records = append(records, []string{
+						entry.Year, entry.Week, entry.Username, strconv.FormatInt(entry.Streak, 10),
+						strconv.FormatInt(rating.Time, 10), formatFloat(rating.RatingChange),
+					})
+				}
+			}
+			return encodeCsv([]string{"year", "week", "username", "streak", "time", "rating_change"}, records)
+		}},
+	}
+	for _, file := range files {
+		content, err := file.fn()
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		fw, err := w.Create(file.name)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		if _, err := fw.Write(content); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return archive.Bytes(), nil
+}
+
+// events pages through all events of the user (the store returns a limited number of events per call).
+func (c *Controller) events(ctx context.Context, sub string) ([]*Event, error) {
+	events := []*Event{}
+	since, until := time.Unix(0, 0), time.Now().Add(eventHorizon)
+	for {
+		page, err := c.eventModel.ListEvents(ctx, sub, since, until)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range page {
+			events = append(events, &Event{
+				Id:              strconv.FormatInt(event.StartTime, 10),
+				Type:            event.Type,
+				Name:            event.Name,
+				Description:     event.Description,
+				MusicUrl:        event.MusicUrl,
+				StartTime:       event.StartTime,
+				StopTime:        event.StopTime,
+				TimerStartTime:  event.TimerStartTime,
+				TimerStopTime:   event.TimerStopTime,
+				RatingChange:    event.RatingChange,
+				RatingAlgorithm: event.RatingAlgorithm,
+				Immutable:       event.Immutable,
+			})
+		}
+		if len(page) < 1 {
+			return events, nil
+		}
+		next := time.Unix(page[len(page)-1].StartTime+1, 0)
+		if !next.After(since) {
+			return events, nil
+		}
+		since = next
+	}
+}
+
+// boardEntries collects the entries of the user from all weekly boards since the account was created.
+func (c *Controller) boardEntries(ctx context.Context, sub string, since time.Time) ([]*BoardEntry, error) {
+	entries := []*BoardEntry{}
+	year, week := time.Now().ISOWeek()
+	for date := since; ; date = date.AddDate(0, 0, 7) {
+		dateYear, dateWeek := date.ISOWeek()
+		if dateYear > year || dateYear == year && dateWeek > week {
+			break
+		}
+		board, found, err := c.boardModel.GetBoard(ctx, date)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			continue
+		}
+		boardEntry, ok := board.Entries[sub]
+		if !ok {
+			continue
+		}
+		entry := &BoardEntry{
+			Year:     board.Year,
+			Week:     board.Week,
+			Username: boardEntry.Username,
+			Streak:   boardEntry.Streak,
+			Ratings:  []BoardRating{},
+		}
+		for t, change := range boardEntry.Rating {
+			entry.Ratings = append(entry.Ratings, BoardRating{Time: t, RatingChange: change})
+		}
+		slices.SortFunc(entry.Ratings, func(a, b BoardRating) int {
+			return cmp.Compare(a.Time, b.Time)
+		})
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func encodeCsv(header []string, records [][]string) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	w := csv.NewWriter(buffer)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 
@@ -27,6 +28,8 @@ func (r *Requestor) Request(ctx context.Context, e events.LambdaFunctionURLReque
 	for k, v := range e.Headers {
 		request.Header.Add(k, v)
 	}
+	// the port of the source is not exposed by the function url, port 0 keeps the address parsable.
+	request.RemoteAddr = net.JoinHostPort(e.RequestContext.HTTP.SourceIP, "0")
 	return request, nil
 }
 
@@ -0,0 +1,161 @@
+// package httpserver provides a standalone net/http server used to run the connect handlers outside of lambda.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Server wraps a http.Server with tls, h2c, cors and graceful shutdown handling.
+type Server struct {
+	logger          *slog.Logger
+	addr            string
+	tlsCert         string
+	tlsKey          string
+	corsOrigins     []string
+	shutdownTimeout time.Duration
+	handler         http.Handler
+}
+
+type Option func(*Server)
+
+func New(logger *slog.Logger, addr string, handler http.Handler, opts ...Option) *Server {
+	server := &Server{
+		logger:          logger,
+		addr:            addr,
+		tlsCert:         "",
+		tlsKey:          "",
+		corsOrigins:     []string{},
+		shutdownTimeout: 15 * time.Second,
+		handler:         handler,
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
+}
+
+// WithTLS serves https with the provided certificate and key files instead of plaintext h2c.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.tlsCert = certFile
+		s.tlsKey = keyFile
+	}
+}
+
+// WithCors allows cross origin requests from the specified origins ("*" allows all origins, but without credentials).
+// If no origin is specified, no cors headers are emitted at all.
+func WithCors(origins []string) Option {
+	return func(s *Server) {
+		s.corsOrigins = origins
+	}
+}
+
+// WithShutdownTimeout defines how long in-flight requests are awaited after the server context is cancelled.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// Serve starts the server and blocks until the context is cancelled or the server fails.
+// On cancellation the server is shut down gracefully.
+func (s *Server) Serve(ctx context.Context) error {
+	protocols := &http.Protocols{}
+	protocols.SetHTTP1(true)
+	if s.tlsCert != "" {
+		protocols.SetHTTP2(true)
+	} else {
+		protocols.SetUnencryptedHTTP2(true) // h2c is required for grpc clients without tls
+	}
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.cors(s.handler),
+		Protocols:         protocols,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		s.logger.Info(fmt.Sprintf("listening on %s", s.addr))
+		var err error
+		if s.tlsCert != "" {
+			err = server.ListenAndServeTLS(s.tlsCert, s.tlsKey)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("server failure: %v", err)
+	case <-ctx.Done():
+	}
+	s.logger.Info("shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shutdown server gracefully: %v", err)
+	}
+	return <-errs
+}
+
+var (
+	corsMethods = []string{http.MethodGet, http.MethodPost}
+	corsHeaders = []string{
+		"Authorization", "Content-Type", "Accept-Encoding", "Content-Encoding",
+		"Connect-Protocol-Version", "Connect-Timeout-Ms", "Connect-Accept-Encoding", "Connect-Content-Encoding",
+		"Grpc-Timeout", "X-Grpc-Web", "X-User-Agent",
+	}
+	corsExposedHeaders = []string{
+		"Content-Encoding", "Connect-Content-Encoding",
+		"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin",
+		// back-off of rate limited requests.
+		"Retry-After",
+	}
+)
+
+// cors wraps the handler with the headers required by browser based connect clients.
+// Credentials are allowed for the explicitly listed origins as the refresh_token is transported via cookie,
+// the wildcard origin "*" never allows credentials (any website could otherwise use the session of the user).
+func (s *Server) cors(next http.Handler) http.Handler {
+	if len(s.corsOrigins) < 1 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		if slices.Contains(s.corsOrigins, origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		} else if slices.Contains(s.corsOrigins, "*") {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(corsExposedHeaders, ", "))
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(corsMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(corsHeaders, ", "))
+			w.Header().Set("Access-Control-Max-Age", "7200")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,45 @@
+// package interceptor provides the connect interceptors shared by the services.
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/token"
+	"github.com/megakuul/zen/pkg/api/v1/manager/authentication/authenticationconnect"
+	"github.com/megakuul/zen/pkg/api/v1/manager/management/managementconnect"
+)
+
+// publicProcedures are served without access token, they authenticate the caller by other means
+// (verifier, refresh cookie, passkey, ...) or are used to acquire the access token in the first place.
+var publicProcedures = map[string]bool{
+	managementconnect.ManagementServiceRegisterProcedure:                   true,
+	authenticationconnect.AuthenticationServiceLoginProcedure:              true,
+	authenticationconnect.AuthenticationServiceLogoutProcedure:             true,
+	authenticationconnect.AuthenticationServiceBeginPasskeyLoginProcedure:  true,
+	authenticationconnect.AuthenticationServiceFinishPasskeyLoginProcedure: true,
+	authenticationconnect.AuthenticationServiceVerifyTotpProcedure:         true,
+	authenticationconnect.AuthenticationServiceBeginOidcLoginProcedure:     true,
+	authenticationconnect.AuthenticationServiceFinishOidcLoginProcedure:    true,
+}
+
+// NewAuth creates an interceptor that verifies the bearer token of all non-public procedures.
+// The claims are attached to the context of the request (see token.FromContext), refresh tokens are rejected.
+func NewAuth(tokenCtrl *token.Controller) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if req.Spec().IsClient || publicProcedures[req.Spec().Procedure] {
+				return next(ctx, req)
+			}
+			claims, err := tokenCtrl.Verify(ctx, strings.TrimPrefix(req.Header().Get("Authorization"), "Bearer "))
+			if err != nil {
+				return nil, connect.NewError(connect.CodeUnauthenticated, err)
+			} else if claims.Refresh {
+				return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("invalid token; expected access_token"))
+			}
+			return next(token.NewContext(ctx, claims), req)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/audit"
+	"github.com/megakuul/zen/internal/model/ratelimit"
+	"github.com/megakuul/zen/internal/token"
+	"github.com/megakuul/zen/pkg/api/v1/manager/authentication/authenticationconnect"
+	"github.com/megakuul/zen/pkg/api/v1/manager/management/managementconnect"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/timing/timingconnect"
+)
+
+// Limits configures the rate limits of a procedure, every limit is enforced with a separate bucket (nil disables it).
+type Limits struct {
+	// Ip limits the requests per client address.
+	Ip *ratelimit.Limit
+	// Subject limits the requests per authenticated user, it requires the auth interceptor to run first.
+	Subject *ratelimit.Limit
+}
+
+// DefaultLimits protects the procedures that trigger expensive side effects (captchas, mails, rating updates, exports).
+// Procedures without limits are not rate limited.
+var DefaultLimits = map[string]Limits{
+	managementconnect.ManagementServiceRegisterProcedure: {
+		Ip: &ratelimit.Limit{Burst: 10, Interval: time.Minute},
+	},
+	authenticationconnect.AuthenticationServiceLoginProcedure: {
+		Ip: &ratelimit.Limit{Burst: 30, Interval: 5 * time.Second},
+	},
+	managementconnect.ManagementServiceChangeEmailProcedure: {
+		Subject: &ratelimit.Limit{Burst: 5, Interval: time.Minute},
+	},
+	managementconnect.ManagementServiceDeleteProcedure: {
+		Subject: &ratelimit.Limit{Burst: 5, Interval: time.Minute},
+	},
+	managementconnect.ManagementServiceExportProcedure: {
+		Subject: &ratelimit.Limit{Burst: 3, Interval: 10 * time.Minute},
+	},
+	timingconnect.TimingServiceStartProcedure: {
+		Subject: &ratelimit.Limit{Burst: 10, Interval: 6 * time.Second},
+	},
+	timingconnect.TimingServiceStopProcedure: {
+		Subject: &ratelimit.Limit{Burst: 10, Interval: 6 * time.Second},
+	},
+}
+
+// NewRateLimit creates an interceptor that enforces the limits with token buckets. Limited requests are rejected
+// with a ResourceExhausted error, the Retry-After metadata contains the seconds until the next request is accepted.
+// If trustProxy is set, the client address is read from the x-forwarded-for header appended by the proxy (cloudfront).
+func NewRateLimit(store ratelimit.Store, limits map[string]Limits, trustProxy bool) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			limit, ok := limits[req.Spec().Procedure]
+			if req.Spec().IsClient || !ok {
+				return next(ctx, req)
+			}
+			if limit.Ip != nil {
+				key := fmt.Sprintf("%s#IP#%s", req.Spec().Procedure, audit.RequestOrigin(req, trustProxy))
+				if err := take(ctx, store, key, *limit.Ip); err != nil {
+					return nil, err
+				}
+			}
+			if limit.Subject != nil {
+				claims, err := token.FromContext(ctx)
+				if err != nil {
+					return nil, err
+				}
+				key := fmt.Sprintf("%s#USER#%s", req.Spec().Procedure, claims.Subject)
+				if err := take(ctx, store, key, *limit.Subject); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+func take(ctx context.Context, store ratelimit.Store, key string, limit ratelimit.Limit) error {
+	wait, ok, err := store.Take(ctx, key, limit)
+	if err != nil {
+		return err
+	} else if !ok {
+		retryAfter := int64(math.Ceil(wait.Seconds()))
+		err := connect.NewError(connect.CodeResourceExhausted, fmt.Errorf("too many requests; try again in %ds", retryAfter))
+		err.Meta().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// package lockout provides the backoff policy for failed verification attempts.
+package lockout
+
+import (
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+const (
+	// number of failed attempts before the verification is locked.
+	maxAttempts = 5
+	// the lockout starts at lockoutBase and doubles with every further failure up to lockoutMax.
+	lockoutBase = time.Minute
+	lockoutMax  = time.Hour
+)
+
+// Until returns the time until which the verification is locked after the specified number of failures.
+// Returns false if the failures do not lead to a lockout.
+func Until(failures int64) (time.Time, bool) {
+	if failures < maxAttempts {
+		return time.Time{}, false
+	}
+	lockout := lockoutBase
+	for i := int64(maxAttempts); i < failures && lockout < lockoutMax; i++ {
+		lockout *= 2
+	}
+	return time.Now().Add(min(lockout, lockoutMax)), true
+}
+
+// Error returns the error reported while the verification is locked.
+func Error(until time.Time) error {
+	return connect.NewError(connect.CodeResourceExhausted, fmt.Errorf(
+		"too many failed attempts; try again in %s", time.Until(until).Round(time.Second),
+	))
+}
@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogMailer writes messages to the logger instead of delivering them (development only).
+type LogMailer struct {
+	logger *slog.Logger
+}
+
+func NewLog(logger *slog.Logger) *LogMailer {
+	return &LogMailer{logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	m.logger.Info(fmt.Sprintf("mail to %v: %s", msg.To, msg.Subject), "text", msg.Text)
+	return nil
+}
+
+// MaildirMailer stores messages in a local maildir instead of delivering them (development only).
+// The directory can be inspected with any maildir capable client (e.g. mutt -f <dir>).
+type MaildirMailer struct {
+	dir    string
+	sender string
+}
+
+func NewMaildir(dir, sender string) (*MaildirMailer, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create maildir: %v", err)
+		}
+	}
+	return &MaildirMailer{dir, sender}, nil
+}
+
+func (m *MaildirMailer) Send(ctx context.Context, msg *Message) error {
+	rawMsg, err := msg.encode(m.sender)
+	if err != nil {
+		return err
+	}
+	suffix := make([]byte, 8)
+	rand.Read(suffix)
+	hostname, _ := os.Hostname()
+	name := fmt.Sprintf("%d.%s.%s", time.Now().UnixNano(), hex.EncodeToString(suffix), hostname)
+
+	// maildir delivery: write to tmp and move to new once complete.
+	tmpPath := filepath.Join(m.dir, "tmp", name)
+	if err := os.WriteFile(tmpPath, rawMsg, 0600); err != nil {
+		return fmt.Errorf("failed to write message: %v", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(m.dir, "new", name)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to deliver message: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// package mail provides the transports used to deliver application mails (e.g. verification codes).
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Mailer delivers a message to its recipients.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	// Html is an optional html alternative of the text body.
+	Html string
+}
+
+// encode renders the message as rfc 5322 mail with a quoted-printable text body.
+// If the message contains html, the text and html bodies are sent as multipart/alternative.
+func (m *Message) encode(sender string) ([]byte, error) {
+	from, err := mail.ParseAddress(sender)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %v", err)
+	}
+	to := make([]string, 0, len(m.To))
+	for _, recipient := range m.To {
+		addr, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient address: %v", err)
+		}
+		to = append(to, addr.String())
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	_, domain, _ := strings.Cut(from.Address, "@")
+
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "From: %s\r\n", from.String())
+	fmt.Fprintf(buffer, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(buffer, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", m.Subject))
+	fmt.Fprintf(buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(buffer, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	fmt.Fprintf(buffer, "MIME-Version: 1.0\r\n")
+	if m.Html == "" {
+		fmt.Fprintf(buffer, "Content-Type: text/plain; charset=UTF-8\r\n")
+		fmt.Fprintf(buffer, "Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(buffer, m.Text); err != nil {
+			return nil, err
+		}
+		buffer.WriteString("\r\n")
+		return buffer.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(buffer)
+	fmt.Fprintf(buffer, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	// parts are ordered by increasing preference (rfc 2046), clients render the last part they support.
+	for _, part := range []struct{ contentType, body string }{{"text/plain", m.Text}, {"text/html", m.Html}} {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; charset=UTF-8", part.contentType)},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(partWriter, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	writer := quotedprintable.NewWriter(w)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	sestypes "github.com/aws/aws-sdk-go-v2/service/ses/types"
+)
+
+// SesMailer delivers messages through aws ses.
+type SesMailer struct {
+	sesClient *ses.Client
+	sender    string
+}
+
+func NewSes(sesClient *ses.Client, sender string) *SesMailer {
+	return &SesMailer{sesClient, sender}
+}
+
+func (m *SesMailer) Send(ctx context.Context, msg *Message) error {
+	body := &sestypes.Body{Text: &sestypes.Content{
+		Data:    aws.String(msg.Text),
+		Charset: aws.String("UTF-8"),
+	}}
+	if msg.Html != "" {
+		body.Html = &sestypes.Content{
+			Data:    aws.String(msg.Html),
+			Charset: aws.String("UTF-8"),
+		}
+	}
+	_, err := m.sesClient.SendEmail(ctx, &ses.SendEmailInput{
+		Destination: &sestypes.Destination{
+			ToAddresses: msg.To,
+		},
+		Source: aws.String(m.sender),
+		Message: &sestypes.Message{
+			Subject: &sestypes.Content{
+				Data: aws.String(msg.Subject), Charset: aws.String("UTF-8"),
+			},
+			Body: body,
+		},
+	})
+	return err
+}
@@ -0,0 +1,111 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SmtpMailer delivers messages to a generic smtp relay.
+type SmtpMailer struct {
+	addr     string
+	host     string
+	sender   string
+	security string
+	auth     smtp.Auth
+}
+
+// NewSmtp creates a mailer for the relay at addr (host:port). The security mode is either
+// 'starttls' (upgrade after connect), 'tls' (implicit tls, e.g. port 465) or 'none' (plaintext, local relays only).
+// If username is empty, no authentication is performed.
+func NewSmtp(addr, sender, security, username, password string) (*SmtpMailer, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address: %v", err)
+	}
+	switch security {
+	case "starttls", "tls", "none":
+	default:
+		return nil, fmt.Errorf("invalid smtp security '%s'; expected 'starttls', 'tls' or 'none'", security)
+	}
+	mailer := &SmtpMailer{
+		addr:     addr,
+		host:     host,
+		sender:   sender,
+		security: security,
+	}
+	if username != "" {
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer, nil
+}
+
+func (m *SmtpMailer) Send(ctx context.Context, msg *Message) error {
+	rawMsg, err := msg.encode(m.sender)
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(m.sender)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %v", err)
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp relay: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+	if m.security == "tls" {
+		conn = tls.Client(conn, &tls.Config{ServerName: m.host})
+	}
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to initiate smtp session: %v", err)
+	}
+	defer client.Close()
+
+	if m.security == "starttls" {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return fmt.Errorf("failed to start tls: %v", err)
+		}
+	}
+	if m.auth != nil {
+		if err := client.Auth(m.auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %v", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp relay rejected sender: %v", err)
+	}
+	for _, recipient := range msg.To {
+		to, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return fmt.Errorf("invalid recipient address: %v", err)
+		}
+		if err := client.Rcpt(to.Address); err != nil {
+			return fmt.Errorf("smtp relay rejected recipient: %v", err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to initiate data transfer: %v", err)
+	}
+	if _, err := writer.Write(rawMsg); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to transfer message: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp relay rejected message: %v", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,179 @@
+// package account provides the operations spanning the user partition (USER#<sub> -> PROFILE)
+// and the email partition (EMAIL#<addr> -> REGISTRATION) of an account.
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/model/user"
+)
+
+// Store writes the profile and the registration of an account in one atomic operation.
+// ScanIndex reads the profiles and registrations of all accounts (expensive, used by the reconciliation).
+type Store interface {
+	CreateAccount(ctx context.Context, sub, email string, profile *user.Profile) error
+	DeleteAccount(ctx context.Context, sub, email string) error
+	ScanIndex(ctx context.Context) (*Index, error)
+}
+
+// Index contains the accounts found in the table.
+type Index struct {
+	// Profiles maps the subject of every profile to its creation time.
+	Profiles map[string]int64
+	// Registrations maps every registered email to its subject.
+	Registrations map[string]string
+}
+
+// Model implements the Store on top of dynamodb.
+type Model struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func New(client *dynamodb.Client, table string) *Model {
+	return &Model{client, table}
+}
+
+// the keys must match the ones of the user and email model.
+const (
+	userPrefix      = "USER#"
+	emailPrefix     = "EMAIL#"
+	profileKey      = "PROFILE"
+	registrationKey = "REGISTRATION"
+)
+
+// CreateAccount inserts the profile and the registration. Fails with AlreadyExists if the email is registered.
+func (m *Model) CreateAccount(ctx context.Context, sub, addr string, profile *user.Profile) error {
+	profile.PK = userPrefix + sub
+	profile.SK = profileKey
+	profileItem, err := attributevalue.MarshalMap(profile)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	registrationItem, err := attributevalue.MarshalMap(&email.Registration{
+		PK:   emailPrefix + addr,
+		SK:   registrationKey,
+		User: sub,
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	_, err = m.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName:           aws.String(m.table),
+				Item:                profileItem,
+				ConditionExpression: aws.String("attribute_not_exists(pk)"),
+			}},
+			{Put: &types.Put{
+				TableName:           aws.String(m.table),
+				Item:                registrationItem,
+				ConditionExpression: aws.String("attribute_not_exists(pk)"),
+			}},
+		},
+	})
+	if err != nil {
+		var tErr *types.TransactionCanceledException
+		if errors.As(err, &tErr) && len(tErr.CancellationReasons) == 2 {
+			if aws.ToString(tErr.CancellationReasons[1].Code) == "ConditionalCheckFailed" {
+				return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("email already associated with an account"))
+			}
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// DeleteAccount removes the registration and the profile. Deleting an account that does not exist succeeds,
+// fails with FailedPrecondition if the email is registered to another user.
+func (m *Model) DeleteAccount(ctx context.Context, sub, addr string) error {
+	_, err := m.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Delete: &types.Delete{
+				TableName: aws.String(m.table),
+				Key: map[string]types.AttributeValue{
+					"pk": &types.AttributeValueMemberS{Value: emailPrefix + addr},
+					"sk": &types.AttributeValueMemberS{Value: registrationKey},
+				},
+				ExpressionAttributeNames: map[string]string{
+					"#user": "user",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":user": &types.AttributeValueMemberS{Value: sub},
+				},
+				ConditionExpression: aws.String("attribute_not_exists(pk) OR #user = :user"),
+			}},
+			{Delete: &types.Delete{
+				TableName: aws.String(m.table),
+				Key: map[string]types.AttributeValue{
+					"pk": &types.AttributeValueMemberS{Value: userPrefix + sub},
+					"sk": &types.AttributeValueMemberS{Value: profileKey},
+				},
+			}},
+		},
+	})
+	if err != nil {
+		var tErr *types.TransactionCanceledException
+		if errors.As(err, &tErr) && len(tErr.CancellationReasons) == 2 {
+			if aws.ToString(tErr.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+				return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("email is not registered to the user"))
+			}
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// ScanIndex scans the whole table (strongly consistent) for profiles and registrations.
+func (m *Model) ScanIndex(ctx context.Context) (*Index, error) {
+	index := &Index{Profiles: map[string]int64{}, Registrations: map[string]string{}}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := m.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName: aws.String(m.table),
+			ExpressionAttributeNames: map[string]string{
+				"#user": "user",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":profile":      &types.AttributeValueMemberS{Value: profileKey},
+				":registration": &types.AttributeValueMemberS{Value: registrationKey},
+			},
+			FilterExpression:     aws.String("sk = :profile OR sk = :registration"),
+			ProjectionExpression: aws.String("pk, sk, #user, created_at"),
+			ConsistentRead:       aws.Bool(true),
+			ExclusiveStartKey:    startKey,
+		})
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		for _, item := range result.Items {
+			entry := struct {
+				PK        string `dynamodbav:"pk"`
+				SK        string `dynamodbav:"sk"`
+				User      string `dynamodbav:"user"`
+				CreatedAt int64  `dynamodbav:"created_at"`
+			}{}
+			if err := attributevalue.UnmarshalMap(item, &entry); err != nil {
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			if sub, ok := strings.CutPrefix(entry.PK, userPrefix); ok && entry.SK == profileKey {
+				index.Profiles[sub] = entry.CreatedAt
+			} else if addr, ok := strings.CutPrefix(entry.PK, emailPrefix); ok && entry.SK == registrationKey {
+				index.Registrations[addr] = entry.User
+			}
+		}
+		if len(result.LastEvaluatedKey) < 1 {
+			return index, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,112 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/model/user"
+)
+
+// BoltModel implements the Store on top of the embedded bolt table.
+type BoltModel struct {
+	table *bolt.Table
+}
+
+func NewBolt(table *bolt.Table) *BoltModel {
+	return &BoltModel{table}
+}
+
+var (
+	// errAccountExists and errAccountMismatch are used to abort the account transactions.
+	errAccountExists   = errors.New("account exists")
+	errAccountMismatch = errors.New("account mismatch")
+)
+
+func (m *BoltModel) CreateAccount(ctx context.Context, sub, addr string, profile *user.Profile) error {
+	profile.PK = userPrefix + sub
+	profile.SK = profileKey
+	registration := &email.Registration{PK: emailPrefix + addr, SK: registrationKey, User: sub}
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		for _, key := range [][2]string{{profile.PK, profile.SK}, {registration.PK, registration.SK}} {
+			exists, err := tx.Exists(key[0], key[1])
+			if err != nil {
+				return err
+			} else if exists {
+				return errAccountExists
+			}
+		}
+		if err := tx.Put(profile.PK, profile.SK, profile, 0); err != nil {
+			return err
+		}
+		return tx.Put(registration.PK, registration.SK, registration, 0)
+	})
+	if err != nil {
+		if errors.Is(err, errAccountExists) {
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("email already associated with an account"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeleteAccount(ctx context.Context, sub, addr string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		registration := &email.Registration{}
+		found, err := tx.Get(emailPrefix+addr, registrationKey, registration)
+		if err != nil {
+			return err
+		} else if found && registration.User != sub {
+			return errAccountMismatch
+		} else if found {
+			if err := tx.Delete(emailPrefix+addr, registrationKey); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(userPrefix+sub, profileKey)
+	})
+	if err != nil {
+		if errors.Is(err, errAccountMismatch) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("email is not registered to the user"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) ScanIndex(ctx context.Context) (*Index, error) {
+	index := &Index{Profiles: map[string]int64{}, Registrations: map[string]string{}}
+	err := m.table.View(func(tx *bolt.Tx) error {
+		err := tx.Scan(profileKey, func(item *bolt.Item) error {
+			profile := &user.Profile{}
+			if err := item.Decode(profile); err != nil {
+				return err
+			}
+			if sub, ok := strings.CutPrefix(item.PK, userPrefix); ok {
+				index.Profiles[sub] = profile.CreatedAt
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Scan(registrationKey, func(item *bolt.Item) error {
+			registration := &email.Registration{}
+			if err := item.Decode(registration); err != nil {
+				return err
+			}
+			if addr, ok := strings.CutPrefix(item.PK, emailPrefix); ok {
+				index.Registrations[addr] = registration.User
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return index, nil
+}
@@ -0,0 +1,217 @@
+// package bolt provides an embedded single table (pk/sk) store on top of bbolt.
+// It is used as dynamodb replacement by the embedded model backends (self-hosted setups and tests).
+package bolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// Table emulates a dynamodb table with a string partition and sort key inside a bbolt bucket.
+// Items are stored as json and can carry an expiration timestamp (emulating the table ttl).
+type Table struct {
+	db     *bbolt.DB
+	bucket []byte
+}
+
+// Open opens (or creates) the database file and ensures the table bucket exists.
+func Open(path, table string) (*Table, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(table))
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create table: %v", err)
+	}
+	return &Table{db: db, bucket: []byte(table)}, nil
+}
+
+func (t *Table) Close() error {
+	return t.db.Close()
+}
+
+// View executes a readonly transaction on the table.
+func (t *Table) View(fn func(tx *Tx) error) error {
+	return t.db.View(func(tx *bbolt.Tx) error {
+		return fn(&Tx{bucket: tx.Bucket(t.bucket), now: time.Now().Unix()})
+	})
+}
+
+// Update executes a read-write transaction on the table, if fn returns an error all writes are rolled back.
+func (t *Table) Update(fn func(tx *Tx) error) error {
+	return t.db.Update(func(tx *bbolt.Tx) error {
+		return fn(&Tx{bucket: tx.Bucket(t.bucket), now: time.Now().Unix()})
+	})
+}
+
+type Tx struct {
+	bucket *bbolt.Bucket
+	now    int64
+}
+
+// envelope wraps the stored item with the ttl information.
+type envelope struct {
+	ExpiresAt int64           `json:"expires_at,omitempty"`
+	Item      json.RawMessage `json:"item"`
+}
+
+// Item is a raw item returned by a query.
+type Item struct {
+	PK   string
+	SK   string
+	item json.RawMessage
+}
+
+// Decode unmarshals the item into v.
+func (i *Item) Decode(v any) error {
+	return json.Unmarshal(i.item, v)
+}
+
+func key(pk, sk string) []byte {
+	return []byte(pk + "\x00" + sk)
+}
+
+func splitKey(k []byte) (string, string) {
+	pk, sk, _ := bytes.Cut(k, []byte{0})
+	return string(pk), string(sk)
+}
+
+// decode returns the item stored in the raw envelope or false if it is expired.
+func (tx *Tx) decode(raw []byte) (json.RawMessage, bool, error) {
+	env := &envelope{}
+	if err := json.Unmarshal(raw, env); err != nil {
+		return nil, false, err
+	}
+	if env.ExpiresAt != 0 && env.ExpiresAt <= tx.now {
+		return nil, false, nil
+	}
+	return env.Item, true, nil
+}
+
+// Get reads the item into v, returns false if the item does not exist or is expired.
+func (tx *Tx) Get(pk, sk string, v any) (bool, error) {
+	raw := tx.bucket.Get(key(pk, sk))
+	if raw == nil {
+		return false, nil
+	}
+	item, ok, err := tx.decode(raw)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := json.Unmarshal(item, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Exists checks if an unexpired item exists under the specified key.
+func (tx *Tx) Exists(pk, sk string) (bool, error) {
+	raw := tx.bucket.Get(key(pk, sk))
+	if raw == nil {
+		return false, nil
+	}
+	_, ok, err := tx.decode(raw)
+	return ok, err
+}
+
+// Put inserts or replaces the item. An expiresAt of 0 disables expiration.
+func (tx *Tx) Put(pk, sk string, v any, expiresAt int64) error {
+	item, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(&envelope{ExpiresAt: expiresAt, Item: item})
+	if err != nil {
+		return err
+	}
+	return tx.bucket.Put(key(pk, sk), raw)
+}
+
+func (tx *Tx) Delete(pk, sk string) error {
+	return tx.bucket.Delete(key(pk, sk))
+}
+
+// Scan calls fn for every unexpired item of the table with the specified sort key (emulates a filtered table scan).
+func (tx *Tx) Scan(sk string, fn func(item *Item) error) error {
+	cursor := tx.bucket.Cursor()
+	for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+		itemPk, itemSk := splitKey(k)
+		if itemSk != sk {
+			continue
+		}
+		item, ok, err := tx.decode(v)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		if err := fn(&Item{PK: itemPk, SK: itemSk, item: item}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query returns all unexpired items of the partition with a sort key between from and to (inclusive).
+// Items are returned in ascending sort key order, a limit of 0 returns all matching items.
+func (tx *Tx) Query(pk, from, to string, limit int) ([]*Item, error) {
+	items := []*Item{}
+	upper := key(pk, to)
+	cursor := tx.bucket.Cursor()
+	for k, v := cursor.Seek(key(pk, from)); k != nil && bytes.Compare(k, upper) <= 0; k, v = cursor.Next() {
+		item, ok, err := tx.decode(v)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		itemPk, itemSk := splitKey(k)
+		items = append(items, &Item{PK: itemPk, SK: itemSk, item: item})
+		if limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+	return items, nil
+}
+
+// QueryReverse returns the unexpired items of the partition with a sort key between from and to (inclusive)
+// in descending sort key order (like a query with ScanIndexForward false), a limit of 0 returns all matching items.
+func (tx *Tx) QueryReverse(pk, from, to string, limit int) ([]*Item, error) {
+	items := []*Item{}
+	lower, upper := key(pk, from), key(pk, to)
+	cursor := tx.bucket.Cursor()
+	k, v := cursor.Seek(upper)
+	if k == nil {
+		k, v = cursor.Last()
+	} else if bytes.Compare(k, upper) > 0 {
+		k, v = cursor.Prev()
+	}
+	for ; k != nil && bytes.Compare(k, lower) >= 0; k, v = cursor.Prev() {
+		item, ok, err := tx.decode(v)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		itemPk, itemSk := splitKey(k)
+		items = append(items, &Item{PK: itemPk, SK: itemSk, item: item})
+		if limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+	return items, nil
+}
+
+// QueryPrefix returns all unexpired items of the partition where the sort key begins with prefix.
+func (tx *Tx) QueryPrefix(pk, prefix string, limit int) ([]*Item, error) {
+	return tx.Query(pk, prefix, prefix+"\xff", limit)
+}
@@ -0,0 +1,54 @@
+package ceremony
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+// BoltModel implements the Store on top of the embedded bolt table.
+type BoltModel struct {
+	table *bolt.Table
+}
+
+func NewBolt(table *bolt.Table) *BoltModel {
+	return &BoltModel{table}
+}
+
+func (m *BoltModel) PutCeremony(ctx context.Context, kind, id string, ceremony *Ceremony) error {
+	ceremony.PK = ceremonyKey(id)
+	ceremony.SK = kind
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		exists, err := tx.Exists(ceremony.PK, ceremony.SK)
+		if err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("ceremony already exists")
+		}
+		return tx.Put(ceremony.PK, ceremony.SK, ceremony, ceremony.ExpiresAt)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) ConsumeCeremony(ctx context.Context, kind, id string) (*Ceremony, bool, error) {
+	ceremony := &Ceremony{}
+	var found bool
+	err := m.table.Update(func(tx *bolt.Tx) (err error) {
+		found, err = tx.Get(ceremonyKey(id), kind, ceremony)
+		if err != nil || !found {
+			return err
+		}
+		return tx.Delete(ceremonyKey(id), kind)
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	return ceremony, true, nil
+}
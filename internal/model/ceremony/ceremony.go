@@ -0,0 +1,89 @@
+// package ceremony provides an application aware wrapper for the required storage communication on the ceremony model.
+// Ceremonies hold the short-lived server-side state of multi-step authentication flows (e.g. webauthn challenges).
+package ceremony
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Store provides access to the ceremonies (CEREMONY#<id> -> <kind>).
+// The kind prevents that the state of one flow is accepted by another flow.
+type Store interface {
+	PutCeremony(ctx context.Context, kind, id string, ceremony *Ceremony) error
+	ConsumeCeremony(ctx context.Context, kind, id string) (*Ceremony, bool, error)
+}
+
+// Ceremony holds the opaque flow state, Subject is optional and binds the ceremony to a user.
+type Ceremony struct {
+	PK        string `dynamodbav:"pk"`
+	SK        string `dynamodbav:"sk"`
+	Subject   string `dynamodbav:"subject,omitempty"`
+	Data      string `dynamodbav:"data"`
+	ExpiresAt int64  `dynamodbav:"expires_at"`
+}
+
+// Model implements the Store on top of dynamodb.
+type Model struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func New(client *dynamodb.Client, table string) *Model {
+	return &Model{client, table}
+}
+
+func ceremonyKey(id string) string {
+	return fmt.Sprintf("CEREMONY#%s", id)
+}
+
+func (m *Model) PutCeremony(ctx context.Context, kind, id string, ceremony *Ceremony) error {
+	ceremony.PK = ceremonyKey(id)
+	ceremony.SK = kind
+	item, err := attributevalue.MarshalMap(ceremony)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	_, err = m.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(m.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(pk)"),
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// ConsumeCeremony deletes the ceremony and returns it, every ceremony can be consumed only once.
+func (m *Model) ConsumeCeremony(ctx context.Context, kind, id string) (*Ceremony, bool, error) {
+	result, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: ceremonyKey(id)},
+			"sk": &types.AttributeValueMemberS{Value: kind},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if len(result.Attributes) < 1 {
+		return nil, false, nil
+	}
+
+	ceremony := &Ceremony{}
+	if err := attributevalue.UnmarshalMap(result.Attributes, ceremony); err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if ceremony.ExpiresAt <= time.Now().Unix() {
+		// ttl deletion is lazy, expired ceremonies can be around for a while.
+		return nil, false, nil
+	}
+	return ceremony, true, nil
+}
@@ -0,0 +1,94 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Attempts counts the code verifications of an email since the last successful one (every attempt is counted before
+// the code is checked). While LockedUntil is in the future, no code is accepted.
+type Attempts struct {
+	PK          string `dynamodbav:"pk"`
+	SK          string `dynamodbav:"sk"`
+	Failures    int64  `dynamodbav:"failures"`
+	LockedUntil int64  `dynamodbav:"locked_until"`
+	ExpiresAt   int64  `dynamodbav:"expires_at"`
+}
+
+func (m *Model) GetAttempts(ctx context.Context, email string) (*Attempts, bool, error) {
+	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(m.table),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":  &types.AttributeValueMemberS{Value: emailKey(email)},
+			":sk":  &types.AttributeValueMemberS{Value: attemptsKey},
+			":now": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
+		},
+		KeyConditionExpression: aws.String("pk = :pk AND sk = :sk"),
+		FilterExpression:       aws.String("expires_at > :now"),
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if len(result.Items) < 1 {
+		return nil, false, nil
+	}
+
+	attempts := &Attempts{}
+	if err := attributevalue.UnmarshalMap(result.Items[0], attempts); err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	}
+	return attempts, true, nil
+}
+
+// PutAttempts replaces the attempts if they still contain the previous number of failures (compare-and-swap,
+// expired attempts count as zero failures). Returns Aborted if the attempts were changed concurrently.
+func (m *Model) PutAttempts(ctx context.Context, email string, previousFailures int64, attempts *Attempts) error {
+	attempts.PK = emailKey(email)
+	attempts.SK = attemptsKey
+	item, err := attributevalue.MarshalMap(attempts)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	condition := "failures = :previous AND expires_at > :now"
+	if previousFailures == 0 {
+		condition = "attribute_not_exists(pk) OR expires_at <= :now"
+	}
+	_, err = m.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(m.table),
+		Item:      item,
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":previous": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", previousFailures)},
+			":now":      &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
+		},
+		ConditionExpression: aws.String(condition),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodeAborted, fmt.Errorf("attempts were changed concurrently"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *Model) DeleteAttempts(ctx context.Context, email string) error {
+	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: emailKey(email)},
+			"sk": &types.AttributeValueMemberS{Value: attemptsKey},
+		},
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) GetAttempts(ctx context.Context, email string) (*Attempts, bool, error) {
+	attempts := &Attempts{}
+	var found bool
+	err := m.table.View(func(tx *bolt.Tx) (err error) {
+		found, err = tx.Get(emailKey(email), attemptsKey, attempts)
+		return err
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	return attempts, true, nil
+}
+
+// errAttemptsChanged is used to abort the transaction if the attempts do not match the previous failures.
+var errAttemptsChanged = errors.New("attempts changed")
+
+func (m *BoltModel) PutAttempts(ctx context.Context, email string, previousFailures int64, attempts *Attempts) error {
+	attempts.PK = emailKey(email)
+	attempts.SK = attemptsKey
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		current := &Attempts{}
+		if _, err := tx.Get(attempts.PK, attempts.SK, current); err != nil {
+			return err
+		} else if current.Failures != previousFailures {
+			return errAttemptsChanged
+		}
+		return tx.Put(attempts.PK, attempts.SK, attempts, attempts.ExpiresAt)
+	})
+	if err != nil {
+		if errors.Is(err, errAttemptsChanged) {
+			return connect.NewError(connect.CodeAborted, fmt.Errorf("attempts were changed concurrently"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeleteAttempts(ctx context.Context, email string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		return tx.Delete(emailKey(email), attemptsKey)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
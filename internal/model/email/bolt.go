@@ -0,0 +1,14 @@
+package email
+
+import (
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+// BoltModel implements the Store on top of the embedded bolt table.
+type BoltModel struct {
+	table *bolt.Table
+}
+
+func NewBolt(table *bolt.Table) *BoltModel {
+	return &BoltModel{table}
+}
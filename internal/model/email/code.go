@@ -17,6 +17,7 @@ type Code struct {
 	PK        string `dynamodbav:"pk"`
 	SK        string `dynamodbav:"sk"`
 	Code      string `dynamodbav:"code,omitempty"`
+	Used      bool   `dynamodbav:"used,omitempty"`
 	ExpiresAt int64  `dynamodbav:"expires_at,omitempty"`
 }
 
@@ -24,8 +25,8 @@ func (m *Model) GetCode(ctx context.Context, email string) (*Code, bool, error)
 	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
 		TableName: aws.String(m.table),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":pk":  &types.AttributeValueMemberS{Value: fmt.Sprintf("EMAIL#%s", email)},
-			":sk":  &types.AttributeValueMemberS{Value: "CODE"},
+			":pk":  &types.AttributeValueMemberS{Value: emailKey(email)},
+			":sk":  &types.AttributeValueMemberS{Value: codeKey},
 			":now": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
 		},
 		KeyConditionExpression: aws.String("pk = :pk AND sk = :sk"),
@@ -45,8 +46,8 @@ func (m *Model) GetCode(ctx context.Context, email string) (*Code, bool, error)
 }
 
 func (m *Model) PutCode(ctx context.Context, email string, code *Code) error {
-	code.PK = fmt.Sprintf("EMAIL#%s", email)
-	code.SK = "CODE"
+	code.PK = emailKey(email)
+	code.SK = codeKey
 	item, err := attributevalue.MarshalMap(code)
 	if err != nil {
 		return connect.NewError(connect.CodeInvalidArgument, err)
@@ -55,9 +56,11 @@ func (m *Model) PutCode(ctx context.Context, email string, code *Code) error {
 		TableName: aws.String(m.table),
 		Item:      item,
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":now": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
+			":now":  &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
+			":used": &types.AttributeValueMemberBOOL{Value: true},
 		},
-		ConditionExpression: aws.String("attribute_not_exists(pk) or expires_at < :now"),
+		// used codes can be replaced immediately, otherwise the user would have to wait until the code expires.
+		ConditionExpression: aws.String("attribute_not_exists(pk) or expires_at < :now or used = :used"),
 	})
 	if err != nil {
 		var cErr *types.ConditionalCheckFailedException
@@ -68,3 +71,47 @@ func (m *Model) PutCode(ctx context.Context, email string, code *Code) error {
 	}
 	return nil
 }
+
+// ConsumeCode marks the code as used if the submitted code matches (compare-and-swap).
+// Returns NotFound if there is no unexpired code, FailedPrecondition if the code was already used
+// and PermissionDenied if the submitted code does not match.
+func (m *Model) ConsumeCode(ctx context.Context, email, submittedCode string) error {
+	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: emailKey(email)},
+			"sk": &types.AttributeValueMemberS{Value: codeKey},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":code": &types.AttributeValueMemberS{Value: submittedCode},
+			":used": &types.AttributeValueMemberBOOL{Value: true},
+			":now":  &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
+		},
+		UpdateExpression:                    aws.String("SET used = :used"),
+		ConditionExpression:                 aws.String("code = :code AND attribute_not_exists(used) AND expires_at > :now"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			code := &Code{}
+			if err := attributevalue.UnmarshalMap(cErr.Item, code); err != nil {
+				return connect.NewError(connect.CodeInternal, err)
+			}
+			return codeMismatchError(code)
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// codeMismatchError returns the error that describes why the submitted code was rejected.
+// The code is a zero value if no code exists.
+func codeMismatchError(code *Code) error {
+	if code.ExpiresAt <= time.Now().Unix() {
+		return connect.NewError(connect.CodeNotFound, fmt.Errorf("code does not exist or has expired"))
+	} else if code.Used {
+		return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("code was already used; request a new code"))
+	}
+	return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("incorrect code - permission denied"))
+}
@@ -0,0 +1,77 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) GetCode(ctx context.Context, email string) (*Code, bool, error) {
+	code := &Code{}
+	var found bool
+	err := m.table.View(func(tx *bolt.Tx) (err error) {
+		found, err = tx.Get(emailKey(email), codeKey, code)
+		return err
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	return code, true, nil
+}
+
+// errCodeExists is used to abort the transaction if an unexpired code is still present.
+var errCodeExists = errors.New("code exists")
+
+func (m *BoltModel) PutCode(ctx context.Context, email string, code *Code) error {
+	code.PK = emailKey(email)
+	code.SK = codeKey
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		current := &Code{}
+		found, err := tx.Get(code.PK, code.SK, current)
+		if err != nil {
+			return err
+		} else if found && !current.Used {
+			return errCodeExists
+		}
+		return tx.Put(code.PK, code.SK, code, code.ExpiresAt)
+	})
+	if err != nil {
+		if errors.Is(err, errCodeExists) {
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("email already sent"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// errCodeMismatch is used to abort the transaction if the submitted code is not accepted.
+var errCodeMismatch = errors.New("code mismatch")
+
+func (m *BoltModel) ConsumeCode(ctx context.Context, email, submittedCode string) error {
+	code := &Code{}
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		found, err := tx.Get(emailKey(email), codeKey, code)
+		if err != nil {
+			return err
+		} else if !found || code.Used || code.Code != submittedCode {
+			if !found {
+				code = &Code{}
+			}
+			return errCodeMismatch
+		}
+		code.Used = true
+		return tx.Put(code.PK, code.SK, code, code.ExpiresAt)
+	})
+	if err != nil {
+		if errors.Is(err, errCodeMismatch) {
+			return codeMismatchError(code)
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
@@ -1,10 +1,40 @@
-// package email provides an application aware wrapper for the required dynamodb communication on the email model.
+// package email provides an application aware wrapper for the required storage communication on the email model.
 package email
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
 
+// CodeStore provides access to the verification code of an email (EMAIL#<addr> -> CODE)
+// and the failed verification attempts on it (EMAIL#<addr> -> ATTEMPTS).
+type CodeStore interface {
+	GetCode(ctx context.Context, email string) (*Code, bool, error)
+	PutCode(ctx context.Context, email string, code *Code) error
+	ConsumeCode(ctx context.Context, email, submittedCode string) error
+	GetAttempts(ctx context.Context, email string) (*Attempts, bool, error)
+	PutAttempts(ctx context.Context, email string, previousFailures int64, attempts *Attempts) error
+	DeleteAttempts(ctx context.Context, email string) error
+}
+
+// RegistrationStore provides access to the user registered on an email (EMAIL#<addr> -> REGISTRATION).
+type RegistrationStore interface {
+	GetRegistration(ctx context.Context, email string) (*Registration, bool, error)
+	PutRegistration(ctx context.Context, email string, registration *Registration) error
+	DeleteRegistration(ctx context.Context, sub, email string) error
+	RegisteredUser(ctx context.Context, email string) (string, bool, error)
+	MoveRegistration(ctx context.Context, sub, from, to string) error
+}
+
+// Store combines all stores of the email partition.
+type Store interface {
+	CodeStore
+	RegistrationStore
+}
+
+// Model implements the Store on top of dynamodb.
 type Model struct {
 	client *dynamodb.Client
 	table  string
@@ -13,3 +43,13 @@ type Model struct {
 func New(client *dynamodb.Client, table string) *Model {
 	return &Model{client, table}
 }
+
+func emailKey(email string) string {
+	return fmt.Sprintf("EMAIL#%s", email)
+}
+
+const (
+	codeKey         = "CODE"
+	attemptsKey     = "ATTEMPTS"
+	registrationKey = "REGISTRATION"
+)
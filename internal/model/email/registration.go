@@ -2,6 +2,7 @@ package email
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"connectrpc.com/connect"
@@ -21,8 +22,8 @@ func (m *Model) GetRegistration(ctx context.Context, email string) (*Registratio
 	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
 		TableName: aws.String(m.table),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("EMAIL#%s", email)},
-			":sk": &types.AttributeValueMemberS{Value: "REGISTRATION"},
+			":pk": &types.AttributeValueMemberS{Value: emailKey(email)},
+			":sk": &types.AttributeValueMemberS{Value: registrationKey},
 		},
 		KeyConditionExpression: aws.String("pk = :pk AND sk = :sk"),
 	})
@@ -40,8 +41,8 @@ func (m *Model) GetRegistration(ctx context.Context, email string) (*Registratio
 }
 
 func (m *Model) PutRegistration(ctx context.Context, email string, registration *Registration) error {
-	registration.PK = fmt.Sprintf("EMAIL#%s", email)
-	registration.SK = "REGISTRATION"
+	registration.PK = emailKey(email)
+	registration.SK = registrationKey
 	item, err := attributevalue.MarshalMap(registration)
 	if err != nil {
 		return connect.NewError(connect.CodeInvalidArgument, err)
@@ -57,15 +58,85 @@ func (m *Model) PutRegistration(ctx context.Context, email string, registration
 	return nil
 }
 
-func (m *Model) DeleteRegistration(ctx context.Context, email string) error {
+// DeleteRegistration removes the registration of the user. A registration of another user is left untouched,
+// deleting a registration that does not exist succeeds (the deletion can be repeated).
+func (m *Model) DeleteRegistration(ctx context.Context, sub, email string) error {
 	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(m.table),
 		Key: map[string]types.AttributeValue{
-			"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("EMAIL#%s", email)},
-			"sk": &types.AttributeValueMemberS{Value: "REGISTRATION"},
+			"pk": &types.AttributeValueMemberS{Value: emailKey(email)},
+			"sk": &types.AttributeValueMemberS{Value: registrationKey},
+		},
+		ExpressionAttributeNames: map[string]string{
+			"#user": "user",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":user": &types.AttributeValueMemberS{Value: sub},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(pk) OR #user = :user"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return nil
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// RegisteredUser returns the user registered with the email.
+func (m *Model) RegisteredUser(ctx context.Context, email string) (string, bool, error) {
+	registration, found, err := m.GetRegistration(ctx, email)
+	if err != nil || !found {
+		return "", false, err
+	}
+	return registration.User, true, nil
+}
+
+// MoveRegistration atomically moves the registration of the user from one email to another.
+// Fails with AlreadyExists if the new email is registered and with FailedPrecondition if the old one is not registered to the user.
+func (m *Model) MoveRegistration(ctx context.Context, sub, from, to string) error {
+	item, err := attributevalue.MarshalMap(&Registration{
+		PK:   emailKey(to),
+		SK:   registrationKey,
+		User: sub,
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	_, err = m.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName:           aws.String(m.table),
+				Item:                item,
+				ConditionExpression: aws.String("attribute_not_exists(pk)"),
+			}},
+			{Delete: &types.Delete{
+				TableName: aws.String(m.table),
+				Key: map[string]types.AttributeValue{
+					"pk": &types.AttributeValueMemberS{Value: emailKey(from)},
+					"sk": &types.AttributeValueMemberS{Value: registrationKey},
+				},
+				ExpressionAttributeNames: map[string]string{
+					"#user": "user",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":user": &types.AttributeValueMemberS{Value: sub},
+				},
+				ConditionExpression: aws.String("#user = :user"),
+			}},
 		},
 	})
 	if err != nil {
+		var tErr *types.TransactionCanceledException
+		if errors.As(err, &tErr) && len(tErr.CancellationReasons) == 2 {
+			if aws.ToString(tErr.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+				return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("email already associated with an account"))
+			} else if aws.ToString(tErr.CancellationReasons[1].Code) == "ConditionalCheckFailed" {
+				return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("email is not registered to the user"))
+			}
+		}
 		return connect.NewError(connect.CodeInternal, err)
 	}
 	return nil
@@ -0,0 +1,106 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) GetRegistration(ctx context.Context, email string) (*Registration, bool, error) {
+	registration := &Registration{}
+	var found bool
+	err := m.table.View(func(tx *bolt.Tx) (err error) {
+		found, err = tx.Get(emailKey(email), registrationKey, registration)
+		return err
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	return registration, true, nil
+}
+
+func (m *BoltModel) PutRegistration(ctx context.Context, email string, registration *Registration) error {
+	registration.PK = emailKey(email)
+	registration.SK = registrationKey
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		exists, err := tx.Exists(registration.PK, registration.SK)
+		if err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("registration already exists")
+		}
+		return tx.Put(registration.PK, registration.SK, registration, 0)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeleteRegistration(ctx context.Context, sub, email string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		registration := &Registration{}
+		found, err := tx.Get(emailKey(email), registrationKey, registration)
+		if err != nil {
+			return err
+		} else if !found || registration.User != sub {
+			return nil
+		}
+		return tx.Delete(registration.PK, registration.SK)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) RegisteredUser(ctx context.Context, email string) (string, bool, error) {
+	registration, found, err := m.GetRegistration(ctx, email)
+	if err != nil || !found {
+		return "", false, err
+	}
+	return registration.User, true, nil
+}
+
+var (
+	// errRegistrationExists and errRegistrationMismatch are used to abort the registration move.
+	errRegistrationExists   = errors.New("registration exists")
+	errRegistrationMismatch = errors.New("registration mismatch")
+)
+
+func (m *BoltModel) MoveRegistration(ctx context.Context, sub, from, to string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		exists, err := tx.Exists(emailKey(to), registrationKey)
+		if err != nil {
+			return err
+		} else if exists {
+			return errRegistrationExists
+		}
+		registration := &Registration{}
+		found, err := tx.Get(emailKey(from), registrationKey, registration)
+		if err != nil {
+			return err
+		} else if !found || registration.User != sub {
+			return errRegistrationMismatch
+		}
+		if err := tx.Delete(registration.PK, registration.SK); err != nil {
+			return err
+		}
+		registration.PK = emailKey(to)
+		return tx.Put(registration.PK, registration.SK, registration, 0)
+	})
+	if err != nil {
+		if errors.Is(err, errRegistrationExists) {
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("email already associated with an account"))
+		} else if errors.Is(err, errRegistrationMismatch) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("email is not registered to the user"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
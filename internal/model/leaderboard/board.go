@@ -32,8 +32,7 @@ type BoardEntry struct {
 }
 
 func (m *Model) GetBoard(ctx context.Context, date time.Time) (*Board, bool, error) {
-	year, week := date.ISOWeek()
-	key := fmt.Sprintf("%d-%d.json", year, week)
+	key := boardKey(date)
 	result, err := m.s3Client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(m.bucket),
 		Key:    aws.String(fmt.Sprint(m.prefix, key)),
@@ -66,8 +65,7 @@ func (m *Model) PutBoard(ctx context.Context, date time.Time, board *Board) erro
 		return connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
-	year, week := date.ISOWeek()
-	key := fmt.Sprintf("%s%d-%d.json", m.prefix, year, week)
+	key := fmt.Sprint(m.prefix, boardKey(date))
 	input := &s3.PutObjectInput{
 		Bucket:  aws.String(m.bucket),
 		Key:     aws.String(key),
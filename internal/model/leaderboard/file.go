@@ -0,0 +1,91 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/gofrs/flock"
+)
+
+// FileModel implements the BoardStore on a local directory.
+// The compare-and-swap semantics of s3 are emulated with a content hash as ETag and an exclusive file lock.
+type FileModel struct {
+	dir string
+}
+
+func NewFile(dir string) (*FileModel, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create board directory: %v", err)
+	}
+	return &FileModel{dir}, nil
+}
+
+func (m *FileModel) GetBoard(ctx context.Context, date time.Time) (*Board, bool, error) {
+	rawBoard, err := os.ReadFile(filepath.Join(m.dir, boardKey(date)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	}
+
+	board := &Board{ETag: contentTag(rawBoard)}
+	err = json.Unmarshal(rawBoard, board)
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	}
+	return board, true, nil
+}
+
+func (m *FileModel) PutBoard(ctx context.Context, date time.Time, board *Board) error {
+	rawBoard, err := json.Marshal(board)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	path := filepath.Join(m.dir, boardKey(date))
+	lock := flock.New(filepath.Join(m.dir, ".lock"))
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	} else if !locked {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to acquire board lock"))
+	}
+	defer lock.Unlock()
+
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	if current == nil && board.ETag != "" || current != nil && board.ETag != contentTag(current) {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("board was modified concurrently"))
+	}
+
+	// write to a temporary file first, so readers never observe a partially written board.
+	tmp, err := os.CreateTemp(m.dir, ".board-*")
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(rawBoard); err != nil {
+		tmp.Close()
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
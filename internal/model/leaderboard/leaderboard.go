@@ -1,10 +1,24 @@
-// package leaderboard provides an application aware wrapper for the required s3 communication on the leaderboard model.
+// package leaderboard provides an application aware wrapper for the required storage communication on the leaderboard model.
 package leaderboard
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
+// BoardStore provides access to the weekly boards (YEAR-WEEK.json).
+// PutBoard performs a compare-and-swap based on the board ETag; a board without ETag can only be created, not overwritten.
+type BoardStore interface {
+	GetBoard(ctx context.Context, date time.Time) (*Board, bool, error)
+	PutBoard(ctx context.Context, date time.Time, board *Board) error
+}
+
+// Model implements the BoardStore on top of s3.
 type Model struct {
 	s3Client *s3.Client
 	bucket   string
@@ -14,3 +28,14 @@ type Model struct {
 func New(s3 *s3.Client, bucket, prefix string) *Model {
 	return &Model{s3, bucket, prefix}
 }
+
+func boardKey(date time.Time) string {
+	year, week := date.ISOWeek()
+	return fmt.Sprintf("%d-%d.json", year, week)
+}
+
+// contentTag returns the ETag used by the local backends (quoted sha256 of the raw board).
+func contentTag(raw []byte) string {
+	hash := sha256.Sum256(raw)
+	return fmt.Sprintf("%q", hex.EncodeToString(hash[:]))
+}
@@ -0,0 +1,53 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// MemoryModel implements the BoardStore in memory (used for tests and ephemeral setups).
+type MemoryModel struct {
+	lock   sync.Mutex
+	boards map[string][]byte
+}
+
+func NewMemory() *MemoryModel {
+	return &MemoryModel{boards: map[string][]byte{}}
+}
+
+func (m *MemoryModel) GetBoard(ctx context.Context, date time.Time) (*Board, bool, error) {
+	m.lock.Lock()
+	rawBoard, ok := m.boards[boardKey(date)]
+	m.lock.Unlock()
+	if !ok {
+		return nil, false, nil
+	}
+
+	board := &Board{ETag: contentTag(rawBoard)}
+	err := json.Unmarshal(rawBoard, board)
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	}
+	return board, true, nil
+}
+
+func (m *MemoryModel) PutBoard(ctx context.Context, date time.Time, board *Board) error {
+	rawBoard, err := json.Marshal(board)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	current, ok := m.boards[boardKey(date)]
+	if !ok && board.ETag != "" || ok && board.ETag != contentTag(current) {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("board was modified concurrently"))
+	}
+	m.boards[boardKey(date)] = rawBoard
+	return nil
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval specifies how often expired buckets are removed from memory.
+const sweepInterval = time.Minute
+
+// MemoryModel implements the Store in memory (used for tests and standalone setups with a single process).
+type MemoryModel struct {
+	lock      sync.Mutex
+	buckets   map[string]*Bucket
+	nextSweep time.Time
+}
+
+func NewMemory() *MemoryModel {
+	return &MemoryModel{buckets: map[string]*Bucket{}}
+}
+
+func (m *MemoryModel) Take(ctx context.Context, key string, limit Limit) (time.Duration, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	now := time.Now()
+	if now.After(m.nextSweep) {
+		for key, bucket := range m.buckets {
+			if bucket.ExpiresAt < now.Unix() {
+				delete(m.buckets, key)
+			}
+		}
+		m.nextSweep = now.Add(sweepInterval)
+	}
+
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &Bucket{}
+	}
+	wait, ok := limit.take(bucket, now)
+	if !ok {
+		return wait, false, nil
+	}
+	m.buckets[key] = bucket
+	return 0, true, nil
+}
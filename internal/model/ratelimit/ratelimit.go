@@ -0,0 +1,131 @@
+// package ratelimit provides an application aware wrapper for the required storage communication on the rate limit buckets.
+// Buckets are stored in the table so that the limits hold across all lambda instances.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	bucketKey = "BUCKET"
+	// number of attempts to update a bucket that is concurrently modified by another instance.
+	maxRetries = 3
+)
+
+// Store provides access to the token buckets (RATELIMIT#<key> -> BUCKET).
+type Store interface {
+	// Take removes one token from the bucket. If the bucket is empty, it returns false and the duration
+	// until the next token is available.
+	Take(ctx context.Context, key string, limit Limit) (time.Duration, bool, error)
+}
+
+// Limit allows bursts of up to Burst requests, the bucket is refilled with one token per Interval.
+type Limit struct {
+	Burst    int64
+	Interval time.Duration
+}
+
+// Bucket holds the tokens available at UpdatedAt (unix milliseconds).
+// The bucket expires once it is refilled completely, a missing bucket is equivalent to a full bucket.
+type Bucket struct {
+	PK        string  `dynamodbav:"pk"`
+	SK        string  `dynamodbav:"sk"`
+	Tokens    float64 `dynamodbav:"tokens"`
+	UpdatedAt int64   `dynamodbav:"updated_at"`
+	ExpiresAt int64   `dynamodbav:"expires_at"`
+}
+
+// take refills the bucket up to now and removes one token if available.
+func (l Limit) take(bucket *Bucket, now time.Time) (time.Duration, bool) {
+	if bucket.UpdatedAt == 0 {
+		bucket.Tokens = float64(l.Burst)
+	} else {
+		elapsed := max(now.UnixMilli()-bucket.UpdatedAt, 0)
+		bucket.Tokens = min(float64(l.Burst), bucket.Tokens+float64(elapsed)/float64(l.Interval.Milliseconds()))
+	}
+	bucket.UpdatedAt = now.UnixMilli()
+	if bucket.Tokens < 1 {
+		return time.Duration((1 - bucket.Tokens) * float64(l.Interval)), false
+	}
+	bucket.Tokens--
+	bucket.ExpiresAt = now.Add(time.Duration((float64(l.Burst)-bucket.Tokens)*float64(l.Interval))).Unix() + 1
+	return 0, true
+}
+
+// Model implements the Store on top of dynamodb.
+type Model struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func New(client *dynamodb.Client, table string) *Model {
+	return &Model{client, table}
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("RATELIMIT#%s", key)
+}
+
+// Take reads the bucket and writes it back with a compare-and-swap on updated_at.
+// If the bucket is modified concurrently too often, the request is treated as limited.
+func (m *Model) Take(ctx context.Context, key string, limit Limit) (time.Duration, bool, error) {
+	for range maxRetries {
+		result, err := m.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(m.table),
+			Key: map[string]types.AttributeValue{
+				"pk": &types.AttributeValueMemberS{Value: rateLimitKey(key)},
+				"sk": &types.AttributeValueMemberS{Value: bucketKey},
+			},
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return 0, false, connect.NewError(connect.CodeInternal, err)
+		}
+		bucket := &Bucket{}
+		if err := attributevalue.UnmarshalMap(result.Item, bucket); err != nil {
+			return 0, false, connect.NewError(connect.CodeInternal, err)
+		}
+		previous := bucket.UpdatedAt
+		wait, ok := limit.take(bucket, time.Now())
+		if !ok {
+			return wait, false, nil
+		}
+
+		bucket.PK = rateLimitKey(key)
+		bucket.SK = bucketKey
+		item, err := attributevalue.MarshalMap(bucket)
+		if err != nil {
+			return 0, false, connect.NewError(connect.CodeInternal, err)
+		}
+		input := &dynamodb.PutItemInput{
+			TableName:           aws.String(m.table),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(pk)"),
+		}
+		if len(result.Item) > 0 {
+			input.ExpressionAttributeValues = map[string]types.AttributeValue{
+				":updated_at": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", previous)},
+			}
+			input.ConditionExpression = aws.String("updated_at = :updated_at")
+		}
+		_, err = m.client.PutItem(ctx, input)
+		if err != nil {
+			var cErr *types.ConditionalCheckFailedException
+			if errors.As(err, &cErr) {
+				continue
+			}
+			return 0, false, connect.NewError(connect.CodeInternal, err)
+		}
+		return 0, true, nil
+	}
+	return limit.Interval, false, nil
+}
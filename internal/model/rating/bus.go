@@ -0,0 +1,213 @@
+package rating
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Bus implements the Sender as in-process queue (replacement for sqs in single binary setups).
+// Every update is spooled to disk before it is acknowledged, pending updates survive restarts and are
+// delivered again until the handler succeeds (at-least-once, like sqs). The handler must therefore be idempotent.
+type Bus struct {
+	logger      *slog.Logger
+	dir         string
+	batchSize   int
+	batchWindow time.Duration
+	retryDelay  time.Duration
+
+	messages chan events.SQSMessage
+	// overflow signals that messages were spooled but did not fit into the channel.
+	overflow chan struct{}
+}
+
+type BusOption func(*Bus)
+
+// WithBatchSize sets the maximum number of updates delivered in one batch.
+func WithBatchSize(size int) BusOption {
+	return func(b *Bus) {
+		b.batchSize = size
+	}
+}
+
+// WithBatchWindow sets the maximum time updates are gathered before a batch is delivered.
+func WithBatchWindow(window time.Duration) BusOption {
+	return func(b *Bus) {
+		b.batchWindow = window
+	}
+}
+
+// WithRetryDelay sets the time a failed batch waits before it is delivered again.
+func WithRetryDelay(delay time.Duration) BusOption {
+	return func(b *Bus) {
+		b.retryDelay = delay
+	}
+}
+
+func NewBus(logger *slog.Logger, dir string, opts ...BusOption) (*Bus, error) {
+	bus := &Bus{
+		logger:      logger,
+		dir:         dir,
+		batchSize:   10000,
+		batchWindow: 10 * time.Second,
+		retryDelay:  30 * time.Second,
+		messages:    make(chan events.SQSMessage, 1024),
+		overflow:    make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(bus)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %v", err)
+	}
+	return bus, nil
+}
+
+func (b *Bus) SendUpdate(ctx context.Context, update *Update) error {
+	rawUpdate, err := json.Marshal(update)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	// ids are sortable by creation time which preserves the update order on replay.
+	id := fmt.Sprintf("%020d-%s", time.Now().UnixNano(), hex.EncodeToString(suffix))
+	if err := b.spool(id, rawUpdate); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	select {
+	case b.messages <- events.SQSMessage{MessageId: id, Body: string(rawUpdate)}:
+	default:
+		select {
+		case b.overflow <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Run delivers the queued updates in batches to the handler until the context is cancelled.
+// The handler receives the same event structure as the sqs triggered lambda.
+func (b *Bus) Run(ctx context.Context, handler func(ctx context.Context, r events.SQSEvent) error) error {
+	pending := map[string]events.SQSMessage{}
+	if err := b.load(pending); err != nil {
+		return err
+	}
+	window := time.NewTimer(b.batchWindow)
+	defer window.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message := <-b.messages:
+			pending[message.MessageId] = message
+			if len(pending) < b.batchSize {
+				continue
+			}
+		case <-b.overflow:
+			if err := b.load(pending); err != nil {
+				b.logger.Error(fmt.Sprintf("failed to load spooled updates: %v", err))
+			}
+			if len(pending) < b.batchSize {
+				continue
+			}
+		case <-window.C:
+		}
+
+		if len(pending) > 0 {
+			batch := b.batch(pending)
+			if err := handler(ctx, batch); err != nil {
+				b.logger.Error(fmt.Sprintf("failed to process %d updates; retrying in %s: %v", len(batch.Records), b.retryDelay, err))
+				window.Reset(b.retryDelay)
+				continue
+			}
+			for _, message := range batch.Records {
+				delete(pending, message.MessageId)
+				if err := os.Remove(b.path(message.MessageId)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					b.logger.Warn(fmt.Sprintf("failed to remove spooled update '%s': %v", message.MessageId, err))
+				}
+			}
+		}
+		window.Reset(b.batchWindow)
+	}
+}
+
+// batch returns the oldest pending messages as event (up to the batch size).
+func (b *Bus) batch(pending map[string]events.SQSMessage) events.SQSEvent {
+	ids := make([]string, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	if len(ids) > b.batchSize {
+		ids = ids[:b.batchSize]
+	}
+	event := events.SQSEvent{Records: make([]events.SQSMessage, 0, len(ids))}
+	for _, id := range ids {
+		event.Records = append(event.Records, pending[id])
+	}
+	return event
+}
+
+func (b *Bus) path(id string) string {
+	return filepath.Join(b.dir, id+".json")
+}
+
+// spool durably writes the update to the spool directory.
+func (b *Bus) spool(id string, rawUpdate []byte) error {
+	tmp, err := os.CreateTemp(b.dir, ".update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(rawUpdate); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), b.path(id))
+}
+
+// load reads all spooled updates into pending.
+func (b *Bus) load(pending map[string]events.SQSMessage) error {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory: %v", err)
+	}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || strings.HasPrefix(id, ".") {
+			continue
+		} else if _, ok := pending[id]; ok {
+			continue
+		}
+		rawUpdate, err := os.ReadFile(b.path(id))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to read spooled update '%s': %v", id, err)
+		}
+		pending[id] = events.SQSMessage{MessageId: id, Body: string(rawUpdate)}
+	}
+	return nil
+}
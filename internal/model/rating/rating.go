@@ -2,9 +2,17 @@
 package rating
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
 
+// Sender publishes rating updates to the leaderboard processor.
+type Sender interface {
+	SendUpdate(ctx context.Context, update *Update) error
+}
+
+// Model implements the Sender on top of sqs.
 type Model struct {
 	sqsClient *sqs.Client
 	queue     string
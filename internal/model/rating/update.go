@@ -17,9 +17,15 @@ type Update struct {
 	Streak       int64     `json:"streak"`
 	Algorithm    string    `json:"algorithm"`
 	RatingChange float64   `json:"rating_change"`
+
+	// Deleted marks the deletion of the user account; the processor removes the user from all boards and purges the storage.
+	// For deletions Time is the creation time of the account (the first board that can contain the user).
+	Deleted bool   `json:"deleted,omitempty"`
+	Email   string `json:"email,omitempty"`
 }
 
-func (m *Model) ParseUpdate(body string) (*Update, error) {
+// ParseUpdate decodes an update from a queue message body.
+func ParseUpdate(body string) (*Update, error) {
 	update := &Update{}
 	err := json.Unmarshal([]byte(body), update)
 	if err != nil {
@@ -0,0 +1,72 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// AuditEvent records a security relevant action on the account. CreatedAt is in unix milliseconds (sort order),
+// UserAgent and Origin describe the device that triggered the event (informational only).
+type AuditEvent struct {
+	PK        string `dynamodbav:"pk"`
+	SK        string `dynamodbav:"sk"`
+	Id        string `dynamodbav:"id"`
+	Type      string `dynamodbav:"type"`
+	Detail    string `dynamodbav:"detail"`
+	UserAgent string `dynamodbav:"user_agent"`
+	Origin    string `dynamodbav:"origin"`
+	CreatedAt int64  `dynamodbav:"created_at"`
+	ExpiresAt int64  `dynamodbav:"expires_at"`
+}
+
+func (m *Model) ListAuditEvents(ctx context.Context, sub string, since, until time.Time) ([]*AuditEvent, error) {
+	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(m.table),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":    &types.AttributeValueMemberS{Value: userKey(sub)},
+			":since": &types.AttributeValueMemberS{Value: auditKey(since.UnixMilli(), "")},
+			// "~" sorts after the ids, events created in the last millisecond are included.
+			":until": &types.AttributeValueMemberS{Value: auditKey(until.UnixMilli(), "~")},
+		},
+		KeyConditionExpression: aws.String("pk = :pk AND sk BETWEEN :since AND :until"),
+		ScanIndexForward:       aws.Bool(false),
+		Limit:                  aws.Int32(100),
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	events := []*AuditEvent{}
+	for _, item := range result.Items {
+		event := &AuditEvent{}
+		if err := attributevalue.UnmarshalMap(item, event); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (m *Model) PutAuditEvent(ctx context.Context, sub string, event *AuditEvent) error {
+	event.PK = userKey(sub)
+	event.SK = auditKey(event.CreatedAt, event.Id)
+	item, err := attributevalue.MarshalMap(event)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	_, err = m.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(m.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(pk)"),
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) ListAuditEvents(ctx context.Context, sub string, since, until time.Time) ([]*AuditEvent, error) {
+	events := []*AuditEvent{}
+	err := m.table.View(func(tx *bolt.Tx) error {
+		items, err := tx.QueryReverse(userKey(sub), auditKey(since.UnixMilli(), ""), auditKey(until.UnixMilli(), "~"), 100)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			event := &AuditEvent{}
+			if err := item.Decode(event); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return events, nil
+}
+
+func (m *BoltModel) PutAuditEvent(ctx context.Context, sub string, event *AuditEvent) error {
+	event.PK = userKey(sub)
+	event.SK = auditKey(event.CreatedAt, event.Id)
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		exists, err := tx.Exists(event.PK, event.SK)
+		if err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("audit event already exists")
+		}
+		return tx.Put(event.PK, event.SK, event, event.ExpiresAt)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
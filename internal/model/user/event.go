@@ -34,8 +34,8 @@ func (m *Model) GetEvent(ctx context.Context, sub, id string) (*Event, bool, err
 	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
 		TableName: aws.String(m.table),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			":sk": &types.AttributeValueMemberS{Value: fmt.Sprintf("EVENT#%s", id)},
+			":pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			":sk": &types.AttributeValueMemberS{Value: eventKey(id)},
 		},
 		KeyConditionExpression: aws.String("pk = :pk AND sk = :sk"),
 	})
@@ -56,9 +56,9 @@ func (m *Model) ListEvents(ctx context.Context, sub string, since, until time.Ti
 	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
 		TableName: aws.String(m.table),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":pk":    &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			":since": &types.AttributeValueMemberS{Value: fmt.Sprintf("EVENT#%d", since.Unix())},
-			":until": &types.AttributeValueMemberS{Value: fmt.Sprintf("EVENT#%d", until.Unix())},
+			":pk":    &types.AttributeValueMemberS{Value: userKey(sub)},
+			":since": &types.AttributeValueMemberS{Value: eventKey(strconv.FormatInt(since.Unix(), 10))},
+			":until": &types.AttributeValueMemberS{Value: eventKey(strconv.FormatInt(until.Unix(), 10))},
 		},
 		KeyConditionExpression: aws.String("pk = :pk AND sk BETWEEN :since AND :until"),
 		ScanIndexForward:       aws.Bool(true),
@@ -88,8 +88,8 @@ func (m *Model) PutEvents(ctx context.Context, sub string, events []Event, oldEv
 		if oldEvents[newId] {
 			delete(oldEvents, newId)
 		}
-		event.PK = fmt.Sprintf("USER#%s", sub)
-		event.SK = fmt.Sprintf("EVENT#%s", newId)
+		event.PK = userKey(sub)
+		event.SK = eventKey(newId)
 		item, err := attributevalue.MarshalMap(event)
 		if err != nil {
 			return connect.NewError(connect.CodeInvalidArgument, err)
@@ -114,8 +114,8 @@ func (m *Model) PutEvents(ctx context.Context, sub string, events []Event, oldEv
 			Delete: &types.Delete{
 				TableName: aws.String(m.table),
 				Key: map[string]types.AttributeValue{
-					"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-					"sk": &types.AttributeValueMemberS{Value: fmt.Sprintf("EVENT#%s", id)},
+					"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+					"sk": &types.AttributeValueMemberS{Value: eventKey(id)},
 				},
 			},
 		})
@@ -137,8 +137,8 @@ func (m *Model) UpdateEventTimer(ctx context.Context, sub, id string, start, sto
 	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(m.table),
 		Key: map[string]types.AttributeValue{
-			"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			"sk": &types.AttributeValueMemberS{Value: fmt.Sprintf("EVENT#%s", id)},
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: eventKey(id)},
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":timer_start_time": &types.AttributeValueMemberN{Value: strconv.Itoa(int(start.Unix()))},
@@ -171,8 +171,8 @@ func (m *Model) DeleteEvent(ctx context.Context, sub, id string) error {
 	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(m.table),
 		Key: map[string]types.AttributeValue{
-			"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			"sk": &types.AttributeValueMemberS{Value: fmt.Sprintf("EVENT#%s", id)},
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: eventKey(id)},
 		},
 	})
 	if err != nil {
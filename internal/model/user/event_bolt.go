@@ -0,0 +1,132 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) GetEvent(ctx context.Context, sub, id string) (*Event, bool, error) {
+	event := &Event{}
+	var found bool
+	err := m.table.View(func(tx *bolt.Tx) (err error) {
+		found, err = tx.Get(userKey(sub), eventKey(id), event)
+		return err
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	return event, true, nil
+}
+
+func (m *BoltModel) ListEvents(ctx context.Context, sub string, since, until time.Time) ([]*Event, error) {
+	events := []*Event{}
+	err := m.table.View(func(tx *bolt.Tx) error {
+		items, err := tx.Query(userKey(sub),
+			eventKey(strconv.FormatInt(since.Unix(), 10)),
+			eventKey(strconv.FormatInt(until.Unix(), 10)),
+			100,
+		)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			event := &Event{}
+			if err := item.Decode(event); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return events, nil
+}
+
+// errImmutableEvent is used to abort the transaction if an immutable event would be changed.
+var errImmutableEvent = errors.New("immutable event")
+
+// PutEvents inserts all provided events and deletes all specified old events in an all or nothing operation.
+// It ensures that events colliding with oldEvents are not deleted (insert: "events" delete: "oldEvents - events").
+func (m *BoltModel) PutEvents(ctx context.Context, sub string, events []Event, oldEvents map[string]bool) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		for _, event := range events {
+			newId := fmt.Sprintf("%d", event.StartTime)
+			if oldEvents[newId] {
+				delete(oldEvents, newId)
+			}
+			event.PK = userKey(sub)
+			event.SK = eventKey(newId)
+			current := &Event{}
+			found, err := tx.Get(event.PK, event.SK, current)
+			if err != nil {
+				return err
+			} else if found && current.Immutable {
+				return errImmutableEvent
+			}
+			if err := tx.Put(event.PK, event.SK, event, 0); err != nil {
+				return err
+			}
+		}
+		for id, ok := range oldEvents {
+			if !ok || id == "" {
+				continue
+			}
+			if err := tx.Delete(userKey(sub), eventKey(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errImmutableEvent) {
+			return connect.NewError(connect.CodeOutOfRange, fmt.Errorf("cannot change past or immutable events"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) UpdateEventTimer(ctx context.Context, sub, id string, start, stop time.Time, rating float64, ratingAlgorithm string, immutable bool) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		event := &Event{}
+		found, err := tx.Get(userKey(sub), eventKey(id), event)
+		if err != nil {
+			return err
+		} else if !found || event.Immutable {
+			return errImmutableEvent
+		}
+		event.TimerStartTime = start.Unix()
+		event.TimerStopTime = stop.Unix()
+		event.RatingChange, _ = strconv.ParseFloat(strconv.FormatFloat(rating, 'f', 2, 64), 64)
+		event.RatingAlgorithm = ratingAlgorithm
+		event.Immutable = immutable
+		return tx.Put(event.PK, event.SK, event, 0)
+	})
+	if err != nil {
+		if errors.Is(err, errImmutableEvent) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("event is immutable or does not exist"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeleteEvent(ctx context.Context, sub, id string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		return tx.Delete(userKey(sub), eventKey(id))
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
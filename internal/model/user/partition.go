@@ -0,0 +1,88 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// maximum number of requests in one BatchWriteItem call (dynamodb limit).
+	batchWriteLimit = 25
+	// number of attempts to write the unprocessed items of a batch (throttling).
+	batchWriteAttempts = 5
+)
+
+// PurgePartition deletes every item of the user partition (including the audit trail).
+// Items are deleted in batches and the profile last; an interrupted purge is completed by calling it again.
+func (m *Model) PurgePartition(ctx context.Context, sub string) error {
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := m.client.Query(ctx, &dynamodb.QueryInput{
+			TableName: aws.String(m.table),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			},
+			KeyConditionExpression: aws.String("pk = :pk"),
+			ProjectionExpression:   aws.String("pk, sk"),
+			ExclusiveStartKey:      startKey,
+		})
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, err)
+		}
+
+		keys := []map[string]types.AttributeValue{}
+		for _, item := range result.Items {
+			sk, ok := item["sk"].(*types.AttributeValueMemberS)
+			if !ok || sk.Value == profileKey {
+				continue
+			}
+			keys = append(keys, item)
+		}
+		if err := m.deleteItems(ctx, keys); err != nil {
+			return err
+		}
+		if len(result.LastEvaluatedKey) < 1 {
+			break
+		}
+		startKey = result.LastEvaluatedKey
+	}
+	return m.DeleteProfile(ctx, sub)
+}
+
+// deleteItems deletes the items in batches, unprocessed items are retried with a linear backoff.
+func (m *Model) deleteItems(ctx context.Context, keys []map[string]types.AttributeValue) error {
+	for len(keys) > 0 {
+		chunk := keys[:min(len(keys), batchWriteLimit)]
+		keys = keys[len(chunk):]
+
+		requests := make([]types.WriteRequest, 0, len(chunk))
+		for _, key := range chunk {
+			requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}})
+		}
+		for attempt := 1; len(requests) > 0; attempt++ {
+			if attempt > batchWriteAttempts {
+				return connect.NewError(connect.CodeUnavailable, fmt.Errorf("failed to delete %d items: write capacity exceeded", len(requests)))
+			} else if attempt > 1 {
+				select {
+				case <-ctx.Done():
+					return connect.NewError(connect.CodeCanceled, ctx.Err())
+				case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+				}
+			}
+			result, err := m.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]types.WriteRequest{m.table: requests},
+			})
+			if err != nil {
+				return connect.NewError(connect.CodeInternal, err)
+			}
+			requests = result.UnprocessedItems[m.table]
+		}
+	}
+	return nil
+}
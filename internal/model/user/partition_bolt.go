@@ -0,0 +1,27 @@
+package user
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) PurgePartition(ctx context.Context, sub string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		items, err := tx.QueryPrefix(userKey(sub), "", 0)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := tx.Delete(item.PK, item.SK); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
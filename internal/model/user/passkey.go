@@ -0,0 +1,137 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Passkey stores a webauthn credential of the user. Credential holds the json encoded credential record,
+// Email is the address of the user at registration time (passkey logins issue tokens without an email stage).
+type Passkey struct {
+	PK         string `dynamodbav:"pk"`
+	SK         string `dynamodbav:"sk"`
+	Id         string `dynamodbav:"id"`
+	Name       string `dynamodbav:"name"`
+	Email      string `dynamodbav:"email"`
+	Credential string `dynamodbav:"credential"`
+	CreatedAt  int64  `dynamodbav:"created_at"`
+	LastUsedAt int64  `dynamodbav:"last_used_at"`
+}
+
+func (m *Model) ListPasskeys(ctx context.Context, sub string) ([]*Passkey, error) {
+	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(m.table),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			":sk": &types.AttributeValueMemberS{Value: passkeyKey("")},
+		},
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :sk)"),
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	passkeys := []*Passkey{}
+	for _, item := range result.Items {
+		passkey := &Passkey{}
+		if err := attributevalue.UnmarshalMap(item, passkey); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		passkeys = append(passkeys, passkey)
+	}
+	return passkeys, nil
+}
+
+func (m *Model) PutPasskey(ctx context.Context, sub, id string, passkey *Passkey) error {
+	passkey.PK = userKey(sub)
+	passkey.SK = passkeyKey(id)
+	passkey.Id = id
+	item, err := attributevalue.MarshalMap(passkey)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	_, err = m.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(m.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(pk)"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("passkey is already registered"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// UpdatePasskeyUsage replaces the credential record (sign counter and flags) after a successful login.
+func (m *Model) UpdatePasskeyUsage(ctx context.Context, sub, id, credential string, lastUsedAt time.Time) error {
+	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: passkeyKey(id)},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":credential":   &types.AttributeValueMemberS{Value: credential},
+			":last_used_at": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", lastUsedAt.Unix())},
+		},
+		UpdateExpression:    aws.String("SET credential = :credential, last_used_at = :last_used_at"),
+		ConditionExpression: aws.String("attribute_exists(pk)"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("passkey does not exist"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// UpdatePasskeyEmail replaces the email that is issued on passkey logins (used when the user changes the email).
+func (m *Model) UpdatePasskeyEmail(ctx context.Context, sub, id, email string) error {
+	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: passkeyKey(id)},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":email": &types.AttributeValueMemberS{Value: email},
+		},
+		UpdateExpression:    aws.String("SET email = :email"),
+		ConditionExpression: aws.String("attribute_exists(pk)"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("passkey does not exist"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *Model) DeletePasskey(ctx context.Context, sub, id string) error {
+	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: passkeyKey(id)},
+		},
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) ListPasskeys(ctx context.Context, sub string) ([]*Passkey, error) {
+	passkeys := []*Passkey{}
+	err := m.table.View(func(tx *bolt.Tx) error {
+		items, err := tx.QueryPrefix(userKey(sub), passkeyKey(""), 0)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			passkey := &Passkey{}
+			if err := item.Decode(passkey); err != nil {
+				return err
+			}
+			passkeys = append(passkeys, passkey)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return passkeys, nil
+}
+
+// errPasskeyExists is used to abort the transaction if the passkey is already registered.
+var errPasskeyExists = errors.New("passkey exists")
+
+func (m *BoltModel) PutPasskey(ctx context.Context, sub, id string, passkey *Passkey) error {
+	passkey.PK = userKey(sub)
+	passkey.SK = passkeyKey(id)
+	passkey.Id = id
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		exists, err := tx.Exists(passkey.PK, passkey.SK)
+		if err != nil {
+			return err
+		} else if exists {
+			return errPasskeyExists
+		}
+		return tx.Put(passkey.PK, passkey.SK, passkey, 0)
+	})
+	if err != nil {
+		if errors.Is(err, errPasskeyExists) {
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("passkey is already registered"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// errPasskeyNotFound is used to abort the transaction if the passkey does not exist.
+var errPasskeyNotFound = errors.New("passkey not found")
+
+func (m *BoltModel) UpdatePasskeyUsage(ctx context.Context, sub, id, credential string, lastUsedAt time.Time) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		passkey := &Passkey{}
+		found, err := tx.Get(userKey(sub), passkeyKey(id), passkey)
+		if err != nil {
+			return err
+		} else if !found {
+			return errPasskeyNotFound
+		}
+		passkey.Credential = credential
+		passkey.LastUsedAt = lastUsedAt.Unix()
+		return tx.Put(passkey.PK, passkey.SK, passkey, 0)
+	})
+	if err != nil {
+		if errors.Is(err, errPasskeyNotFound) {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("passkey does not exist"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) UpdatePasskeyEmail(ctx context.Context, sub, id, email string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		passkey := &Passkey{}
+		found, err := tx.Get(userKey(sub), passkeyKey(id), passkey)
+		if err != nil {
+			return err
+		} else if !found {
+			return errPasskeyNotFound
+		}
+		passkey.Email = email
+		return tx.Put(passkey.PK, passkey.SK, passkey, 0)
+	})
+	if err != nil {
+		if errors.Is(err, errPasskeyNotFound) {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("passkey does not exist"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeletePasskey(ctx context.Context, sub, id string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		return tx.Delete(userKey(sub), passkeyKey(id))
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
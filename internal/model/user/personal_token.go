@@ -0,0 +1,106 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// PersonalToken registers an issued personal access token (the id is the jti of the token).
+// The token itself is not stored, deleting the item revokes it.
+type PersonalToken struct {
+	PK        string   `dynamodbav:"pk"`
+	SK        string   `dynamodbav:"sk"`
+	Id        string   `dynamodbav:"id"`
+	Name      string   `dynamodbav:"name"`
+	Scopes    []string `dynamodbav:"scopes,stringset"`
+	CreatedAt int64    `dynamodbav:"created_at"`
+	ExpiresAt int64    `dynamodbav:"expires_at"`
+}
+
+func (m *Model) ListPersonalTokens(ctx context.Context, sub string) ([]*PersonalToken, error) {
+	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(m.table),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":  &types.AttributeValueMemberS{Value: userKey(sub)},
+			":sk":  &types.AttributeValueMemberS{Value: personalTokenKey("")},
+			":now": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
+		},
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :sk)"),
+		// ttl deletion is lazy, expired tokens can be around for a while.
+		FilterExpression: aws.String("expires_at > :now"),
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	tokens := []*PersonalToken{}
+	for _, item := range result.Items {
+		token := &PersonalToken{}
+		if err := attributevalue.UnmarshalMap(item, token); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func (m *Model) ExistsPersonalToken(ctx context.Context, sub, id string) (bool, error) {
+	result, err := m.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: personalTokenKey(id)},
+		},
+		ProjectionExpression: aws.String("expires_at"),
+	})
+	if err != nil {
+		return false, connect.NewError(connect.CodeInternal, err)
+	} else if result.Item == nil {
+		return false, nil
+	}
+	token := &PersonalToken{}
+	if err := attributevalue.UnmarshalMap(result.Item, token); err != nil {
+		return false, connect.NewError(connect.CodeInternal, err)
+	}
+	return token.ExpiresAt > time.Now().Unix(), nil
+}
+
+func (m *Model) PutPersonalToken(ctx context.Context, sub, id string, token *PersonalToken) error {
+	token.PK = userKey(sub)
+	token.SK = personalTokenKey(id)
+	token.Id = id
+	item, err := attributevalue.MarshalMap(token)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	_, err = m.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(m.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(pk)"),
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *Model) DeletePersonalToken(ctx context.Context, sub, id string) error {
+	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: personalTokenKey(id)},
+		},
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
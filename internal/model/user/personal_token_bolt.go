@@ -0,0 +1,72 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) ListPersonalTokens(ctx context.Context, sub string) ([]*PersonalToken, error) {
+	tokens := []*PersonalToken{}
+	err := m.table.View(func(tx *bolt.Tx) error {
+		items, err := tx.QueryPrefix(userKey(sub), personalTokenKey(""), 0)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			token := &PersonalToken{}
+			if err := item.Decode(token); err != nil {
+				return err
+			}
+			tokens = append(tokens, token)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return tokens, nil
+}
+
+func (m *BoltModel) ExistsPersonalToken(ctx context.Context, sub, id string) (bool, error) {
+	var exists bool
+	err := m.table.View(func(tx *bolt.Tx) (err error) {
+		exists, err = tx.Exists(userKey(sub), personalTokenKey(id))
+		return err
+	})
+	if err != nil {
+		return false, connect.NewError(connect.CodeInternal, err)
+	}
+	return exists, nil
+}
+
+func (m *BoltModel) PutPersonalToken(ctx context.Context, sub, id string, token *PersonalToken) error {
+	token.PK = userKey(sub)
+	token.SK = personalTokenKey(id)
+	token.Id = id
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		exists, err := tx.Exists(token.PK, token.SK)
+		if err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("token already exists")
+		}
+		return tx.Put(token.PK, token.SK, token, token.ExpiresAt)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeletePersonalToken(ctx context.Context, sub, id string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		return tx.Delete(userKey(sub), personalTokenKey(id))
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
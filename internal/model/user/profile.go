@@ -28,8 +28,8 @@ func (m *Model) GetProfile(ctx context.Context, sub string) (*Profile, bool, err
 	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
 		TableName: aws.String(m.table),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			":sk": &types.AttributeValueMemberS{Value: "PROFILE"},
+			":pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			":sk": &types.AttributeValueMemberS{Value: profileKey},
 		},
 		KeyConditionExpression: aws.String("pk = :pk AND sk = :sk"),
 	})
@@ -47,8 +47,8 @@ func (m *Model) GetProfile(ctx context.Context, sub string) (*Profile, bool, err
 }
 
 func (m *Model) PutProfile(ctx context.Context, sub string, profile *Profile) error {
-	profile.PK = fmt.Sprintf("USER#%s", sub)
-	profile.SK = "PROFILE"
+	profile.PK = userKey(sub)
+	profile.SK = profileKey
 	item, err := attributevalue.MarshalMap(profile)
 	if err != nil {
 		return connect.NewError(connect.CodeInvalidArgument, err)
@@ -68,8 +68,8 @@ func (m *Model) UpdateProfile(ctx context.Context, sub string, profile *Profile)
 	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(m.table),
 		Key: map[string]types.AttributeValue{
-			"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			"sk": &types.AttributeValueMemberS{Value: "PROFILE"},
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: profileKey},
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":username":    &types.AttributeValueMemberS{Value: profile.Username},
@@ -93,8 +93,8 @@ func (m *Model) UpdateProfileRating(ctx context.Context, sub string, ratingChang
 	result, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(m.table),
 		Key: map[string]types.AttributeValue{
-			"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			"sk": &types.AttributeValueMemberS{Value: "PROFILE"},
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: profileKey},
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":rating_change": &types.AttributeValueMemberN{Value: strconv.FormatFloat(ratingChange, 'f', 10, 64)},
@@ -116,8 +116,8 @@ func (m *Model) UpdateProfileRating(ctx context.Context, sub string, ratingChang
 	_, err = m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(m.table),
 		Key: map[string]types.AttributeValue{
-			"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			"sk": &types.AttributeValueMemberS{Value: "PROFILE"},
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: profileKey},
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":streak": result.Attributes["streak"],
@@ -132,8 +132,8 @@ func (m *Model) DeleteProfile(ctx context.Context, sub string) error {
 	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(m.table),
 		Key: map[string]types.AttributeValue{
-			"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("USER#%s", sub)},
-			"sk": &types.AttributeValueMemberS{Value: "PROFILE"},
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: profileKey},
 		},
 	})
 	if err != nil {
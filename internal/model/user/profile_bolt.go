@@ -0,0 +1,98 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) GetProfile(ctx context.Context, sub string) (*Profile, bool, error) {
+	profile := &Profile{}
+	var found bool
+	err := m.table.View(func(tx *bolt.Tx) (err error) {
+		found, err = tx.Get(userKey(sub), profileKey, profile)
+		return err
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	return profile, true, nil
+}
+
+func (m *BoltModel) PutProfile(ctx context.Context, sub string, profile *Profile) error {
+	profile.PK = userKey(sub)
+	profile.SK = profileKey
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		exists, err := tx.Exists(profile.PK, profile.SK)
+		if err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("profile already exists")
+		}
+		return tx.Put(profile.PK, profile.SK, profile, 0)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) UpdateProfile(ctx context.Context, sub string, profile *Profile) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		current := &Profile{}
+		found, err := tx.Get(userKey(sub), profileKey, current)
+		if err != nil {
+			return err
+		} else if !found {
+			return fmt.Errorf("profile does not exist")
+		}
+		current.Username = profile.Username
+		current.Description = profile.Description
+		current.Leaderboard = profile.Leaderboard
+		return tx.Put(current.PK, current.SK, current, 0)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) UpdateProfileRating(ctx context.Context, sub string, ratingChange float64) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		current := &Profile{}
+		found, err := tx.Get(userKey(sub), profileKey, current)
+		if err != nil {
+			return err
+		} else if !found {
+			return fmt.Errorf("profile does not exist")
+		}
+		current.Score += ratingChange
+		if ratingChange < 0 {
+			current.Streak = 0 // reset streak if negative
+		} else {
+			current.Streak++
+		}
+		if current.Streak > current.MaxStreak {
+			current.MaxStreak = current.Streak
+		}
+		return tx.Put(current.PK, current.SK, current, 0)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeleteProfile(ctx context.Context, sub string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		return tx.Delete(userKey(sub), profileKey)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
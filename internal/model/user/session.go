@@ -0,0 +1,148 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Session tracks a refresh token family. Token holds the jti of the only refresh token
+// of the family that is currently valid, every refresh rotates it.
+// PreviousToken holds the jti that was replaced by the last rotation at RotatedAt (concurrent refreshes).
+// UserAgent and Origin describe the device that last used the session (informational only).
+type Session struct {
+	PK            string `dynamodbav:"pk"`
+	SK            string `dynamodbav:"sk"`
+	Id            string `dynamodbav:"id"`
+	Token         string `dynamodbav:"token"`
+	PreviousToken string `dynamodbav:"previous_token"`
+	RotatedAt     int64  `dynamodbav:"rotated_at"`
+	UserAgent     string `dynamodbav:"user_agent"`
+	Origin        string `dynamodbav:"origin"`
+	CreatedAt     int64  `dynamodbav:"created_at"`
+	LastUsedAt    int64  `dynamodbav:"last_used_at"`
+	ExpiresAt     int64  `dynamodbav:"expires_at"`
+}
+
+// RotationGracePeriod is the time the previous token of a session is still accepted after a rotation.
+// Tabs that refresh concurrently present the same token, only the first one is rotated.
+const RotationGracePeriod = 10 * time.Second
+
+func (m *Model) ListSessions(ctx context.Context, sub string) ([]*Session, error) {
+	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(m.table),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":  &types.AttributeValueMemberS{Value: userKey(sub)},
+			":sk":  &types.AttributeValueMemberS{Value: sessionKey("")},
+			":now": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
+		},
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :sk)"),
+		// ttl deletion is lazy, expired sessions can be around for a while.
+		FilterExpression: aws.String("expires_at > :now"),
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	sessions := []*Session{}
+	for _, item := range result.Items {
+		session := &Session{}
+		if err := attributevalue.UnmarshalMap(item, session); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (m *Model) PutSession(ctx context.Context, sub, id string, session *Session) error {
+	session.PK = userKey(sub)
+	session.SK = sessionKey(id)
+	session.Id = id
+	item, err := attributevalue.MarshalMap(session)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	_, err = m.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(m.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(pk)"),
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// RotateSession replaces the current token of the session with the token of next (compare-and-swap).
+// The expiration and the usage information (user agent, origin, last use) are taken from next as well.
+// Returns an Aborted error if the current token was rotated within the RotationGracePeriod (the session is unchanged)
+// and a FailedPrecondition error if the session does not exist (anymore) or the current token does not match.
+func (m *Model) RotateSession(ctx context.Context, sub, id, currentToken string, next *Session) error {
+	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: sessionKey(id)},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":current":      &types.AttributeValueMemberS{Value: currentToken},
+			":new":          &types.AttributeValueMemberS{Value: next.Token},
+			":user_agent":   &types.AttributeValueMemberS{Value: next.UserAgent},
+			":origin":       &types.AttributeValueMemberS{Value: next.Origin},
+			":last_used_at": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", next.LastUsedAt)},
+			":expires_at":   &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", next.ExpiresAt)},
+			":now":          &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", time.Now().Unix())},
+		},
+		UpdateExpression:    aws.String("SET #token = :new, previous_token = :current, rotated_at = :now, user_agent = :user_agent, origin = :origin, last_used_at = :last_used_at, expires_at = :expires_at"),
+		ConditionExpression: aws.String("#token = :current AND expires_at > :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#token": "token",
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			session := &Session{}
+			if err := attributevalue.UnmarshalMap(cErr.Item, session); err != nil {
+				return connect.NewError(connect.CodeInternal, err)
+			}
+			return sessionMismatchError(session, currentToken)
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// sessionMismatchError returns the error that describes why the rotation of the session was rejected.
+// The session is a zero value if no session exists.
+func sessionMismatchError(session *Session, currentToken string) error {
+	now := time.Now()
+	if session.ExpiresAt > now.Unix() && session.PreviousToken == currentToken &&
+		session.RotatedAt > now.Add(-RotationGracePeriod).Unix() {
+		return connect.NewError(connect.CodeAborted, fmt.Errorf("token was rotated by a concurrent refresh"))
+	}
+	return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("session is revoked or token was already used"))
+}
+
+func (m *Model) DeleteSession(ctx context.Context, sub, id string) error {
+	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: sessionKey(id)},
+		},
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) ListSessions(ctx context.Context, sub string) ([]*Session, error) {
+	sessions := []*Session{}
+	err := m.table.View(func(tx *bolt.Tx) error {
+		items, err := tx.QueryPrefix(userKey(sub), sessionKey(""), 0)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			session := &Session{}
+			if err := item.Decode(session); err != nil {
+				return err
+			}
+			sessions = append(sessions, session)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return sessions, nil
+}
+
+func (m *BoltModel) PutSession(ctx context.Context, sub, id string, session *Session) error {
+	session.PK = userKey(sub)
+	session.SK = sessionKey(id)
+	session.Id = id
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		exists, err := tx.Exists(session.PK, session.SK)
+		if err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("session already exists")
+		}
+		return tx.Put(session.PK, session.SK, session, session.ExpiresAt)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// errSessionMismatch is used to abort the transaction if the session token does not match.
+var errSessionMismatch = errors.New("session mismatch")
+
+func (m *BoltModel) RotateSession(ctx context.Context, sub, id, currentToken string, next *Session) error {
+	session := &Session{}
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		found, err := tx.Get(userKey(sub), sessionKey(id), session)
+		if err != nil {
+			return err
+		} else if !found || session.Token != currentToken {
+			return errSessionMismatch
+		}
+		session.PreviousToken = session.Token
+		session.RotatedAt = time.Now().Unix()
+		session.Token = next.Token
+		session.UserAgent = next.UserAgent
+		session.Origin = next.Origin
+		session.LastUsedAt = next.LastUsedAt
+		session.ExpiresAt = next.ExpiresAt
+		return tx.Put(session.PK, session.SK, session, session.ExpiresAt)
+	})
+	if err != nil {
+		if errors.Is(err, errSessionMismatch) {
+			return sessionMismatchError(session, currentToken)
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeleteSession(ctx context.Context, sub, id string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		return tx.Delete(userKey(sub), sessionKey(id))
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
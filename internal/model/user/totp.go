@@ -0,0 +1,226 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Totp holds the totp second factor of the user, it is only enforced once the enrollment is confirmed (Enabled).
+// RecoveryCodes contains the hashes of the unused recovery codes, LastStep is the last accepted time step (replay protection).
+type Totp struct {
+	PK            string   `dynamodbav:"pk"`
+	SK            string   `dynamodbav:"sk"`
+	Secret        string   `dynamodbav:"secret"`
+	Enabled       bool     `dynamodbav:"enabled"`
+	RecoveryCodes []string `dynamodbav:"recovery_codes,stringset,omitempty"`
+	LastStep      int64    `dynamodbav:"last_step"`
+	Failures      int64    `dynamodbav:"failures"`
+	LockedUntil   int64    `dynamodbav:"locked_until"`
+	CreatedAt     int64    `dynamodbav:"created_at"`
+}
+
+func (m *Model) GetTotp(ctx context.Context, sub string) (*Totp, bool, error) {
+	result, err := m.client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(m.table),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			":sk": &types.AttributeValueMemberS{Value: totpKey},
+		},
+		KeyConditionExpression: aws.String("pk = :pk AND sk = :sk"),
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if len(result.Items) < 1 {
+		return nil, false, nil
+	}
+
+	totp := &Totp{}
+	if err := attributevalue.UnmarshalMap(result.Items[0], totp); err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	}
+	return totp, true, nil
+}
+
+// PutTotp stores a new (not yet enabled) enrollment, pending enrollments are replaced.
+func (m *Model) PutTotp(ctx context.Context, sub string, totp *Totp) error {
+	totp.PK = userKey(sub)
+	totp.SK = totpKey
+	item, err := attributevalue.MarshalMap(totp)
+	if err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	_, err = m.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(m.table),
+		Item:      item,
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":enabled": &types.AttributeValueMemberBOOL{Value: true},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(pk) OR enabled <> :enabled"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("totp is already enabled"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// EnableTotp confirms the pending enrollment with the first accepted step and sets the recovery codes.
+func (m *Model) EnableTotp(ctx context.Context, sub string, step int64, recoveryCodes []string) error {
+	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: totpKey},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":enabled":        &types.AttributeValueMemberBOOL{Value: true},
+			":step":           &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", step)},
+			":recovery_codes": &types.AttributeValueMemberSS{Value: recoveryCodes},
+			":zero":           &types.AttributeValueMemberN{Value: "0"},
+		},
+		UpdateExpression:    aws.String("SET enabled = :enabled, last_step = :step, recovery_codes = :recovery_codes, failures = :zero"),
+		ConditionExpression: aws.String("attribute_exists(pk) AND enabled <> :enabled"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("totp is not enrolled or already enabled"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// UseTotpStep accepts the time step if it is newer than the last accepted step (compare-and-swap) and resets the failures.
+// Returns a FailedPrecondition error if the step was already used.
+func (m *Model) UseTotpStep(ctx context.Context, sub string, step int64) error {
+	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: totpKey},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":enabled": &types.AttributeValueMemberBOOL{Value: true},
+			":step":    &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", step)},
+			":zero":    &types.AttributeValueMemberN{Value: "0"},
+		},
+		UpdateExpression:    aws.String("SET last_step = :step, failures = :zero"),
+		ConditionExpression: aws.String("enabled = :enabled AND last_step < :step"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("code was already used; wait for the next code"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// UseTotpRecoveryCode removes the recovery code (hash) if it is unused and resets the failures.
+// Returns a PermissionDenied error if the recovery code does not exist.
+func (m *Model) UseTotpRecoveryCode(ctx context.Context, sub, recoveryCode string) error {
+	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: totpKey},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":enabled":       &types.AttributeValueMemberBOOL{Value: true},
+			":recovery_code": &types.AttributeValueMemberS{Value: recoveryCode},
+			":recovery_set":  &types.AttributeValueMemberSS{Value: []string{recoveryCode}},
+			":zero":          &types.AttributeValueMemberN{Value: "0"},
+		},
+		UpdateExpression:    aws.String("DELETE recovery_codes :recovery_set SET failures = :zero"),
+		ConditionExpression: aws.String("enabled = :enabled AND contains(recovery_codes, :recovery_code)"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("incorrect recovery code - permission denied"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// AddTotpFailure atomically increments the failure counter. Returns the updated totp.
+func (m *Model) AddTotpFailure(ctx context.Context, sub string) (*Totp, error) {
+	result, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: totpKey},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":zero": &types.AttributeValueMemberN{Value: "0"},
+			":one":  &types.AttributeValueMemberN{Value: "1"},
+		},
+		UpdateExpression:    aws.String("SET failures = if_not_exists(failures, :zero) + :one"),
+		ConditionExpression: aws.String("attribute_exists(pk)"),
+		ReturnValues:        types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("totp is not enrolled"))
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	totp := &Totp{}
+	if err := attributevalue.UnmarshalMap(result.Attributes, totp); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return totp, nil
+}
+
+func (m *Model) LockTotp(ctx context.Context, sub string, until time.Time) error {
+	_, err := m.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: totpKey},
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":locked_until": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", until.Unix())},
+		},
+		UpdateExpression:    aws.String("SET locked_until = :locked_until"),
+		ConditionExpression: aws.String("attribute_exists(pk)"),
+	})
+	if err != nil {
+		var cErr *types.ConditionalCheckFailedException
+		if errors.As(err, &cErr) {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("totp is not enrolled"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *Model) DeleteTotp(ctx context.Context, sub string) error {
+	_, err := m.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(m.table),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: userKey(sub)},
+			"sk": &types.AttributeValueMemberS{Value: totpKey},
+		},
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/bolt"
+)
+
+func (m *BoltModel) GetTotp(ctx context.Context, sub string) (*Totp, bool, error) {
+	totp := &Totp{}
+	var found bool
+	err := m.table.View(func(tx *bolt.Tx) (err error) {
+		found, err = tx.Get(userKey(sub), totpKey, totp)
+		return err
+	})
+	if err != nil {
+		return nil, false, connect.NewError(connect.CodeInternal, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	return totp, true, nil
+}
+
+// errTotpMismatch is used to abort the transaction if the totp is not in the expected state.
+var errTotpMismatch = errors.New("totp mismatch")
+
+func (m *BoltModel) PutTotp(ctx context.Context, sub string, totp *Totp) error {
+	totp.PK = userKey(sub)
+	totp.SK = totpKey
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		current := &Totp{}
+		found, err := tx.Get(totp.PK, totp.SK, current)
+		if err != nil {
+			return err
+		} else if found && current.Enabled {
+			return errTotpMismatch
+		}
+		return tx.Put(totp.PK, totp.SK, totp, 0)
+	})
+	if err != nil {
+		if errors.Is(err, errTotpMismatch) {
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("totp is already enabled"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) EnableTotp(ctx context.Context, sub string, step int64, recoveryCodes []string) error {
+	err := m.updateTotp(sub, func(totp *Totp) error {
+		if totp.Enabled {
+			return errTotpMismatch
+		}
+		totp.Enabled = true
+		totp.LastStep = step
+		totp.RecoveryCodes = recoveryCodes
+		totp.Failures = 0
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errTotpMismatch) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("totp is not enrolled or already enabled"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) UseTotpStep(ctx context.Context, sub string, step int64) error {
+	err := m.updateTotp(sub, func(totp *Totp) error {
+		if !totp.Enabled || totp.LastStep >= step {
+			return errTotpMismatch
+		}
+		totp.LastStep = step
+		totp.Failures = 0
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errTotpMismatch) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("code was already used; wait for the next code"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) UseTotpRecoveryCode(ctx context.Context, sub, recoveryCode string) error {
+	err := m.updateTotp(sub, func(totp *Totp) error {
+		idx := slices.Index(totp.RecoveryCodes, recoveryCode)
+		if !totp.Enabled || idx < 0 {
+			return errTotpMismatch
+		}
+		totp.RecoveryCodes = slices.Delete(totp.RecoveryCodes, idx, idx+1)
+		totp.Failures = 0
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errTotpMismatch) {
+			return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("incorrect recovery code - permission denied"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) AddTotpFailure(ctx context.Context, sub string) (*Totp, error) {
+	var updated *Totp
+	err := m.updateTotp(sub, func(totp *Totp) error {
+		totp.Failures++
+		updated = totp
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errTotpMismatch) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("totp is not enrolled"))
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return updated, nil
+}
+
+func (m *BoltModel) LockTotp(ctx context.Context, sub string, until time.Time) error {
+	err := m.updateTotp(sub, func(totp *Totp) error {
+		totp.LockedUntil = until.Unix()
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errTotpMismatch) {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("totp is not enrolled"))
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (m *BoltModel) DeleteTotp(ctx context.Context, sub string) error {
+	err := m.table.Update(func(tx *bolt.Tx) error {
+		return tx.Delete(userKey(sub), totpKey)
+	})
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+// updateTotp applies fn to the stored totp in one transaction, errTotpMismatch is returned if no totp exists.
+func (m *BoltModel) updateTotp(sub string, fn func(totp *Totp) error) error {
+	return m.table.Update(func(tx *bolt.Tx) error {
+		totp := &Totp{}
+		found, err := tx.Get(userKey(sub), totpKey, totp)
+		if err != nil {
+			return err
+		} else if !found {
+			return errTotpMismatch
+		}
+		if err := fn(totp); err != nil {
+			return err
+		}
+		return tx.Put(totp.PK, totp.SK, totp, 0)
+	})
+}
@@ -1,10 +1,94 @@
-// package user provides an application aware wrapper for the required dynamodb communication on the user model.
+// package user provides an application aware wrapper for the required storage communication on the user model.
 package user
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
 
+// ProfileStore provides access to the user profile (USER#<sub> -> PROFILE).
+type ProfileStore interface {
+	GetProfile(ctx context.Context, sub string) (*Profile, bool, error)
+	PutProfile(ctx context.Context, sub string, profile *Profile) error
+	UpdateProfile(ctx context.Context, sub string, profile *Profile) error
+	UpdateProfileRating(ctx context.Context, sub string, ratingChange float64) error
+	DeleteProfile(ctx context.Context, sub string) error
+}
+
+// EventStore provides access to the planned events of a user (USER#<sub> -> EVENT#<id>).
+type EventStore interface {
+	GetEvent(ctx context.Context, sub, id string) (*Event, bool, error)
+	ListEvents(ctx context.Context, sub string, since, until time.Time) ([]*Event, error)
+	PutEvents(ctx context.Context, sub string, events []Event, oldEvents map[string]bool) error
+	UpdateEventTimer(ctx context.Context, sub, id string, start, stop time.Time, rating float64, ratingAlgorithm string, immutable bool) error
+	DeleteEvent(ctx context.Context, sub, id string) error
+}
+
+// SessionStore provides access to the refresh token families of a user (USER#<sub> -> SESSION#<id>).
+type SessionStore interface {
+	ListSessions(ctx context.Context, sub string) ([]*Session, error)
+	PutSession(ctx context.Context, sub, id string, session *Session) error
+	RotateSession(ctx context.Context, sub, id, currentToken string, next *Session) error
+	DeleteSession(ctx context.Context, sub, id string) error
+}
+
+// PasskeyStore provides access to the webauthn credentials of a user (USER#<sub> -> PASSKEY#<id>).
+type PasskeyStore interface {
+	ListPasskeys(ctx context.Context, sub string) ([]*Passkey, error)
+	PutPasskey(ctx context.Context, sub, id string, passkey *Passkey) error
+	UpdatePasskeyUsage(ctx context.Context, sub, id, credential string, lastUsedAt time.Time) error
+	UpdatePasskeyEmail(ctx context.Context, sub, id, email string) error
+	DeletePasskey(ctx context.Context, sub, id string) error
+}
+
+// TotpStore provides access to the totp second factor of a user (USER#<sub> -> TOTP).
+type TotpStore interface {
+	GetTotp(ctx context.Context, sub string) (*Totp, bool, error)
+	PutTotp(ctx context.Context, sub string, totp *Totp) error
+	EnableTotp(ctx context.Context, sub string, step int64, recoveryCodes []string) error
+	UseTotpStep(ctx context.Context, sub string, step int64) error
+	UseTotpRecoveryCode(ctx context.Context, sub, recoveryCode string) error
+	AddTotpFailure(ctx context.Context, sub string) (*Totp, error)
+	LockTotp(ctx context.Context, sub string, until time.Time) error
+	DeleteTotp(ctx context.Context, sub string) error
+}
+
+// PersonalTokenStore provides access to the personal access tokens of a user (USER#<sub> -> TOKEN#<id>).
+type PersonalTokenStore interface {
+	ListPersonalTokens(ctx context.Context, sub string) ([]*PersonalToken, error)
+	ExistsPersonalToken(ctx context.Context, sub, id string) (bool, error)
+	PutPersonalToken(ctx context.Context, sub, id string, token *PersonalToken) error
+	DeletePersonalToken(ctx context.Context, sub, id string) error
+}
+
+// AuditStore provides access to the audit trail of a user (USER#<sub> -> AUDIT#<created_at>#<id>).
+// The trail is append-only, events are removed by the table ttl. Lists return the latest 100 events of the range (newest first).
+type AuditStore interface {
+	ListAuditEvents(ctx context.Context, sub string, since, until time.Time) ([]*AuditEvent, error)
+	PutAuditEvent(ctx context.Context, sub string, event *AuditEvent) error
+}
+
+// PartitionStore provides bulk access to the whole user partition (USER#<sub> -> *).
+type PartitionStore interface {
+	PurgePartition(ctx context.Context, sub string) error
+}
+
+// Store combines all stores of the user partition.
+type Store interface {
+	ProfileStore
+	EventStore
+	SessionStore
+	PasskeyStore
+	TotpStore
+	PersonalTokenStore
+	AuditStore
+	PartitionStore
+}
+
+// Model implements the Store on top of dynamodb.
 type Model struct {
 	client *dynamodb.Client
 	table  string
@@ -13,3 +97,34 @@ type Model struct {
 func New(client *dynamodb.Client, table string) *Model {
 	return &Model{client, table}
 }
+
+func userKey(sub string) string {
+	return fmt.Sprintf("USER#%s", sub)
+}
+
+func eventKey(id string) string {
+	return fmt.Sprintf("EVENT#%s", id)
+}
+
+func sessionKey(id string) string {
+	return fmt.Sprintf("SESSION#%s", id)
+}
+
+func passkeyKey(id string) string {
+	return fmt.Sprintf("PASSKEY#%s", id)
+}
+
+func personalTokenKey(id string) string {
+	return fmt.Sprintf("TOKEN#%s", id)
+}
+
+// auditKey sorts the audit events chronologically (zero padded unix milliseconds).
+func auditKey(createdAt int64, id string) string {
+	return fmt.Sprintf("%s%013d#%s", auditPrefix, createdAt, id)
+}
+
+const (
+	profileKey  = "PROFILE"
+	totpKey     = "TOTP"
+	auditPrefix = "AUDIT#"
+)
@@ -0,0 +1,147 @@
+// package oidc implements the openid connect login (authorization code flow with pkce) against an external identity provider.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/megakuul/zen/internal/model/ceremony"
+	"golang.org/x/oauth2"
+)
+
+const (
+	ceremonyTTL = 10 * time.Minute
+
+	loginCeremony = "OIDC_LOGIN"
+)
+
+type Controller struct {
+	verifier      *gooidc.IDTokenVerifier
+	config        *oauth2.Config
+	ceremonyModel ceremony.Store
+}
+
+// New discovers the identity provider (issuer/.well-known/openid-configuration) and creates an oidc controller.
+// The redirectUrl must be registered at the provider, it receives the authorization code and state (see FinishLogin).
+func New(ctx context.Context, issuer, clientId, clientSecret, redirectUrl string, ceremonies ceremony.Store) (*Controller, error) {
+	provider, err := gooidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	return &Controller{
+		verifier: provider.Verifier(&gooidc.Config{ClientID: clientId}),
+		config: &oauth2.Config{
+			ClientID:     clientId,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectUrl,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{gooidc.ScopeOpenID, "email"},
+		},
+		ceremonyModel: ceremonies,
+	}, nil
+}
+
+// loginState is the server-side state of the authorization request, the ceremony id is used as oauth2 state.
+type loginState struct {
+	Verifier    string `json:"verifier"`
+	Nonce       string `json:"nonce"`
+	AutoRefresh bool   `json:"auto_refresh"`
+	// Device is the hash of the device secret, the state is only accepted together with the secret.
+	Device string `json:"device"`
+}
+
+// Identity is the verified identity returned by the provider.
+type Identity struct {
+	Subject     string
+	Email       string
+	AutoRefresh bool
+}
+
+// BeginLogin starts the authorization request. Returns the provider url the user agent must be redirected to,
+// the device secret that must be presented together with the state (see FinishLogin) and its expiration.
+// The secret binds the state to the user agent that started the login (prevents login csrf).
+func (c *Controller) BeginLogin(ctx context.Context, autoRefresh bool) (string, string, time.Time, error) {
+	device := rand.Text()
+	state := &loginState{
+		Verifier:    oauth2.GenerateVerifier(),
+		Nonce:       rand.Text(),
+		AutoRefresh: autoRefresh,
+		Device:      hashDevice(device),
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", "", time.Time{}, connect.NewError(connect.CodeInternal, err)
+	}
+	id := rand.Text()
+	expiresAt := time.Now().Add(ceremonyTTL)
+	err = c.ceremonyModel.PutCeremony(ctx, loginCeremony, id, &ceremony.Ceremony{
+		Data:      string(data),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return c.config.AuthCodeURL(id, oauth2.S256ChallengeOption(state.Verifier), gooidc.Nonce(state.Nonce)), device, expiresAt, nil
+}
+
+// FinishLogin exchanges the authorization code and validates the returned id token.
+// The state is only accepted from the user agent that started the login (device secret).
+// Only identities with an email verified by the provider are accepted.
+func (c *Controller) FinishLogin(ctx context.Context, id, code, device string) (*Identity, error) {
+	cer, found, err := c.ceremonyModel.ConsumeCeremony(ctx, loginCeremony, id)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("ceremony does not exist or has expired"))
+	}
+	state := &loginState{}
+	if err := json.Unmarshal([]byte(cer.Data), state); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashDevice(device)), []byte(state.Device)) != 1 {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("login must be finished on the device that started it"))
+	}
+
+	oauthToken, err := c.config.Exchange(ctx, code, oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("authorization code exchange failed: %v", err))
+	}
+	rawIdToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok || rawIdToken == "" {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("provider did not return an id_token"))
+	}
+	idToken, err := c.verifier.Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("invalid id_token: %v", err))
+	} else if idToken.Nonce != state.Nonce {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("invalid id_token: nonce mismatch"))
+	}
+
+	claims := struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("invalid id_token claims: %v", err))
+	} else if claims.Email == "" || !claims.EmailVerified {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("provider did not return a verified email"))
+	}
+	return &Identity{
+		Subject:     idToken.Subject,
+		Email:       claims.Email,
+		AutoRefresh: state.AutoRefresh,
+	}, nil
+}
+
+func hashDevice(device string) string {
+	hash := sha256.Sum256([]byte(device))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,282 @@
+// package passkey implements the webauthn registration and login ceremonies.
+package passkey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/megakuul/zen/internal/model/ceremony"
+	"github.com/megakuul/zen/internal/model/user"
+)
+
+const (
+	ceremonyTTL = 5 * time.Minute
+
+	registrationCeremony = "PASSKEY_REGISTRATION"
+	loginCeremony        = "PASSKEY_LOGIN"
+)
+
+type Controller struct {
+	webauthn      *webauthn.WebAuthn
+	ceremonyModel ceremony.Store
+	passkeyModel  user.PasskeyStore
+}
+
+// New creates a passkey controller for the relying party. The rpId is the domain the passkeys are bound to,
+// rpOrigins lists the full origins (e.g. https://zen.example) the browser ceremonies are executed on.
+func New(rpId, rpName string, rpOrigins []string, ceremonies ceremony.Store, passkeys user.PasskeyStore) (*Controller, error) {
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          rpId,
+		RPDisplayName: rpName,
+		RPOrigins:     rpOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: ceremonyTTL},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: ceremonyTTL},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Controller{
+		webauthn:      wa,
+		ceremonyModel: ceremonies,
+		passkeyModel:  passkeys,
+	}, nil
+}
+
+// BeginRegistration starts the creation of a new passkey for the user.
+// Returns the ceremony id and the json encoded credential creation options for navigator.credentials.create().
+func (c *Controller) BeginRegistration(ctx context.Context, sub, email string) (string, []byte, error) {
+	acc, err := c.loadAccount(ctx, sub, email)
+	if err != nil {
+		return "", nil, err
+	}
+	exclusions := []protocol.CredentialDescriptor{}
+	for _, credential := range acc.credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+	creation, session, err := c.webauthn.BeginRegistration(acc,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(exclusions),
+	)
+	if err != nil {
+		return "", nil, connect.NewError(connect.CodeInternal, err)
+	}
+	id, err := c.putCeremony(ctx, registrationCeremony, sub, session)
+	if err != nil {
+		return "", nil, err
+	}
+	options, err := json.Marshal(creation)
+	if err != nil {
+		return "", nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return id, options, nil
+}
+
+// FinishRegistration verifies the json encoded public key credential returned by the browser and stores the passkey.
+func (c *Controller) FinishRegistration(ctx context.Context, sub, email, ceremonyId, name string, response []byte) (*user.Passkey, error) {
+	session, err := c.consumeCeremony(ctx, registrationCeremony, sub, ceremonyId)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid passkey credential: %v", err))
+	}
+	acc, err := c.loadAccount(ctx, sub, email)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := c.webauthn.CreateCredential(acc, *session, parsed)
+	if err != nil {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("passkey verification failed: %v", err))
+	}
+	record, err := json.Marshal(credential)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	passkey := &user.Passkey{
+		Name:       name,
+		Email:      email,
+		Credential: string(record),
+		CreatedAt:  time.Now().Unix(),
+		LastUsedAt: time.Now().Unix(),
+	}
+	if err := c.passkeyModel.PutPasskey(ctx, sub, passkeyId(credential.ID), passkey); err != nil {
+		return nil, err
+	}
+	return passkey, nil
+}
+
+// BeginLogin starts a discoverable login (the user is identified by the passkey).
+// Returns the ceremony id and the json encoded credential request options for navigator.credentials.get().
+func (c *Controller) BeginLogin(ctx context.Context) (string, []byte, error) {
+	assertion, session, err := c.webauthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return "", nil, connect.NewError(connect.CodeInternal, err)
+	}
+	id, err := c.putCeremony(ctx, loginCeremony, "", session)
+	if err != nil {
+		return "", nil, err
+	}
+	options, err := json.Marshal(assertion)
+	if err != nil {
+		return "", nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return id, options, nil
+}
+
+// FinishLogin verifies the json encoded assertion returned by the browser.
+// Returns the subject of the user and the passkey that was used.
+func (c *Controller) FinishLogin(ctx context.Context, ceremonyId string, response []byte) (string, *user.Passkey, error) {
+	session, err := c.consumeCeremony(ctx, loginCeremony, "", ceremonyId)
+	if err != nil {
+		return "", nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return "", nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid passkey assertion: %v", err))
+	}
+	var acc *account
+	credential, err := c.webauthn.ValidateDiscoverableLogin(func(rawId, userHandle []byte) (webauthn.User, error) {
+		loaded, err := c.loadAccount(ctx, string(userHandle), "")
+		acc = loaded
+		return loaded, err
+	}, *session, parsed)
+	if err != nil {
+		return "", nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("passkey verification failed: %v", err))
+	} else if credential.Authenticator.CloneWarning {
+		// a sign counter that did not increase indicates that the authenticator was cloned.
+		return "", nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("passkey verification failed: possibly cloned authenticator"))
+	}
+
+	passkey, ok := acc.passkeys[passkeyId(credential.ID)]
+	if !ok {
+		return "", nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("passkey does not exist"))
+	}
+	record, err := json.Marshal(credential)
+	if err != nil {
+		return "", nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if err := c.passkeyModel.UpdatePasskeyUsage(ctx, acc.sub, passkey.Id, string(record), time.Now()); err != nil {
+		return "", nil, err
+	}
+	return acc.sub, passkey, nil
+}
+
+// List returns all passkeys registered by the user.
+func (c *Controller) List(ctx context.Context, sub string) ([]*user.Passkey, error) {
+	return c.passkeyModel.ListPasskeys(ctx, sub)
+}
+
+// Delete removes the passkey, it can no longer be used to log in.
+func (c *Controller) Delete(ctx context.Context, sub, id string) error {
+	return c.passkeyModel.DeletePasskey(ctx, sub, id)
+}
+
+// ChangeEmail updates the email that is issued on logins with the passkeys of the user.
+func (c *Controller) ChangeEmail(ctx context.Context, sub, email string) error {
+	passkeys, err := c.passkeyModel.ListPasskeys(ctx, sub)
+	if err != nil {
+		return err
+	}
+	for _, passkey := range passkeys {
+		if err := c.passkeyModel.UpdatePasskeyEmail(ctx, sub, passkey.Id, email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Controller) putCeremony(ctx context.Context, kind, sub string, session *webauthn.SessionData) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", connect.NewError(connect.CodeInternal, err)
+	}
+	id := rand.Text()
+	err = c.ceremonyModel.PutCeremony(ctx, kind, id, &ceremony.Ceremony{
+		Subject:   sub,
+		Data:      string(data),
+		ExpiresAt: time.Now().Add(ceremonyTTL).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (c *Controller) consumeCeremony(ctx context.Context, kind, sub, id string) (*webauthn.SessionData, error) {
+	cer, found, err := c.ceremonyModel.ConsumeCeremony(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	} else if !found || cer.Subject != sub {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("ceremony does not exist or has expired"))
+	}
+	session := &webauthn.SessionData{}
+	if err := json.Unmarshal([]byte(cer.Data), session); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return session, nil
+}
+
+func (c *Controller) loadAccount(ctx context.Context, sub, email string) (*account, error) {
+	passkeys, err := c.passkeyModel.ListPasskeys(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	acc := &account{
+		sub:         sub,
+		email:       email,
+		passkeys:    map[string]*user.Passkey{},
+		credentials: []webauthn.Credential{},
+	}
+	for _, passkey := range passkeys {
+		credential := webauthn.Credential{}
+		if err := json.Unmarshal([]byte(passkey.Credential), &credential); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		acc.passkeys[passkey.Id] = passkey
+		acc.credentials = append(acc.credentials, credential)
+	}
+	return acc, nil
+}
+
+// passkeyId derives the storage id from the raw credential id (which can be up to 1023 bytes long).
+func passkeyId(credentialId []byte) string {
+	hash := sha256.Sum256(credentialId)
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// account implements the webauthn.User.
+type account struct {
+	sub         string
+	email       string
+	passkeys    map[string]*user.Passkey
+	credentials []webauthn.Credential
+}
+
+func (a *account) WebAuthnID() []byte {
+	return []byte(a.sub)
+}
+
+func (a *account) WebAuthnName() string {
+	return a.email
+}
+
+func (a *account) WebAuthnDisplayName() string {
+	return a.email
+}
+
+func (a *account) WebAuthnCredentials() []webauthn.Credential {
+	return a.credentials
+}
@@ -0,0 +1,105 @@
+// package reconcile finds and removes orphaned accounts (a profile without registration or a registration without profile).
+// Accounts are created and deleted atomically, orphans are leftovers of older versions that wrote the items separately.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/megakuul/zen/internal/model/account"
+	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/model/user"
+)
+
+// grace protects recently created profiles, the table scan is not a snapshot and can miss concurrent writes.
+const grace = time.Hour
+
+type Controller struct {
+	accountModel   account.Store
+	profileModel   user.ProfileStore
+	partitionModel user.PartitionStore
+	emailModel     email.RegistrationStore
+}
+
+func New(accounts account.Store, profiles user.ProfileStore, partitions user.PartitionStore, emails email.RegistrationStore) *Controller {
+	return &Controller{
+		accountModel:   accounts,
+		profileModel:   profiles,
+		partitionModel: partitions,
+		emailModel:     emails,
+	}
+}
+
+type Orphans struct {
+	// Profiles contains the subjects of the profiles without registration.
+	Profiles []string
+	// Registrations maps the emails of the registrations without profile to their subject.
+	Registrations map[string]string
+}
+
+// Find scans the table for orphans. Every candidate is confirmed before it is reported: registrations by a
+// consistent profile lookup, profiles (that have no reverse lookup) by a second scan.
+func (c *Controller) Find(ctx context.Context) (*Orphans, error) {
+	orphans := &Orphans{Profiles: []string{}, Registrations: map[string]string{}}
+	index, err := c.accountModel.ScanIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for addr, sub := range index.Registrations {
+		if _, ok := index.Profiles[sub]; ok {
+			continue
+		}
+		_, found, err := c.profileModel.GetProfile(ctx, sub)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			orphans.Registrations[addr] = sub
+		}
+	}
+
+	candidates := unreferenced(index, time.Now().Add(-grace))
+	if len(candidates) < 1 {
+		return orphans, nil
+	}
+	index, err = c.accountModel.ScanIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range unreferenced(index, time.Now().Add(-grace)) {
+		if slices.Contains(candidates, sub) {
+			orphans.Profiles = append(orphans.Profiles, sub)
+		}
+	}
+	return orphans, nil
+}
+
+// Clean removes the orphans. Orphaned profiles are purged with their partition (the data is no longer reachable).
+func (c *Controller) Clean(ctx context.Context, orphans *Orphans) error {
+	var errs error
+	for _, sub := range orphans.Profiles {
+		errs = errors.Join(errs, c.partitionModel.PurgePartition(ctx, sub))
+	}
+	for addr, sub := range orphans.Registrations {
+		errs = errors.Join(errs, c.emailModel.DeleteRegistration(ctx, sub, addr))
+	}
+	return errs
+}
+
+// unreferenced returns the sorted subjects of the profiles created before the deadline that have no registration.
+func unreferenced(index *account.Index, deadline time.Time) []string {
+	referenced := map[string]bool{}
+	for _, sub := range index.Registrations {
+		referenced[sub] = true
+	}
+	subs := []string{}
+	for sub, createdAt := range index.Profiles {
+		if !referenced[sub] && createdAt < deadline.Unix() {
+			subs = append(subs, sub)
+		}
+	}
+	slices.Sort(subs)
+	return subs
+}
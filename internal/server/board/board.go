@@ -0,0 +1,94 @@
+// package board serves the weekly leaderboards (/leaderboard/YEAR-WEEK.json) like the s3 route of the cdn.
+// Boards are read through the board model, only valid board names are accepted (no listing, no other files).
+package board
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/megakuul/zen/internal/model/leaderboard"
+)
+
+const Path = "/leaderboard/"
+
+// cacheControl keeps the caching short, the current board changes with every processed rating batch.
+const cacheControl = "public, max-age=60"
+
+type Service struct {
+	logger     *slog.Logger
+	boardModel leaderboard.BoardStore
+}
+
+func New(logger *slog.Logger, board leaderboard.BoardStore) *Service {
+	return &Service{
+		logger:     logger,
+		boardModel: board,
+	}
+}
+
+// Handler returns the path and handler to mount on the mux (like the generated connect handlers).
+func (s *Service) Handler() (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+Path+"{name}", s.board)
+	return Path, mux
+}
+
+func (s *Service) board(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	date, ok := parseName(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	board, found, err := s.boardModel.GetBoard(r.Context(), date)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("board lookup failure: %v", err), "endpoint", "board")
+		http.Error(w, "failed to load board", http.StatusInternalServerError)
+		return
+	} else if !found {
+		http.NotFound(w, r)
+		return
+	}
+	rawBoard, err := json.Marshal(board)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("board encoding failure: %v", err), "endpoint", "board")
+		http.Error(w, "failed to encode board", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", cacheControl)
+	if board.ETag != "" {
+		w.Header().Set("ETag", board.ETag)
+	}
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(rawBoard))
+}
+
+// parseName returns a date in the iso week of the board name (YEAR-WEEK.json).
+// Only the canonical name of an existing week is accepted (e.g. no leading zeros and no week 53 in a 52 week year).
+func parseName(name string) (time.Time, bool) {
+	base, ok := strings.CutSuffix(name, ".json")
+	if !ok {
+		return time.Time{}, false
+	}
+	rawYear, rawWeek, ok := strings.Cut(base, "-")
+	if !ok {
+		return time.Time{}, false
+	}
+	year, yearErr := strconv.Atoi(rawYear)
+	week, weekErr := strconv.Atoi(rawWeek)
+	if yearErr != nil || weekErr != nil || week < 1 || week > 53 || fmt.Sprintf("%d-%d.json", year, week) != name {
+		return time.Time{}, false
+	}
+	// january 4th is always in the first iso week of the year.
+	date := time.Date(year, time.January, 4, 12, 0, 0, 0, time.UTC).AddDate(0, 0, (week-1)*7)
+	if y, w := date.ISOWeek(); y != year || w != week {
+		return time.Time{}, false
+	}
+	return date, true
+}
@@ -0,0 +1,145 @@
+// package server wires the controllers and the api handlers, it is shared by the binaries and the test environment.
+// The binaries only select the backends (models, token provider, mailer and captcha store).
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	dchest "github.com/dchest/captcha"
+	"github.com/megakuul/zen/internal/audit"
+	"github.com/megakuul/zen/internal/auth"
+	"github.com/megakuul/zen/internal/captcha"
+	"github.com/megakuul/zen/internal/export"
+	"github.com/megakuul/zen/internal/interceptor"
+	"github.com/megakuul/zen/internal/mail"
+	"github.com/megakuul/zen/internal/model/account"
+	"github.com/megakuul/zen/internal/model/ceremony"
+	"github.com/megakuul/zen/internal/model/email"
+	leaderboardmodel "github.com/megakuul/zen/internal/model/leaderboard"
+	"github.com/megakuul/zen/internal/model/ratelimit"
+	"github.com/megakuul/zen/internal/model/rating"
+	"github.com/megakuul/zen/internal/model/user"
+	"github.com/megakuul/zen/internal/oidc"
+	"github.com/megakuul/zen/internal/passkey"
+	"github.com/megakuul/zen/internal/server/board"
+	"github.com/megakuul/zen/internal/server/v1/manager/authentication"
+	"github.com/megakuul/zen/internal/server/v1/manager/management"
+	"github.com/megakuul/zen/internal/server/v1/scheduler/planning"
+	"github.com/megakuul/zen/internal/server/v1/scheduler/timing"
+	"github.com/megakuul/zen/internal/server/wellknown"
+	"github.com/megakuul/zen/internal/token"
+	"github.com/megakuul/zen/internal/totp"
+	"github.com/megakuul/zen/pkg/api/v1/manager/authentication/authenticationconnect"
+	"github.com/megakuul/zen/pkg/api/v1/manager/management/managementconnect"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/planning/planningconnect"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/timing/timingconnect"
+)
+
+type Config struct {
+	TokenIssuer string
+	// TrustProxy reads the client address from the x-forwarded-for header (rate limits, audit trail and sessions).
+	TrustProxy       bool
+	LoginLinkUrl     string
+	SupportSubjects  []string
+	PasskeyRpId      string
+	PasskeyRpName    string
+	PasskeyRpOrigins []string
+	TotpIssuer       string
+	// the oidc login is disabled if there is no issuer.
+	OidcIssuer       string
+	OidcClientId     string
+	OidcClientSecret string
+	OidcRedirectUrl  string
+	RatingAnchor     time.Duration
+}
+
+// Models contains the storage backends. The scheduler only requires Users, Emails, RateLimits and Ratings.
+type Models struct {
+	Accounts   account.Store
+	Emails     email.Store
+	Users      user.Store
+	Ceremonies ceremony.Store
+	RateLimits ratelimit.Store
+	Boards     leaderboardmodel.BoardStore
+	Ratings    rating.Sender
+}
+
+// Server holds the controllers that are shared by the apis, the apis are added to a mux with the Register functions.
+type Server struct {
+	logger       *slog.Logger
+	cfg          *Config
+	models       *Models
+	interceptors connect.HandlerOption
+
+	Token *token.Controller
+	Audit *audit.Controller
+}
+
+func New(logger *slog.Logger, cfg *Config, models *Models, tokenProvider token.Provider) *Server {
+	tokenCtrl := token.New(cfg.TokenIssuer, tokenProvider)
+	tokenCtrl.Registry = models.Users
+	tokenCtrl.Accounts = models.Emails
+	auditCtrl := audit.New(models.Users)
+	auditCtrl.SupportSubjects = cfg.SupportSubjects
+	auditCtrl.TrustProxy = cfg.TrustProxy
+	return &Server{
+		logger: logger,
+		cfg:    cfg,
+		models: models,
+		interceptors: connect.WithInterceptors(
+			interceptor.NewAuth(tokenCtrl),
+			interceptor.NewRateLimit(models.RateLimits, interceptor.DefaultLimits, cfg.TrustProxy),
+		),
+		Token: tokenCtrl,
+		Audit: auditCtrl,
+	}
+}
+
+// RegisterManager adds the authentication, management and well-known apis to the mux.
+func (s *Server) RegisterManager(ctx context.Context, mux *http.ServeMux, mailer mail.Mailer, captchaStore dchest.Store) error {
+	m := s.models
+	exportCtrl := export.New(m.Users, m.Users, m.Boards)
+	authCtrl := auth.New(m.Emails, m.Ceremonies, s.Token, s.Audit, mailer, s.cfg.LoginLinkUrl)
+	captchaCtrl := captcha.New(captchaStore)
+	passkeyCtrl, err := passkey.New(s.cfg.PasskeyRpId, s.cfg.PasskeyRpName, s.cfg.PasskeyRpOrigins, m.Ceremonies, m.Users)
+	if err != nil {
+		return fmt.Errorf("cannot create passkey relying party: %w", err)
+	}
+	totpCtrl := totp.New(s.cfg.TotpIssuer, m.Users, m.Ceremonies)
+	var oidcCtrl *oidc.Controller
+	if s.cfg.OidcIssuer != "" {
+		oidcCtrl, err = oidc.New(ctx, s.cfg.OidcIssuer, s.cfg.OidcClientId, s.cfg.OidcClientSecret, s.cfg.OidcRedirectUrl, m.Ceremonies)
+		if err != nil {
+			return fmt.Errorf("cannot discover oidc provider: %w", err)
+		}
+	}
+
+	mux.Handle(
+		authenticationconnect.NewAuthenticationServiceHandler(authentication.New(s.logger, s.Token, authCtrl, passkeyCtrl, totpCtrl, oidcCtrl, s.Audit, m.Emails, m.Users), s.interceptors),
+	)
+	mux.Handle(
+		managementconnect.NewManagementServiceHandler(management.New(s.logger, s.Token, authCtrl, captchaCtrl, passkeyCtrl, totpCtrl, s.Audit, exportCtrl, m.Ratings, m.Accounts, m.Users, m.Emails, m.Users, m.Users), s.interceptors),
+	)
+	mux.Handle(wellknown.New(s.logger, s.Token).Handler())
+	return nil
+}
+
+// RegisterBoards adds the read-only board route to the mux (served by the cdn if the boards are stored in s3).
+func (s *Server) RegisterBoards(mux *http.ServeMux) {
+	mux.Handle(board.New(s.logger, s.models.Boards).Handler())
+}
+
+// RegisterScheduler adds the planning and timing apis to the mux.
+func (s *Server) RegisterScheduler(mux *http.ServeMux) {
+	mux.Handle(
+		planningconnect.NewPlanningServiceHandler(planning.New(s.logger, s.models.Users), s.interceptors),
+	)
+	mux.Handle(
+		timingconnect.NewTimingServiceHandler(timing.New(s.logger, s.models.Users, s.models.Ratings, s.cfg.RatingAnchor), s.interceptors),
+	)
+}
@@ -8,21 +8,27 @@ import (
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/megakuul/zen/internal/model/email"
 	"github.com/megakuul/zen/internal/model/leaderboard"
 	"github.com/megakuul/zen/internal/model/rating"
+	"github.com/megakuul/zen/internal/model/user"
 )
 
 type Service struct {
-	logger      *slog.Logger
-	boardModel  *leaderboard.Model
-	ratingModel *rating.Model
+	logger       *slog.Logger
+	boardModel   leaderboard.BoardStore
+	profileModel user.ProfileStore
+	userModel    user.PartitionStore
+	emailModel   email.RegistrationStore
 }
 
-func New(logger *slog.Logger, board *leaderboard.Model, rating *rating.Model) *Service {
+func New(logger *slog.Logger, board leaderboard.BoardStore, profile user.ProfileStore, user user.PartitionStore, email email.RegistrationStore) *Service {
 	return &Service{
-		logger:      logger,
-		boardModel:  board,
-		ratingModel: rating,
+		logger:       logger,
+		boardModel:   board,
+		profileModel: profile,
+		userModel:    user,
+		emailModel:   email,
 	}
 }
 
@@ -42,12 +48,42 @@ func (s *Service) Process(ctx context.Context, r events.SQSEvent) error {
 			Entries:    map[string]leaderboard.BoardEntry{},
 		}
 	}
+	updates := []*rating.Update{}
+	deletions := map[string]*rating.Update{}
 	for _, message := range r.Records {
-		update, err := s.ratingModel.ParseUpdate(message.Body)
+		update, err := rating.ParseUpdate(message.Body)
 		if err != nil {
 			s.logger.Error(fmt.Sprintf("critical error: failed to read message '%s': %v", message.MessageId, err))
 			return fmt.Errorf("failed to parse update: %v", err)
 		}
+		if update.Deleted {
+			deletions[update.UserId] = update
+		} else {
+			updates = append(updates, update)
+		}
+	}
+	// rating updates that were in flight during the deletion must not add the user again. The queue does not preserve
+	// the order, updates sent before the deletion can arrive in a later batch -> the profile must still exist
+	// (it is removed together with the registration when the account is deleted).
+	exists := map[string]bool{}
+	for _, update := range updates {
+		if _, ok := exists[update.UserId]; ok {
+			continue
+		} else if _, ok := deletions[update.UserId]; ok {
+			exists[update.UserId] = false
+			continue
+		}
+		_, found, err := s.profileModel.GetProfile(ctx, update.UserId)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failure while checking profile of user '%s': %v", update.UserId, err))
+			return fmt.Errorf("failed to lookup profile: %v", err)
+		}
+		exists[update.UserId] = found
+	}
+	for _, update := range updates {
+		if !exists[update.UserId] {
+			continue
+		}
 		board.Algorithms[update.Algorithm] = time.Now().Unix()
 		entry, ok := board.Entries[update.UserId]
 		if !ok {
@@ -67,10 +103,55 @@ func (s *Service) Process(ctx context.Context, r events.SQSEvent) error {
 		}
 		board.Entries[update.UserId] = entry
 	}
+	for id := range deletions {
+		delete(board.Entries, id)
+	}
 	err = s.boardModel.PutBoard(ctx, time.Now(), board)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("failure while inserting updated board: %v", err))
 		return fmt.Errorf("failed to insert updated board: %v", err)
 	}
+
+	// deletions are processed here because the queue redelivers them until the purge is complete.
+	// every step is idempotent, an interrupted purge simply starts over.
+	for _, deletion := range deletions {
+		if err := s.purge(ctx, deletion); err != nil {
+			s.logger.Error(fmt.Sprintf("failure while purging user '%s': %v", deletion.UserId, err))
+			return fmt.Errorf("failed to purge user: %v", err)
+		}
+	}
+	return nil
+}
+
+// purge removes the deleted user from the storage and the archived boards (the current board is updated by Process).
+func (s *Service) purge(ctx context.Context, deletion *rating.Update) error {
+	if deletion.Email != "" {
+		if err := s.emailModel.DeleteRegistration(ctx, deletion.UserId, deletion.Email); err != nil {
+			return err
+		}
+	}
+	if err := s.userModel.PurgePartition(ctx, deletion.UserId); err != nil {
+		return err
+	}
+
+	year, week := time.Now().ISOWeek()
+	for date := deletion.Time; date.Before(time.Now()); date = date.AddDate(0, 0, 7) {
+		if dateYear, dateWeek := date.ISOWeek(); dateYear == year && dateWeek == week {
+			break
+		}
+		board, found, err := s.boardModel.GetBoard(ctx, date)
+		if err != nil {
+			return err
+		} else if !found {
+			continue
+		}
+		if _, ok := board.Entries[deletion.UserId]; !ok {
+			continue
+		}
+		delete(board.Entries, deletion.UserId)
+		if err := s.boardModel.PutBoard(ctx, date, board); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -8,53 +8,110 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/google/uuid"
 
+	"github.com/megakuul/zen/internal/audit"
 	"github.com/megakuul/zen/internal/auth"
 	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/model/user"
+	"github.com/megakuul/zen/internal/oidc"
+	"github.com/megakuul/zen/internal/passkey"
 	"github.com/megakuul/zen/internal/token"
+	"github.com/megakuul/zen/internal/totp"
+	"github.com/megakuul/zen/pkg/api/v1/manager"
 	"github.com/megakuul/zen/pkg/api/v1/manager/authentication"
 )
 
 const (
 	refreshTokenName = "refresh_token"
-	accessTokenTTL   = 15 * time.Minute // 15 minutes
-	refreshTokenTTL  = 720 * time.Hour  // 30 days
+	// linkDeviceName holds the device secret that binds a sign-in link to the requesting device.
+	linkDeviceName = "link_device"
+	// oidcDeviceName holds the device secret that binds the oidc state to the device that started the login.
+	oidcDeviceName = "oidc_device"
+	accessTokenTTL = 15 * time.Minute // 15 minutes
 )
 
+// RefreshTokenTTL is the lifetime of refresh tokens (30 days).
+const RefreshTokenTTL = 720 * time.Hour
+
 type Service struct {
-	logger     *slog.Logger
-	tokenCtrl  *token.Controller
-	authCtrl   *auth.Controller
-	emailModel *email.Model
+	logger       *slog.Logger
+	tokenCtrl    *token.Controller
+	authCtrl     *auth.Controller
+	passkeyCtrl  *passkey.Controller
+	totpCtrl     *totp.Controller
+	oidcCtrl     *oidc.Controller
+	auditCtrl    *audit.Controller
+	emailModel   email.RegistrationStore
+	sessionModel user.SessionStore
 }
 
-func New(logger *slog.Logger, token *token.Controller, auth *auth.Controller, email *email.Model) *Service {
+// New creates the authentication service, oidc is optional (nil disables the oidc login).
+func New(logger *slog.Logger, token *token.Controller, auth *auth.Controller, passkey *passkey.Controller, totp *totp.Controller, oidc *oidc.Controller, audit *audit.Controller, email email.RegistrationStore, session user.SessionStore) *Service {
 	return &Service{
-		logger:     logger,
-		tokenCtrl:  token,
-		authCtrl:   auth,
-		emailModel: email,
+		logger:       logger,
+		tokenCtrl:    token,
+		authCtrl:     auth,
+		passkeyCtrl:  passkey,
+		totpCtrl:     totp,
+		oidcCtrl:     oidc,
+		auditCtrl:    audit,
+		emailModel:   email,
+		sessionModel: session,
 	}
 }
 
 func (s *Service) Login(ctx context.Context, r *connect.Request[authentication.LoginRequest]) (*connect.Response[authentication.LoginResponse], error) {
-	refreshCookie := findRefreshCookie(r.Header())
+	if r.Msg.LinkToken != "" {
+		return s.completeLinkLogin(ctx, r)
+	}
+	refreshCookie := findCookie(r.Header(), refreshTokenName)
 	if refreshCookie != nil {
 		claims, err := s.tokenCtrl.Verify(ctx, refreshCookie.Value)
 		if err != nil {
 			return nil, err
-		} else if !claims.Refresh {
+		} else if !claims.Refresh || claims.Session == "" {
 			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("invalid token; expected refresh_token"))
 		}
-		token, err := s.tokenCtrl.Issue(ctx, claims.Subject, claims.Email, false, time.Now().Add(accessTokenTTL))
+		resp := connect.NewResponse(&authentication.LoginResponse{})
+		// the refresh token is rotated on every use, presenting an already rotated token means that
+		// the token family was leaked -> the whole session is revoked (legitimate user must log in again).
+		// tokens rotated within the grace period are from concurrent refreshes (e.g. multiple tabs), they
+		// only get an access token, the cookie of the concurrent refresh stays in place.
+		refreshId, err := s.issueRefreshToken(ctx, resp, claims.Subject, claims.Email, claims.Session)
+		if err != nil {
+			return nil, err
+		}
+		err = s.sessionModel.RotateSession(ctx, claims.Subject, claims.Session, claims.ID, &user.Session{
+			Token:      refreshId,
+			UserAgent:  r.Header().Get("User-Agent"),
+			Origin:     s.auditCtrl.Origin(r),
+			LastUsedAt: time.Now().Unix(),
+			ExpiresAt:  time.Now().Add(RefreshTokenTTL).Unix(),
+		})
+		if connect.CodeOf(err) == connect.CodeAborted {
+			resp.Header().Del("Set-Cookie")
+		} else if err != nil {
+			if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+				return nil, err
+			}
+			s.logger.Warn(fmt.Sprintf("refresh token reuse detected; revoking session '%s'", claims.Session), "endpoint", "login")
+			if err := s.sessionModel.DeleteSession(ctx, claims.Subject, claims.Session); err != nil {
+				return nil, err
+			}
+			if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeRefreshReuse, claims.Session, r); err != nil {
+				return nil, err
+			}
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("refresh token was revoked"))
+		}
+		resp.Msg.Token, _, err = s.tokenCtrl.Issue(ctx, claims.Subject, claims.Email, claims.Session, false, time.Now().Add(accessTokenTTL))
 		if err != nil {
 			return nil, err
 		}
-		return &connect.Response[authentication.LoginResponse]{
-			Msg: &authentication.LoginResponse{
-				Token: token,
-			},
-		}, nil
+		if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeRefresh, claims.Session, r); err != nil {
+			return nil, err
+		}
+		return resp, nil
 	}
 	if r.Msg.Verifier.Email == "" {
 		// 401 seems a bit weird here, but the point is that the user tries to log in without
@@ -69,6 +126,25 @@ func (s *Service) Login(ctx context.Context, r *connect.Request[authentication.L
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("email is not registered"))
 	}
 
+	if r.Msg.MagicLink && r.Msg.Verifier.Stage == manager.VerifierStage_VERIFIER_STAGE_EMAIL {
+		device, expiresAt, err := s.authCtrl.SendLink(ctx, r.Msg.Verifier.Email, r.Msg.AutoRefresh)
+		if err != nil {
+			return nil, err
+		}
+		resp := connect.NewResponse(&authentication.LoginResponse{})
+		cookie := http.Cookie{
+			Name:     linkDeviceName,
+			Expires:  expiresAt,
+			Secure:   true,
+			HttpOnly: true,
+			Path:     "/",
+			SameSite: http.SameSiteStrictMode,
+			Value:    device,
+		}
+		resp.Header().Add("Set-Cookie", cookie.String())
+		return resp, nil
+	}
+
 	verified, err := s.authCtrl.Authenticate(ctx, r.Msg.Verifier)
 	if err != nil {
 		return nil, err
@@ -76,49 +152,265 @@ func (s *Service) Login(ctx context.Context, r *connect.Request[authentication.L
 		return connect.NewResponse(&authentication.LoginResponse{}), nil
 	}
 
-	accessToken, err := s.tokenCtrl.Issue(ctx, registration.User, r.Msg.Verifier.Email, false, time.Now().Add(accessTokenTTL))
+	resp := connect.NewResponse(&authentication.LoginResponse{})
+	resp.Msg.Token, resp.Msg.TotpCeremonyId, err = s.completeLogin(ctx, r, resp, registration.User, r.Msg.Verifier.Email, "email", r.Msg.AutoRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// completeLinkLogin completes the login with the token of a sign-in link, it must be opened on the device
+// that requested the link (device cookie). The device cookie is removed once the link was used.
+func (s *Service) completeLinkLogin(ctx context.Context, r *connect.Request[authentication.LoginRequest]) (*connect.Response[authentication.LoginResponse], error) {
+	deviceCookie := findCookie(r.Header(), linkDeviceName)
+	if deviceCookie == nil {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("sign-in link must be opened on the device that requested it"))
+	}
+	state, err := s.authCtrl.ConsumeLink(ctx, r.Msg.LinkToken, deviceCookie.Value)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("sign-in link failure: %v", err), "endpoint", "login")
+		return nil, err
+	}
+	registration, found, err := s.emailModel.GetRegistration(ctx, state.Email)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("email is not registered"))
+	}
+
+	resp := connect.NewResponse(&authentication.LoginResponse{})
+	resp.Msg.Token, resp.Msg.TotpCeremonyId, err = s.completeLogin(ctx, r, resp, registration.User, state.Email, "link", state.AutoRefresh)
 	if err != nil {
 		return nil, err
 	}
-	resp := connect.NewResponse(&authentication.LoginResponse{
-		Token: accessToken,
-	})
-	if r.Msg.AutoRefresh {
-		refreshToken, err := s.tokenCtrl.Issue(ctx, registration.User, r.Msg.Verifier.Email, true, time.Now().Add(refreshTokenTTL))
+	cookie := http.Cookie{
+		Name:     linkDeviceName,
+		Expires:  time.Now().Add(-8760 * time.Hour), // expire cookie
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		Path:     "/",
+		SameSite: http.SameSiteStrictMode,
+	}
+	resp.Header().Add("Set-Cookie", cookie.String())
+	return resp, nil
+}
+
+func (s *Service) BeginOidcLogin(ctx context.Context, r *connect.Request[authentication.BeginOidcLoginRequest]) (*connect.Response[authentication.BeginOidcLoginResponse], error) {
+	if s.oidcCtrl == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("oidc login is not configured"))
+	}
+	url, device, expiresAt, err := s.oidcCtrl.BeginLogin(ctx, r.Msg.AutoRefresh)
+	if err != nil {
+		return nil, err
+	}
+	resp := connect.NewResponse(&authentication.BeginOidcLoginResponse{Url: url})
+	cookie := http.Cookie{
+		Name:     oidcDeviceName,
+		Expires:  expiresAt,
+		Secure:   true,
+		HttpOnly: true,
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode, // the user agent returns from the provider with a cross-site redirect
+		Value:    device,
+	}
+	resp.Header().Add("Set-Cookie", cookie.String())
+	return resp, nil
+}
+
+// FinishOidcLogin logs in the user registered with the verified email of the identity provider.
+// Users are not provisioned automatically, the email must be registered beforehand.
+func (s *Service) FinishOidcLogin(ctx context.Context, r *connect.Request[authentication.FinishOidcLoginRequest]) (*connect.Response[authentication.FinishOidcLoginResponse], error) {
+	if s.oidcCtrl == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("oidc login is not configured"))
+	}
+	deviceCookie := findCookie(r.Header(), oidcDeviceName)
+	if deviceCookie == nil {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("login must be finished on the device that started it"))
+	}
+	identity, err := s.oidcCtrl.FinishLogin(ctx, r.Msg.State, r.Msg.Code, deviceCookie.Value)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("oidc login failure: %v", err), "endpoint", "finish_oidc_login")
+		return nil, err
+	}
+	registration, found, err := s.emailModel.GetRegistration(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("email is not registered"))
+	}
+
+	resp := connect.NewResponse(&authentication.FinishOidcLoginResponse{})
+	resp.Msg.Token, resp.Msg.TotpCeremonyId, err = s.completeLogin(ctx, r, resp, registration.User, identity.Email, "oidc", identity.AutoRefresh)
+	if err != nil {
+		return nil, err
+	}
+	cookie := http.Cookie{
+		Name:     oidcDeviceName,
+		Expires:  time.Now().Add(-8760 * time.Hour), // expire cookie
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode,
+	}
+	resp.Header().Add("Set-Cookie", cookie.String())
+	return resp, nil
+}
+
+func (s *Service) VerifyTotp(ctx context.Context, r *connect.Request[authentication.VerifyTotpRequest]) (*connect.Response[authentication.VerifyTotpResponse], error) {
+	state, err := s.totpCtrl.FinishLogin(ctx, r.Msg.CeremonyId, r.Msg.Code)
+	if err != nil {
+		return nil, err
+	}
+	resp := connect.NewResponse(&authentication.VerifyTotpResponse{})
+	resp.Msg.Token, err = s.createLogin(ctx, r, resp, state.Subject, state.Email, state.Method+"+totp", state.AutoRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Service) BeginPasskeyLogin(ctx context.Context, r *connect.Request[authentication.BeginPasskeyLoginRequest]) (*connect.Response[authentication.BeginPasskeyLoginResponse], error) {
+	id, options, err := s.passkeyCtrl.BeginLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&authentication.BeginPasskeyLoginResponse{
+		CeremonyId: id,
+		Options:    string(options),
+	}), nil
+}
+
+// FinishPasskeyLogin does not require the totp step, passkeys are verified with user verification (multi-factor by themselves).
+func (s *Service) FinishPasskeyLogin(ctx context.Context, r *connect.Request[authentication.FinishPasskeyLoginRequest]) (*connect.Response[authentication.FinishPasskeyLoginResponse], error) {
+	sub, key, err := s.passkeyCtrl.FinishLogin(ctx, r.Msg.CeremonyId, []byte(r.Msg.Credential))
+	if err != nil {
+		return nil, err
+	}
+	resp := connect.NewResponse(&authentication.FinishPasskeyLoginResponse{})
+	resp.Msg.Token, err = s.createLogin(ctx, r, resp, sub, key.Email, "passkey", r.Msg.AutoRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// completeLogin finishes a login that passed the first factor. If the user has totp enabled, no token is issued,
+// instead the id of the totp ceremony is returned (completed with VerifyTotp).
+// The method describes the first factor in the audit trail.
+func (s *Service) completeLogin(ctx context.Context, r connect.AnyRequest, resp connect.AnyResponse, sub, email, method string, autoRefresh bool) (string, string, error) {
+	totpEnabled, err := s.totpCtrl.Enabled(ctx, sub)
+	if err != nil {
+		return "", "", err
+	} else if totpEnabled {
+		ceremonyId, err := s.totpCtrl.BeginLogin(ctx, &totp.LoginState{
+			Subject:     sub,
+			Email:       email,
+			Method:      method,
+			AutoRefresh: autoRefresh,
+		})
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
+		return "", ceremonyId, nil
+	}
+	token, err := s.createLogin(ctx, r, resp, sub, email, method, autoRefresh)
+	if err != nil {
+		return "", "", err
+	}
+	return token, "", nil
+}
 
-		// for simplicity there is no strict path enforcement (yeah its not optimal I know)
-		// due to the nature of the api design, functions are top level which would require one cookie per service...
-		// one could argue that this means the api was designed incorrectly and maybe he is right and maybe I'll refactor it in the future.
-		cookie := http.Cookie{
-			Name:     refreshTokenName,
-			Expires:  time.Now().Add(refreshTokenTTL),
-			Secure:   true,
-			HttpOnly: true,
-			Path:     "/", // <- read the text above :(
-			SameSite: http.SameSiteStrictMode,
-			Value:    refreshToken,
+// createLogin issues an access token for the verified user. With autoRefresh a new session is created,
+// its refresh token is attached as cookie to the response.
+func (s *Service) createLogin(ctx context.Context, r connect.AnyRequest, resp connect.AnyResponse, sub, email, method string, autoRefresh bool) (string, error) {
+	session := ""
+	if autoRefresh {
+		session = uuid.New().String()
+		refreshId, err := s.issueRefreshToken(ctx, resp, sub, email, session)
+		if err != nil {
+			return "", err
+		}
+		err = s.sessionModel.PutSession(ctx, sub, session, &user.Session{
+			Token:      refreshId,
+			UserAgent:  r.Header().Get("User-Agent"),
+			Origin:     s.auditCtrl.Origin(r),
+			CreatedAt:  time.Now().Unix(),
+			LastUsedAt: time.Now().Unix(),
+			ExpiresAt:  time.Now().Add(RefreshTokenTTL).Unix(),
+		})
+		if err != nil {
+			return "", err
 		}
-		resp.Header().Add("Set-Cookie", cookie.String())
 	}
-	return resp, nil
+	token, _, err := s.tokenCtrl.Issue(ctx, sub, email, session, false, time.Now().Add(accessTokenTTL))
+	if err != nil {
+		return "", err
+	}
+	if err := s.auditCtrl.Record(ctx, sub, audit.TypeLogin, method, r); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// issueRefreshToken issues a refresh token for the session and attaches it as cookie to the response.
+// Returns the id of the issued token.
+func (s *Service) issueRefreshToken(ctx context.Context, resp connect.AnyResponse, sub, email, session string) (string, error) {
+	refreshToken, refreshId, err := s.tokenCtrl.Issue(ctx, sub, email, session, true, time.Now().Add(RefreshTokenTTL))
+	if err != nil {
+		return "", err
+	}
+
+	// for simplicity there is no strict path enforcement (yeah its not optimal I know)
+	// due to the nature of the api design, functions are top level which would require one cookie per service...
+	// one could argue that this means the api was designed incorrectly and maybe he is right and maybe I'll refactor it in the future.
+	cookie := http.Cookie{
+		Name:     refreshTokenName,
+		Expires:  time.Now().Add(RefreshTokenTTL),
+		Secure:   true,
+		HttpOnly: true,
+		Path:     "/", // <- read the text above :(
+		SameSite: http.SameSiteStrictMode,
+		Value:    refreshToken,
+	}
+	resp.Header().Add("Set-Cookie", cookie.String())
+	return refreshId, nil
 }
 
 func (s *Service) Logout(ctx context.Context, r *connect.Request[authentication.LogoutRequest]) (*connect.Response[authentication.LogoutResponse], error) {
 	resp := connect.NewResponse(&authentication.LogoutResponse{})
-	refreshCookie := findRefreshCookie(r.Header())
+	refreshCookie := findCookie(r.Header(), refreshTokenName)
 	if refreshCookie == nil {
 		return resp, nil
 	}
-	refreshCookie.Expires = time.Now().Add(-8760 * time.Hour) // expire cookie
-	refreshCookie.MaxAge = -1
-	resp.Header().Add("Set-Cookie", refreshCookie.String())
+	// invalid tokens are not revoked (nothing to revoke), but the cookie is still removed.
+	claims, err := s.tokenCtrl.Verify(ctx, refreshCookie.Value)
+	if err == nil && claims.Refresh && claims.Session != "" {
+		if err := s.sessionModel.DeleteSession(ctx, claims.Subject, claims.Session); err != nil {
+			s.logger.Warn(fmt.Sprintf("session revocation failure: %v", err), "endpoint", "logout")
+			return nil, err
+		}
+		if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeLogout, claims.Session, r); err != nil {
+			return nil, err
+		}
+	}
+	// the cookie must match the attributes of the issued cookie (especially the path), otherwise it is not replaced.
+	cookie := http.Cookie{
+		Name:     refreshTokenName,
+		Expires:  time.Now().Add(-8760 * time.Hour), // expire cookie
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		Path:     "/",
+		SameSite: http.SameSiteStrictMode,
+	}
+	resp.Header().Add("Set-Cookie", cookie.String())
 	return resp, nil
 }
 
-func findRefreshCookie(headers http.Header) *http.Cookie {
+func findCookie(headers http.Header, name string) *http.Cookie {
 	cookieHeader := headers.Get("Cookie")
 	if cookieHeader != "" {
 		cookies, err := http.ParseCookie(cookieHeader)
@@ -126,7 +418,7 @@ func findRefreshCookie(headers http.Header) *http.Cookie {
 			return nil
 		}
 		for _, cookie := range cookies {
-			if cookie.Name == refreshTokenName {
+			if cookie.Name == name {
 				return cookie
 			}
 		}
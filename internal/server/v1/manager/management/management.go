@@ -1,39 +1,68 @@
 package management
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
-	"strings"
+	"slices"
 	"time"
 
 	"connectrpc.com/connect"
-	"github.com/dchest/captcha"
 	"github.com/google/uuid"
+	"github.com/megakuul/zen/internal/audit"
 	"github.com/megakuul/zen/internal/auth"
+	"github.com/megakuul/zen/internal/captcha"
+	"github.com/megakuul/zen/internal/export"
+	"github.com/megakuul/zen/internal/model/account"
 	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/model/rating"
 	"github.com/megakuul/zen/internal/model/user"
+	"github.com/megakuul/zen/internal/passkey"
 	"github.com/megakuul/zen/internal/token"
+	"github.com/megakuul/zen/internal/totp"
 	"github.com/megakuul/zen/pkg/api/v1/manager"
 	"github.com/megakuul/zen/pkg/api/v1/manager/management"
 )
 
+// lifetime of personal access tokens if not specified otherwise (90 days).
+const personalTokenDefaultTTL = 90 * 24 * time.Hour
+
+// PersonalTokenMaxTTL is the upper limit for the lifetime of personal access tokens (365 days).
+const PersonalTokenMaxTTL = 365 * 24 * time.Hour
+
 type Service struct {
-	logger     *slog.Logger
-	tokenCtrl  *token.Controller
-	authCtrl   *auth.Controller
-	userModel  *user.Model
-	emailModel *email.Model
+	logger       *slog.Logger
+	tokenCtrl    *token.Controller
+	authCtrl     *auth.Controller
+	captchaCtrl  *captcha.Controller
+	passkeyCtrl  *passkey.Controller
+	totpCtrl     *totp.Controller
+	auditCtrl    *audit.Controller
+	exportCtrl   *export.Controller
+	ratingModel  rating.Sender
+	accountModel account.Store
+	userModel    user.ProfileStore
+	emailModel   email.RegistrationStore
+	sessionModel user.SessionStore
+	tokenModel   user.PersonalTokenStore
 }
 
-func New(logger *slog.Logger, token *token.Controller, auth *auth.Controller, user *user.Model, email *email.Model) *Service {
+func New(logger *slog.Logger, token *token.Controller, auth *auth.Controller, captcha *captcha.Controller, passkey *passkey.Controller, totp *totp.Controller, audit *audit.Controller, export *export.Controller, rating rating.Sender, account account.Store, user user.ProfileStore, email email.RegistrationStore, session user.SessionStore, personalToken user.PersonalTokenStore) *Service {
 	return &Service{
-		logger:     logger,
-		tokenCtrl:  token,
-		authCtrl:   auth,
-		userModel:  user,
-		emailModel: email,
+		logger:       logger,
+		tokenCtrl:    token,
+		authCtrl:     auth,
+		captchaCtrl:  captcha,
+		passkeyCtrl:  passkey,
+		totpCtrl:     totp,
+		auditCtrl:    audit,
+		exportCtrl:   export,
+		ratingModel:  rating,
+		accountModel: account,
+		userModel:    user,
+		emailModel:   email,
+		sessionModel: session,
+		tokenModel:   personalToken,
 	}
 }
 
@@ -45,22 +74,21 @@ func (s *Service) Register(ctx context.Context, r *connect.Request[management.Re
 	}
 
 	if r.Msg.CaptchaId == "" {
-		id := captcha.New()
-		image := bytes.NewBuffer(nil)
-		if err := captcha.WriteImage(image, id, 128, 64); err != nil {
+		id, image, err := s.captchaCtrl.Generate()
+		if err != nil {
 			s.logger.Warn(fmt.Sprintf("captcha image failure: %v", err), "endpoint", "register")
 			return nil, connect.NewError(connect.CodeInternal, err)
 		}
 		return connect.NewResponse(&management.RegisterResponse{
-			CaptchaId: id, CaptchaBlob: image.Bytes(),
+			CaptchaId: id, CaptchaBlob: image,
 		}), nil
 	}
 	// captcha check is only skipped when the user is in the code stage.
-	if r.Msg.Verifier.Stage != manager.VerifierStage_VERIFIER_STAGE_CODE && !captcha.VerifyString(r.Msg.CaptchaId, r.Msg.CaptchaDigits) {
+	if r.Msg.Verifier.Stage != manager.VerifierStage_VERIFIER_STAGE_CODE && !s.captchaCtrl.Verify(r.Msg.CaptchaId, r.Msg.CaptchaDigits) {
 		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("incorrect captcha code"))
 	}
 
-	// precheck registration, even though PutRegistration() is atomic, this is required
+	// precheck registration, even though CreateAccount() is atomic, this is required
 	// to prevent users from sending unnecessary mails and to decrease chance of a userunfriendly registration failure.
 	_, found, err := s.emailModel.GetRegistration(ctx, r.Msg.User.Email)
 	if err != nil {
@@ -77,9 +105,7 @@ func (s *Service) Register(ctx context.Context, r *connect.Request[management.Re
 	}
 	userId := uuid.New().String()
 
-	// TODO: to make this super duper clean it should be a transaction, but this makes the code a bit messy.
-	// -> so rn if email registration fails (extremly unlikely) I just leave an orphaned account (but who cares tbh)
-	err = s.userModel.PutProfile(ctx, userId, &user.Profile{
+	err = s.accountModel.CreateAccount(ctx, userId, r.Msg.Verifier.Email, &user.Profile{
 		Username:    r.Msg.User.Username,
 		Description: r.Msg.User.Description,
 		Leaderboard: r.Msg.User.Leaderboard,
@@ -89,23 +115,18 @@ func (s *Service) Register(ctx context.Context, r *connect.Request[management.Re
 		MaxStreak:   0,
 	})
 	if err != nil {
-		s.logger.Warn(fmt.Sprintf("profile registration failure: %v", err), "endpoint", "register")
-		return nil, err
-	}
-	err = s.emailModel.PutRegistration(ctx, r.Msg.Verifier.Email, &email.Registration{
-		User: userId,
-	})
-	if err != nil {
-		s.logger.Error(fmt.Sprintf("email registration failure (orphaned profile left behind): %v", err), "endpoint", "register")
+		s.logger.Warn(fmt.Sprintf("account registration failure: %v", err), "endpoint", "register")
 		return nil, err
 	}
 	return connect.NewResponse(&management.RegisterResponse{}), nil
 }
 
 func (s *Service) Get(ctx context.Context, r *connect.Request[management.GetRequest]) (*connect.Response[management.GetResponse], error) {
-	claims, err := s.tokenCtrl.Verify(ctx, strings.TrimPrefix(r.Header().Get("Authorization"), "Bearer "))
+	claims, err := token.FromContext(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+		return nil, err
+	} else if err := claims.Allow(token.ScopeProfileRead); err != nil {
+		return nil, err
 	}
 
 	profile, found, err := s.userModel.GetProfile(ctx, claims.Subject)
@@ -114,6 +135,10 @@ func (s *Service) Get(ctx context.Context, r *connect.Request[management.GetRequ
 	} else if !found {
 		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("user not found"))
 	}
+	totpEnabled, err := s.totpCtrl.Enabled(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
 
 	return connect.NewResponse(&management.GetResponse{
 		User: &manager.User{
@@ -126,14 +151,20 @@ func (s *Service) Get(ctx context.Context, r *connect.Request[management.GetRequ
 			Score:       profile.Score,
 			Streak:      profile.Streak,
 			MaxStreak:   profile.MaxStreak,
+			TotpEnabled: totpEnabled,
 		},
 	}), nil
 }
 
 func (s *Service) Update(ctx context.Context, r *connect.Request[management.UpdateRequest]) (*connect.Response[management.UpdateResponse], error) {
-	claims, err := s.tokenCtrl.Verify(ctx, strings.TrimPrefix(r.Header().Get("Authorization"), "Bearer "))
+	claims, err := token.FromContext(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+	if r.Msg.User == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no user provided"))
 	}
 
 	err = s.userModel.UpdateProfile(ctx, claims.Subject, &user.Profile{
@@ -145,32 +176,547 @@ func (s *Service) Update(ctx context.Context, r *connect.Request[management.Upda
 		s.logger.Warn(fmt.Sprintf("profile update failure: %v", err), "endpoint", "update")
 		return nil, err
 	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeProfileUpdate, "", r); err != nil {
+		return nil, err
+	}
 	return connect.NewResponse(&management.UpdateResponse{}), nil
 }
 
 func (s *Service) Delete(ctx context.Context, r *connect.Request[management.DeleteRequest]) (*connect.Response[management.DeleteResponse], error) {
-	claims, err := s.tokenCtrl.Verify(ctx, strings.TrimPrefix(r.Header().Get("Authorization"), "Bearer "))
+	claims, err := token.FromContext(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
 	}
 
+	totpRequired, err := s.secondFactor(ctx, claims, r.Msg.Verifier, r.Msg.TotpCode)
+	if err != nil {
+		return nil, err
+	}
 	verified, err := s.authCtrl.Authenticate(ctx, r.Msg.Verifier)
 	if err != nil {
 		return nil, err
 	} else if !verified {
 		return connect.NewResponse(&management.DeleteResponse{}), nil
 	}
+	// the totp step is only consumed after the email code was accepted.
+	if totpRequired {
+		if err := s.totpCtrl.Verify(ctx, claims.Subject, r.Msg.TotpCode); err != nil {
+			return nil, err
+		}
+	}
 
-	// TODO transaction would be the super duper clean way here
-	err = s.userModel.DeleteProfile(ctx, claims.Subject)
+	profile, found, err := s.userModel.GetProfile(ctx, claims.Subject)
 	if err != nil {
-		s.logger.Warn(fmt.Sprintf("profile deletion failure: %v", err), "endpoint", "delete")
-		return nil, connect.NewError(connect.CodeInternal, err)
+		s.logger.Warn(fmt.Sprintf("profile lookup failure: %v", err), "endpoint", "delete")
+		return nil, err
+	} else if !found {
+		profile = &user.Profile{CreatedAt: time.Now().Unix()}
 	}
-	err = s.emailModel.DeleteRegistration(ctx, claims.Email)
+	// the deletion is queued before anything is removed, the leaderboard processor purges the user partition and
+	// the boards and retries until the purge is complete (a timeout of this function never leaves a half-deleted account).
+	err = s.ratingModel.SendUpdate(ctx, &rating.Update{
+		Time:    time.Unix(profile.CreatedAt, 0),
+		UserId:  claims.Subject,
+		Deleted: true,
+		Email:   claims.Email,
+	})
 	if err != nil {
-		s.logger.Error(fmt.Sprintf("email deletion failure (orphaned email left behind): %v", err), "endpoint", "delete")
-		return nil, connect.NewError(connect.CodeInternal, err)
+		s.logger.Warn(fmt.Sprintf("deletion enqueue failure: %v", err), "endpoint", "delete")
+		return nil, err
+	}
+	// removing the registration immediately revokes all tokens of the user (see token.Controller.Accounts).
+	err = s.accountModel.DeleteAccount(ctx, claims.Subject, claims.Email)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("account deletion failure: %v", err), "endpoint", "delete")
+		return nil, err
 	}
 	return connect.NewResponse(&management.DeleteResponse{}), nil
 }
+
+// ChangeEmail moves the account to the new email once both the new and the current email are verified.
+// Tokens carrying the old email are no longer accepted, the sessions and personal access tokens of the user are revoked
+// (the user must log in again).
+func (s *Service) ChangeEmail(ctx context.Context, r *connect.Request[management.ChangeEmailRequest]) (*connect.Response[management.ChangeEmailResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+	if r.Msg.Verifier == nil || r.Msg.Verifier.Email == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no new email provided"))
+	} else if r.Msg.Verifier.Email == claims.Email {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("new email matches the current email"))
+	}
+
+	// precheck registration to prevent sending unnecessary mails (MoveRegistration() is atomic anyway).
+	_, found, err := s.emailModel.GetRegistration(ctx, r.Msg.Verifier.Email)
+	if err != nil {
+		return nil, err
+	} else if found {
+		return nil, connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("email already associated with an account"))
+	}
+
+	// the new email only proves control over the new mailbox, the change must also be confirmed by the current one.
+	if r.Msg.CurrentVerifier.GetStage() != r.Msg.Verifier.Stage {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("verifiers of the current and the new email must be in the same stage"))
+	}
+	totpRequired, err := s.secondFactor(ctx, claims, r.Msg.CurrentVerifier, r.Msg.TotpCode)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := s.authCtrl.Authenticate(ctx, r.Msg.CurrentVerifier)
+	if err != nil {
+		return nil, err
+	}
+	newVerified, err := s.authCtrl.Authenticate(ctx, r.Msg.Verifier)
+	if err != nil {
+		return nil, err
+	} else if !verified || !newVerified {
+		return connect.NewResponse(&management.ChangeEmailResponse{}), nil
+	}
+	// the totp step is only consumed after the email code was accepted.
+	if totpRequired {
+		if err := s.totpCtrl.Verify(ctx, claims.Subject, r.Msg.TotpCode); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.emailModel.MoveRegistration(ctx, claims.Subject, claims.Email, r.Msg.Verifier.Email)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("email change failure: %v", err), "endpoint", "change_email")
+		return nil, err
+	}
+	// the registration is moved at this point, failures below leave credentials with the old email behind
+	// (they are rejected by the token verification anyway).
+	if err := s.passkeyCtrl.ChangeEmail(ctx, claims.Subject, r.Msg.Verifier.Email); err != nil {
+		s.logger.Error(fmt.Sprintf("passkey email update failure: %v", err), "endpoint", "change_email")
+		return nil, err
+	}
+	sessions, err := s.sessionModel.ListSessions(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if err := s.sessionModel.DeleteSession(ctx, claims.Subject, session.Id); err != nil {
+			s.logger.Error(fmt.Sprintf("session revocation failure: %v", err), "endpoint", "change_email")
+			return nil, err
+		}
+	}
+	personalTokens, err := s.tokenModel.ListPersonalTokens(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	for _, personalToken := range personalTokens {
+		if err := s.tokenModel.DeletePersonalToken(ctx, claims.Subject, personalToken.Id); err != nil {
+			s.logger.Error(fmt.Sprintf("personal token revocation failure: %v", err), "endpoint", "change_email")
+			return nil, err
+		}
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeEmailChange, r.Msg.Verifier.Email, r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.ChangeEmailResponse{}), nil
+}
+
+// Export returns the personal data of the user (profile, events and leaderboard entries) as zip archive.
+func (s *Service) Export(ctx context.Context, r *connect.Request[management.ExportRequest]) (*connect.Response[management.ExportResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	archive, err := s.exportCtrl.Archive(ctx, claims.Subject, claims.Email)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("export failure: %v", err), "endpoint", "export")
+		return nil, err
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeDataExport, "", r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.ExportResponse{
+		Archive:  archive,
+		Filename: fmt.Sprintf("zen-export-%s.zip", time.Now().UTC().Format(time.DateOnly)),
+	}), nil
+}
+
+func (s *Service) ListSessions(ctx context.Context, r *connect.Request[management.ListSessionsRequest]) (*connect.Response[management.ListSessionsResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionModel.ListSessions(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	resp := connect.NewResponse(&management.ListSessionsResponse{})
+	for _, session := range sessions {
+		resp.Msg.Sessions = append(resp.Msg.Sessions, &manager.Session{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			Origin:     session.Origin,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    claims.Session != "" && session.Id == claims.Session,
+		})
+	}
+	return resp, nil
+}
+
+// RevokeSession deletes the session which invalidates its refresh token.
+// Access tokens issued for the session are not revoked and stay valid until they expire.
+func (s *Service) RevokeSession(ctx context.Context, r *connect.Request[management.RevokeSessionRequest]) (*connect.Response[management.RevokeSessionResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	if !r.Msg.All {
+		if r.Msg.Id == "" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no session id provided"))
+		}
+		err = s.sessionModel.DeleteSession(ctx, claims.Subject, r.Msg.Id)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("session revocation failure: %v", err), "endpoint", "revoke_session")
+			return nil, err
+		}
+		if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeSessionRevoke, r.Msg.Id, r); err != nil {
+			return nil, err
+		}
+		return connect.NewResponse(&management.RevokeSessionResponse{}), nil
+	}
+
+	sessions, err := s.sessionModel.ListSessions(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		err = s.sessionModel.DeleteSession(ctx, claims.Subject, session.Id)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("session revocation failure: %v", err), "endpoint", "revoke_session")
+			return nil, err
+		}
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeSessionRevoke, "all", r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.RevokeSessionResponse{}), nil
+}
+
+// ListAuditEvents returns the audit trail of the caller, support staff can investigate the trail of other users.
+func (s *Service) ListAuditEvents(ctx context.Context, r *connect.Request[management.ListAuditEventsRequest]) (*connect.Response[management.ListAuditEventsResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	sub := r.Msg.Subject
+	if sub == "" {
+		sub = claims.Subject
+	}
+	until := time.Now()
+	if r.Msg.Until != 0 {
+		until = time.Unix(r.Msg.Until, 0)
+	}
+	events, err := s.auditCtrl.List(ctx, claims.Subject, sub, time.Unix(r.Msg.Since, 0), until)
+	if err != nil {
+		return nil, err
+	}
+	resp := connect.NewResponse(&management.ListAuditEventsResponse{Events: []*manager.AuditEvent{}})
+	for _, event := range events {
+		resp.Msg.Events = append(resp.Msg.Events, &manager.AuditEvent{
+			Id:        event.Id,
+			Type:      event.Type,
+			Detail:    event.Detail,
+			UserAgent: event.UserAgent,
+			Origin:    event.Origin,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) BeginPasskeyRegistration(ctx context.Context, r *connect.Request[management.BeginPasskeyRegistrationRequest]) (*connect.Response[management.BeginPasskeyRegistrationResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	id, options, err := s.passkeyCtrl.BeginRegistration(ctx, claims.Subject, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.BeginPasskeyRegistrationResponse{
+		CeremonyId: id,
+		Options:    string(options),
+	}), nil
+}
+
+func (s *Service) FinishPasskeyRegistration(ctx context.Context, r *connect.Request[management.FinishPasskeyRegistrationRequest]) (*connect.Response[management.FinishPasskeyRegistrationResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	key, err := s.passkeyCtrl.FinishRegistration(ctx, claims.Subject, claims.Email, r.Msg.CeremonyId, r.Msg.Name, []byte(r.Msg.Credential))
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("passkey registration failure: %v", err), "endpoint", "finish_passkey_registration")
+		return nil, err
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypePasskeyAdd, key.Name, r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.FinishPasskeyRegistrationResponse{
+		Passkey: &manager.Passkey{
+			Id:         key.Id,
+			Name:       key.Name,
+			CreatedAt:  key.CreatedAt,
+			LastUsedAt: key.LastUsedAt,
+		},
+	}), nil
+}
+
+func (s *Service) ListPasskeys(ctx context.Context, r *connect.Request[management.ListPasskeysRequest]) (*connect.Response[management.ListPasskeysResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.passkeyCtrl.List(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	resp := connect.NewResponse(&management.ListPasskeysResponse{})
+	for _, key := range keys {
+		resp.Msg.Passkeys = append(resp.Msg.Passkeys, &manager.Passkey{
+			Id:         key.Id,
+			Name:       key.Name,
+			CreatedAt:  key.CreatedAt,
+			LastUsedAt: key.LastUsedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) DeletePasskey(ctx context.Context, r *connect.Request[management.DeletePasskeyRequest]) (*connect.Response[management.DeletePasskeyResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	} else if r.Msg.Id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no passkey id provided"))
+	}
+
+	err = s.passkeyCtrl.Delete(ctx, claims.Subject, r.Msg.Id)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("passkey deletion failure: %v", err), "endpoint", "delete_passkey")
+		return nil, err
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypePasskeyDelete, r.Msg.Id, r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.DeletePasskeyResponse{}), nil
+}
+
+func (s *Service) EnrollTotp(ctx context.Context, r *connect.Request[management.EnrollTotpRequest]) (*connect.Response[management.EnrollTotpResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.totpCtrl.Enroll(ctx, claims.Subject, claims.Email)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("totp enrollment failure: %v", err), "endpoint", "enroll_totp")
+		return nil, err
+	}
+	return connect.NewResponse(&management.EnrollTotpResponse{
+		Uri:    enrollment.Uri,
+		Secret: enrollment.Secret,
+		Qr:     enrollment.Qr,
+	}), nil
+}
+
+func (s *Service) ConfirmTotp(ctx context.Context, r *connect.Request[management.ConfirmTotpRequest]) (*connect.Response[management.ConfirmTotpResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.totpCtrl.Confirm(ctx, claims.Subject, r.Msg.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeTotpEnable, "", r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.ConfirmTotpResponse{
+		RecoveryCodes: recoveryCodes,
+	}), nil
+}
+
+func (s *Service) DisableTotp(ctx context.Context, r *connect.Request[management.DisableTotpRequest]) (*connect.Response[management.DisableTotpResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	err = s.totpCtrl.Disable(ctx, claims.Subject, r.Msg.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypeTotpDisable, "", r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.DisableTotpResponse{}), nil
+}
+
+func (s *Service) CreatePersonalToken(ctx context.Context, r *connect.Request[management.CreatePersonalTokenRequest]) (*connect.Response[management.CreatePersonalTokenResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+	if r.Msg.Name == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no token name provided"))
+	} else if len(r.Msg.Scopes) < 1 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no scopes provided"))
+	}
+	for _, scope := range r.Msg.Scopes {
+		if !slices.Contains(token.Scopes, scope) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown scope '%s'; expected one of %v", scope, token.Scopes))
+		}
+	}
+	// the days are checked before they are converted, large values overflow the duration.
+	maxDays := int64(PersonalTokenMaxTTL / (24 * time.Hour))
+	if r.Msg.ExpiresInDays < 0 || r.Msg.ExpiresInDays > maxDays {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("token lifetime must be between 1 and %d days", maxDays))
+	}
+	ttl := personalTokenDefaultTTL
+	if r.Msg.ExpiresInDays > 0 {
+		ttl = time.Duration(r.Msg.ExpiresInDays) * 24 * time.Hour
+	}
+
+	scopes := slices.Compact(slices.Sorted(slices.Values(r.Msg.Scopes)))
+	expiresAt := time.Now().Add(ttl)
+	signedToken, id, err := s.tokenCtrl.IssuePersonal(ctx, claims.Subject, claims.Email, scopes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	personalToken := &user.PersonalToken{
+		Name:      r.Msg.Name,
+		Scopes:    scopes,
+		CreatedAt: time.Now().Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}
+	err = s.tokenModel.PutPersonalToken(ctx, claims.Subject, id, personalToken)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("personal token creation failure: %v", err), "endpoint", "create_personal_token")
+		return nil, err
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypePersonalTokenCreate, personalToken.Name, r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.CreatePersonalTokenResponse{
+		PersonalToken: &manager.PersonalToken{
+			Id:        personalToken.Id,
+			Name:      personalToken.Name,
+			Scopes:    personalToken.Scopes,
+			CreatedAt: personalToken.CreatedAt,
+			ExpiresAt: personalToken.ExpiresAt,
+		},
+		Token: signedToken,
+	}), nil
+}
+
+func (s *Service) ListPersonalTokens(ctx context.Context, r *connect.Request[management.ListPersonalTokensRequest]) (*connect.Response[management.ListPersonalTokensResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	}
+
+	personalTokens, err := s.tokenModel.ListPersonalTokens(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	resp := connect.NewResponse(&management.ListPersonalTokensResponse{})
+	for _, personalToken := range personalTokens {
+		resp.Msg.PersonalTokens = append(resp.Msg.PersonalTokens, &manager.PersonalToken{
+			Id:        personalToken.Id,
+			Name:      personalToken.Name,
+			Scopes:    personalToken.Scopes,
+			CreatedAt: personalToken.CreatedAt,
+			ExpiresAt: personalToken.ExpiresAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) RevokePersonalToken(ctx context.Context, r *connect.Request[management.RevokePersonalTokenRequest]) (*connect.Response[management.RevokePersonalTokenResponse], error) {
+	claims, err := token.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	} else if err := claims.Interactive(); err != nil {
+		return nil, err
+	} else if r.Msg.Id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no token id provided"))
+	}
+
+	err = s.tokenModel.DeletePersonalToken(ctx, claims.Subject, r.Msg.Id)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("personal token revocation failure: %v", err), "endpoint", "revoke_personal_token")
+		return nil, err
+	}
+	if err := s.auditCtrl.Record(ctx, claims.Subject, audit.TypePersonalTokenRevoke, r.Msg.Id, r); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&management.RevokePersonalTokenResponse{}), nil
+}
+
+// secondFactor checks that the verifier belongs to the email of the account (otherwise a leaked access token and
+// any mailbox are sufficient) and reports if the code stage must be completed with the totp code of the user.
+// A missing totp code is rejected before the email code is consumed, the totp code itself is verified after
+// the email code was accepted, so that an incorrect email code does not consume the totp step.
+func (s *Service) secondFactor(ctx context.Context, claims *token.TokenClaims, verifier *manager.Verifier, totpCode string) (bool, error) {
+	if verifier.GetEmail() != claims.Email {
+		return false, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("verifier email does not match the account"))
+	} else if verifier.Stage != manager.VerifierStage_VERIFIER_STAGE_CODE {
+		return false, nil
+	}
+	totpEnabled, err := s.totpCtrl.Enabled(ctx, claims.Subject)
+	if err != nil {
+		return false, err
+	} else if !totpEnabled {
+		return false, nil
+	} else if totpCode == "" {
+		return false, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("two-factor code required"))
+	}
+	return true, nil
+}
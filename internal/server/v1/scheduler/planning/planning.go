@@ -4,7 +4,6 @@ import (
 	"context"
 	"log/slog"
 	"strconv"
-	"strings"
 	"time"
 
 	"connectrpc.com/connect"
@@ -16,22 +15,22 @@ import (
 
 type Service struct {
 	logger    *slog.Logger
-	tokenCtrl *token.Controller
-	userModel *user.Model
+	userModel user.EventStore
 }
 
-func New(logger *slog.Logger, token *token.Controller, user *user.Model) *Service {
+func New(logger *slog.Logger, user user.EventStore) *Service {
 	return &Service{
 		logger:    logger,
-		tokenCtrl: token,
 		userModel: user,
 	}
 }
 
 func (s *Service) Get(ctx context.Context, r *connect.Request[planning.GetRequest]) (*connect.Response[planning.GetResponse], error) {
-	claims, err := s.tokenCtrl.Verify(ctx, strings.TrimPrefix(r.Header().Get("Authorization"), "Bearer "))
+	claims, err := token.FromContext(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+		return nil, err
+	} else if err := claims.Allow(token.ScopePlanningRead); err != nil {
+		return nil, err
 	}
 	events, err := s.userModel.ListEvents(ctx, claims.Subject, time.Unix(r.Msg.Since, 0), time.Unix(r.Msg.Until, 0))
 	if err != nil {
@@ -58,9 +57,11 @@ func (s *Service) Get(ctx context.Context, r *connect.Request[planning.GetReques
 }
 
 func (s *Service) Upsert(ctx context.Context, r *connect.Request[planning.UpsertRequest]) (*connect.Response[planning.UpsertResponse], error) {
-	claims, err := s.tokenCtrl.Verify(ctx, strings.TrimPrefix(r.Header().Get("Authorization"), "Bearer "))
+	claims, err := token.FromContext(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+		return nil, err
+	} else if err := claims.Allow(token.ScopePlanningWrite); err != nil {
+		return nil, err
 	}
 	oldEvents := map[string]bool{}
 	newEvents := []user.Event{}
@@ -88,9 +89,11 @@ func (s *Service) Upsert(ctx context.Context, r *connect.Request[planning.Upsert
 }
 
 func (s *Service) Delete(ctx context.Context, r *connect.Request[planning.DeleteRequest]) (*connect.Response[planning.DeleteResponse], error) {
-	claims, err := s.tokenCtrl.Verify(ctx, strings.TrimPrefix(r.Header().Get("Authorization"), "Bearer "))
+	claims, err := token.FromContext(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+		return nil, err
+	} else if err := claims.Allow(token.ScopePlanningWrite); err != nil {
+		return nil, err
 	}
 	err = s.userModel.DeleteEvent(ctx, claims.Subject, r.Msg.Id)
 	if err != nil {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"strings"
 	"time"
 
 	"connectrpc.com/connect"
@@ -17,16 +16,14 @@ import (
 
 type Service struct {
 	logger       *slog.Logger
-	tokenCtrl    *token.Controller
-	userModel    *user.Model
-	ratingModel  *rating.Model
+	userModel    user.Store
+	ratingModel  rating.Sender
 	ratingAnchor time.Duration
 }
 
-func New(logger *slog.Logger, token *token.Controller, user *user.Model, rating *rating.Model, ratingAnchor time.Duration) *Service {
+func New(logger *slog.Logger, user user.Store, rating rating.Sender, ratingAnchor time.Duration) *Service {
 	return &Service{
 		logger:       logger,
-		tokenCtrl:    token,
 		userModel:    user,
 		ratingModel:  rating,
 		ratingAnchor: ratingAnchor,
@@ -34,9 +31,11 @@ func New(logger *slog.Logger, token *token.Controller, user *user.Model, rating
 }
 
 func (s *Service) Start(ctx context.Context, r *connect.Request[timing.StartRequest]) (*connect.Response[timing.StartResponse], error) {
-	claims, err := s.tokenCtrl.Verify(ctx, strings.TrimPrefix(r.Header().Get("Authorization"), "Bearer "))
+	claims, err := token.FromContext(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+		return nil, err
+	} else if err := claims.Allow(token.ScopeTiming); err != nil {
+		return nil, err
 	}
 	event, found, err := s.userModel.GetEvent(ctx, claims.Subject, r.Msg.Id)
 	if err != nil {
@@ -56,9 +55,11 @@ func (s *Service) Start(ctx context.Context, r *connect.Request[timing.StartRequ
 }
 
 func (s *Service) Stop(ctx context.Context, r *connect.Request[timing.StopRequest]) (*connect.Response[timing.StopResponse], error) {
-	claims, err := s.tokenCtrl.Verify(ctx, strings.TrimPrefix(r.Header().Get("Authorization"), "Bearer "))
+	claims, err := token.FromContext(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+		return nil, err
+	} else if err := claims.Allow(token.ScopeTiming); err != nil {
+		return nil, err
 	}
 
 	profile, found, err := s.userModel.GetProfile(ctx, claims.Subject)
@@ -0,0 +1,94 @@
+// package wellknown serves the discovery documents of the zen token issuer (/.well-known/...).
+// Other services use them to verify zen access tokens offline (without calling the manager or kms).
+package wellknown
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/megakuul/zen/internal/token"
+)
+
+const (
+	Path          = "/.well-known/"
+	jwksPath      = Path + "jwks.json"
+	discoveryPath = Path + "openid-configuration"
+)
+
+// cacheControl allows verifiers to cache the documents, keys must therefore be published ahead of their use.
+const cacheControl = "public, max-age=300"
+
+type Service struct {
+	logger    *slog.Logger
+	tokenCtrl *token.Controller
+}
+
+func New(logger *slog.Logger, token *token.Controller) *Service {
+	return &Service{
+		logger:    logger,
+		tokenCtrl: token,
+	}
+}
+
+// Handler returns the path and handler to mount on the mux (like the generated connect handlers).
+func (s *Service) Handler() (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+jwksPath, s.jwks)
+	mux.HandleFunc("GET "+discoveryPath, s.discovery)
+	return Path, mux
+}
+
+func (s *Service) jwks(w http.ResponseWriter, r *http.Request) {
+	set, err := s.tokenCtrl.JWKS(r.Context())
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("jwks failure: %v", err), "endpoint", "jwks")
+		http.Error(w, "failed to load signing keys", http.StatusInternalServerError)
+		return
+	}
+	s.write(w, "jwks", set)
+}
+
+// Discovery is the subset of the openid provider metadata (OpenID Connect Discovery 1.0) that is relevant to
+// verify tokens. Zen is not a general purpose openid provider, it only issues tokens to its own clients.
+type Discovery struct {
+	Issuer                           string   `json:"issuer"`
+	JwksUri                          string   `json:"jwks_uri"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IdTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
+
+func (s *Service) discovery(w http.ResponseWriter, r *http.Request) {
+	s.write(w, "discovery", &Discovery{
+		Issuer:                           s.tokenCtrl.Issuer,
+		JwksUri:                          issuerUrl(s.tokenCtrl.Issuer) + jwksPath,
+		ResponseTypesSupported:           []string{"token"},
+		SubjectTypesSupported:            []string{"public"},
+		IdTokenSigningAlgValuesSupported: s.tokenCtrl.Verifier.Methods(),
+		ClaimsSupported:                  []string{"iss", "aud", "sub", "exp", "iat", "nbf", "jti", "email", "sid", "pat", "scopes"},
+	})
+}
+
+func (s *Service) write(w http.ResponseWriter, endpoint string, document any) {
+	rawDocument, err := json.Marshal(document)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("%s failure: %v", endpoint, err), "endpoint", endpoint)
+		http.Error(w, "failed to encode document", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Write(rawDocument)
+}
+
+// issuerUrl returns the url the issuer is served on, the issuer is typically the plain domain (served with https).
+func issuerUrl(issuer string) string {
+	if strings.Contains(issuer, "://") {
+		return strings.TrimSuffix(issuer, "/")
+	}
+	return "https://" + issuer
+}
@@ -0,0 +1,634 @@
+package testenv_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/megakuul/zen/internal/auth"
+	"github.com/megakuul/zen/internal/server/wellknown"
+	"github.com/megakuul/zen/internal/testenv"
+	"github.com/megakuul/zen/internal/token"
+	"github.com/megakuul/zen/pkg/api/v1/manager"
+	"github.com/megakuul/zen/pkg/api/v1/manager/authentication"
+	"github.com/megakuul/zen/pkg/api/v1/manager/management"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/planning"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestRefreshRotation(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		env.Login(t, "monk@zen.test", true)
+
+		initial := env.RefreshCookie()
+		if initial == "" {
+			t.Fatalf("login did not set a refresh cookie")
+		}
+		if _, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{Verifier: &manager.Verifier{}})); err != nil {
+			t.Fatalf("failed to refresh token: %v", err)
+		}
+		rotated := env.RefreshCookie()
+		if rotated == "" || rotated == initial {
+			t.Fatalf("refresh token was not rotated")
+		}
+
+		// a concurrent refresh with the rotated token (second tab) gets an access token but no new cookie.
+		concurrent, err := env.Anonymous().Login(ctx, testenv.WithRefreshCookie(&authentication.LoginRequest{Verifier: &manager.Verifier{}}, initial))
+		if err != nil {
+			t.Fatalf("expected concurrent refresh to succeed, got: %v", err)
+		} else if concurrent.Msg.Token == "" || concurrent.Header().Get("Set-Cookie") != "" {
+			t.Fatalf("expected concurrent refresh to only return an access token")
+		}
+		if _, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{Verifier: &manager.Verifier{}})); err != nil {
+			t.Fatalf("failed to refresh token: %v", err)
+		}
+		if latest := env.RefreshCookie(); latest == "" || latest == rotated {
+			t.Fatalf("refresh token was not rotated")
+		}
+
+		// replaying a token outside of the grace period revokes the whole family, including the latest token.
+		_, err = env.Anonymous().Login(ctx, testenv.WithRefreshCookie(&authentication.LoginRequest{Verifier: &manager.Verifier{}}, initial))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected reused token to be rejected, got: %v", err)
+		}
+		_, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{Verifier: &manager.Verifier{}}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected token family to be revoked, got: %v", err)
+		}
+	})
+}
+
+func TestLogoutRevocation(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		env.Login(t, "monk@zen.test", true)
+
+		refreshToken := env.RefreshCookie()
+		if _, err := env.Authentication.Logout(ctx, connect.NewRequest(&authentication.LogoutRequest{})); err != nil {
+			t.Fatalf("failed to logout: %v", err)
+		}
+		if env.RefreshCookie() != "" {
+			t.Fatalf("logout did not expire the refresh cookie")
+		}
+		_, err := env.Anonymous().Login(ctx, testenv.WithRefreshCookie(&authentication.LoginRequest{Verifier: &manager.Verifier{}}, refreshToken))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected revoked token to be rejected, got: %v", err)
+		}
+	})
+}
+
+func TestCodeReplay(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		env.Login(t, "monk@zen.test", false)
+
+		_, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+		}))
+		if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Fatalf("expected used code to be rejected, got: %v", err)
+		}
+	})
+}
+
+func TestCodeLockout(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		_, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+		}))
+		if err != nil {
+			t.Fatalf("failed to initiate login: %v", err)
+		}
+
+		for i := range 5 {
+			_, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+				Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: "nope"},
+			}))
+			if i < 4 && connect.CodeOf(err) != connect.CodePermissionDenied {
+				t.Fatalf("expected incorrect code to be rejected, got: %v", err)
+			}
+		}
+		if connect.CodeOf(err) != connect.CodeResourceExhausted {
+			t.Fatalf("expected email to be locked, got: %v", err)
+		}
+		// the correct code is not accepted while locked.
+		_, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+		}))
+		if connect.CodeOf(err) != connect.CodeResourceExhausted {
+			t.Fatalf("expected locked email to reject the correct code, got: %v", err)
+		}
+
+		// parallel guesses are counted before the code is checked, they cannot pass the lockout. The controller is
+		// called directly, the rate limiter in front of the api serializes the requests of one client.
+		env.Register(t, &manager.User{Email: "nomad@zen.test", Username: "nomad"})
+		authCtrl := auth.New(env.Emails, env.Ceremonies, env.Token, env.Audit, env.Mailer, "https://zen.test/login/link")
+		if _, err := authCtrl.Authenticate(ctx, &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "nomad@zen.test"}); err != nil {
+			t.Fatalf("failed to send code: %v", err)
+		}
+		if env.Dynamo != nil {
+			env.Dynamo.SetLatency(5 * time.Millisecond)
+		}
+		var wg sync.WaitGroup
+		var checked atomic.Int64
+		for range 15 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := authCtrl.Authenticate(ctx, &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "nomad@zen.test", Code: "nope"})
+				if connect.CodeOf(err) == connect.CodePermissionDenied {
+					checked.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		if checked.Load() > 4 {
+			t.Fatalf("expected at most 4 rejected guesses before the lockout, got %d", checked.Load())
+		}
+		attempts, found, err := env.Emails.GetAttempts(ctx, "nomad@zen.test")
+		if err != nil || !found {
+			t.Fatalf("failed to get attempts (found: %t): %v", found, err)
+		} else if attempts.Failures > 5 {
+			t.Fatalf("expected at most 5 checked guesses, got %d", attempts.Failures)
+		}
+	})
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		sub := env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		token := env.Login(t, "monk@zen.test", false)
+
+		authenticator := testenv.NewAuthenticator(t)
+		creation, err := env.Management.BeginPasskeyRegistration(ctx, testenv.Authorize(&management.BeginPasskeyRegistrationRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to begin passkey registration: %v", err)
+		}
+		registration, err := env.Management.FinishPasskeyRegistration(ctx, testenv.Authorize(&management.FinishPasskeyRegistrationRequest{
+			CeremonyId: creation.Msg.CeremonyId,
+			Name:       "phone",
+			Credential: authenticator.Create(t, creation.Msg.Options),
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to finish passkey registration: %v", err)
+		}
+
+		assertion, err := env.Authentication.BeginPasskeyLogin(ctx, connect.NewRequest(&authentication.BeginPasskeyLoginRequest{}))
+		if err != nil {
+			t.Fatalf("failed to begin passkey login: %v", err)
+		}
+		credential := authenticator.Get(t, assertion.Msg.Options)
+		login, err := env.Authentication.FinishPasskeyLogin(ctx, connect.NewRequest(&authentication.FinishPasskeyLoginRequest{
+			CeremonyId: assertion.Msg.CeremonyId,
+			Credential: credential,
+		}))
+		if err != nil {
+			t.Fatalf("failed to finish passkey login: %v", err)
+		}
+		claims, err := env.Token.Verify(ctx, login.Msg.Token)
+		if err != nil || claims.Subject != sub || claims.Email != "monk@zen.test" {
+			t.Fatalf("passkey login issued an invalid token: %v", err)
+		}
+
+		// every ceremony can only be finished once.
+		_, err = env.Authentication.FinishPasskeyLogin(ctx, connect.NewRequest(&authentication.FinishPasskeyLoginRequest{
+			CeremonyId: assertion.Msg.CeremonyId,
+			Credential: credential,
+		}))
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			t.Fatalf("expected finished ceremony to be rejected, got: %v", err)
+		}
+
+		_, err = env.Management.DeletePasskey(ctx, testenv.Authorize(&management.DeletePasskeyRequest{Id: registration.Msg.Passkey.Id}, token))
+		if err != nil {
+			t.Fatalf("failed to delete passkey: %v", err)
+		}
+		assertion, err = env.Authentication.BeginPasskeyLogin(ctx, connect.NewRequest(&authentication.BeginPasskeyLoginRequest{}))
+		if err != nil {
+			t.Fatalf("failed to begin passkey login: %v", err)
+		}
+		_, err = env.Authentication.FinishPasskeyLogin(ctx, connect.NewRequest(&authentication.FinishPasskeyLoginRequest{
+			CeremonyId: assertion.Msg.CeremonyId,
+			Credential: authenticator.Get(t, assertion.Msg.Options),
+		}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected deleted passkey to be rejected, got: %v", err)
+		}
+	})
+}
+
+func TestTotp(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		token := env.Login(t, "monk@zen.test", false)
+
+		enrollment, err := env.Management.EnrollTotp(ctx, testenv.Authorize(&management.EnrollTotpRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to enroll totp: %v", err)
+		} else if enrollment.Msg.Uri == "" || len(enrollment.Msg.Qr) == 0 {
+			t.Fatalf("enrollment did not return the otpauth uri and qr code")
+		}
+		// every step is accepted once, subsequent codes are generated for the adjacent steps (accepted due to clock drift).
+		code := func(offset time.Duration) string {
+			code, err := totp.GenerateCode(enrollment.Msg.Secret, time.Now().Add(offset))
+			if err != nil {
+				t.Fatalf("failed to generate totp code: %v", err)
+			}
+			return code
+		}
+		confirmation, err := env.Management.ConfirmTotp(ctx, testenv.Authorize(&management.ConfirmTotpRequest{Code: code(0)}, token))
+		if err != nil {
+			t.Fatalf("failed to confirm totp: %v", err)
+		} else if len(confirmation.Msg.RecoveryCodes) == 0 {
+			t.Fatalf("confirmation did not return recovery codes")
+		}
+
+		// the code stage no longer issues a token, it must be completed with the totp code.
+		_, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+		}))
+		if err != nil {
+			t.Fatalf("failed to initiate login: %v", err)
+		}
+		login, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+		}))
+		if err != nil {
+			t.Fatalf("failed to complete code stage: %v", err)
+		} else if login.Msg.Token != "" || login.Msg.TotpCeremonyId == "" {
+			t.Fatalf("expected totp ceremony instead of token, got: %v", login.Msg)
+		}
+		_, err = env.Authentication.VerifyTotp(ctx, connect.NewRequest(&authentication.VerifyTotpRequest{
+			CeremonyId: login.Msg.TotpCeremonyId, Code: "000000",
+		}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected incorrect totp code to be rejected, got: %v", err)
+		}
+		// the ceremony stays open after an incorrect code.
+		used := code(30 * time.Second)
+		verified, err := env.Authentication.VerifyTotp(ctx, connect.NewRequest(&authentication.VerifyTotpRequest{
+			CeremonyId: login.Msg.TotpCeremonyId, Code: used,
+		}))
+		if err != nil {
+			t.Fatalf("failed to verify totp: %v", err)
+		} else if verified.Msg.Token == "" {
+			t.Fatalf("totp verification did not return an access token")
+		}
+		_, err = env.Authentication.VerifyTotp(ctx, connect.NewRequest(&authentication.VerifyTotpRequest{
+			CeremonyId: login.Msg.TotpCeremonyId, Code: code(30 * time.Second),
+		}))
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			t.Fatalf("expected completed ceremony to be rejected, got: %v", err)
+		}
+		// the step is accepted once per user, also outside of the ceremony.
+		_, err = env.Management.DisableTotp(ctx, testenv.Authorize(&management.DisableTotpRequest{Code: used}, token))
+		if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Fatalf("expected used totp step to be rejected, got: %v", err)
+		}
+
+		// account deletion requires the second factor in the code stage.
+		_, err = env.Management.Delete(ctx, testenv.Authorize(&management.DeleteRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to initiate deletion: %v", err)
+		}
+		_, err = env.Management.Delete(ctx, testenv.Authorize(&management.DeleteRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+		}, token))
+		if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Fatalf("expected deletion without totp code to be rejected, got: %v", err)
+		}
+
+		// the totp code is only verified (and consumed) after the email code was accepted.
+		_, err = env.Management.Delete(ctx, testenv.Authorize(&management.DeleteRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: "nope"},
+			TotpCode: confirmation.Msg.RecoveryCodes[0],
+		}, token))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected deletion with incorrect email code to be rejected, got: %v", err)
+		}
+		_, err = env.Management.Delete(ctx, testenv.Authorize(&management.DeleteRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+			TotpCode: "000000",
+		}, token))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected deletion with incorrect totp code to be rejected, got: %v", err)
+		}
+
+		// the recovery code was not consumed by the rejected deletion, recovery codes are single use.
+		_, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+		}))
+		if err != nil {
+			t.Fatalf("failed to initiate login: %v", err)
+		}
+		login, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+		}))
+		if err != nil {
+			t.Fatalf("failed to complete code stage: %v", err)
+		}
+		_, err = env.Authentication.VerifyTotp(ctx, connect.NewRequest(&authentication.VerifyTotpRequest{
+			CeremonyId: login.Msg.TotpCeremonyId, Code: confirmation.Msg.RecoveryCodes[0],
+		}))
+		if err != nil {
+			t.Fatalf("failed to verify totp with recovery code: %v", err)
+		}
+		_, err = env.Management.DisableTotp(ctx, testenv.Authorize(&management.DisableTotpRequest{Code: confirmation.Msg.RecoveryCodes[0]}, token))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected used recovery code to be rejected, got: %v", err)
+		}
+		_, err = env.Management.DisableTotp(ctx, testenv.Authorize(&management.DisableTotpRequest{Code: confirmation.Msg.RecoveryCodes[1]}, token))
+		if err != nil {
+			t.Fatalf("failed to disable totp with recovery code: %v", err)
+		}
+		user, err := env.Management.Get(ctx, testenv.Authorize(&management.GetRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to get user: %v", err)
+		} else if user.Msg.User.TotpEnabled {
+			t.Fatalf("expected totp to be disabled")
+		}
+		env.Login(t, "monk@zen.test", false)
+	})
+}
+
+func TestOidcLogin(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		sub := env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+
+		begin, err := env.Authentication.BeginOidcLogin(ctx, connect.NewRequest(&authentication.BeginOidcLoginRequest{AutoRefresh: true}))
+		if err != nil {
+			t.Fatalf("failed to begin oidc login: %v", err)
+		}
+		state, code := env.IdP.Authorize(t, begin.Msg.Url, "monk@zen.test", true)
+		login, err := env.Authentication.FinishOidcLogin(ctx, connect.NewRequest(&authentication.FinishOidcLoginRequest{State: state, Code: code}))
+		if err != nil {
+			t.Fatalf("failed to finish oidc login: %v", err)
+		}
+		claims, err := env.Token.Verify(ctx, login.Msg.Token)
+		if err != nil {
+			t.Fatalf("oidc login returned an invalid token: %v", err)
+		} else if claims.Subject != sub || claims.Email != "monk@zen.test" {
+			t.Fatalf("oidc login issued a token for the wrong user: %v", claims)
+		} else if env.RefreshCookie() == "" {
+			t.Fatalf("oidc login with auto_refresh did not set the refresh cookie")
+		}
+		// the state is single use (the device cookie is removed after the login).
+		_, err = env.Authentication.FinishOidcLogin(ctx, connect.NewRequest(&authentication.FinishOidcLoginRequest{State: state, Code: code}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected replayed state to be rejected, got: %v", err)
+		}
+
+		// login csrf: the victim must not finish a login started (and authorized) by the attacker.
+		env.Register(t, &manager.User{Email: "mallory@zen.test", Username: "mallory"})
+		// the victim has a device cookie of its own login, it does not match the state of the attacker.
+		if _, err := env.Authentication.BeginOidcLogin(ctx, connect.NewRequest(&authentication.BeginOidcLoginRequest{})); err != nil {
+			t.Fatalf("failed to begin oidc login: %v", err)
+		}
+		attacker := env.Anonymous()
+		begin, err = attacker.BeginOidcLogin(ctx, connect.NewRequest(&authentication.BeginOidcLoginRequest{}))
+		if err != nil {
+			t.Fatalf("failed to begin oidc login: %v", err)
+		}
+		state, code = env.IdP.Authorize(t, begin.Msg.Url, "mallory@zen.test", true)
+		_, err = env.Authentication.FinishOidcLogin(ctx, connect.NewRequest(&authentication.FinishOidcLoginRequest{State: state, Code: code}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected state of another device to be rejected, got: %v", err)
+		}
+
+		begin, err = env.Authentication.BeginOidcLogin(ctx, connect.NewRequest(&authentication.BeginOidcLoginRequest{}))
+		if err != nil {
+			t.Fatalf("failed to begin oidc login: %v", err)
+		}
+		state, code = env.IdP.Authorize(t, begin.Msg.Url, "monk@zen.test", false)
+		_, err = env.Authentication.FinishOidcLogin(ctx, connect.NewRequest(&authentication.FinishOidcLoginRequest{State: state, Code: code}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected unverified email to be rejected, got: %v", err)
+		}
+
+		begin, err = env.Authentication.BeginOidcLogin(ctx, connect.NewRequest(&authentication.BeginOidcLoginRequest{}))
+		if err != nil {
+			t.Fatalf("failed to begin oidc login: %v", err)
+		}
+		state, code = env.IdP.Authorize(t, begin.Msg.Url, "nomad@zen.test", true)
+		_, err = env.Authentication.FinishOidcLogin(ctx, connect.NewRequest(&authentication.FinishOidcLoginRequest{State: state, Code: code}))
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			t.Fatalf("expected unregistered email to be rejected, got: %v", err)
+		}
+	})
+}
+
+func TestMagicLinkLogin(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		_, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier:    &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+			AutoRefresh: true,
+			MagicLink:   true,
+		}))
+		if err != nil {
+			t.Fatalf("failed to request sign-in link: %v", err)
+		}
+		if env.Mailer.Last(t, "monk@zen.test").Html == "" {
+			t.Fatalf("expected mail to contain an html alternative")
+		}
+		linkToken := env.Mailer.LinkToken(t, "monk@zen.test")
+		device := env.Cookie("link_device")
+		if device == "" {
+			t.Fatalf("sign-in request did not set a device cookie")
+		}
+
+		// the link is bound to the device that requested it.
+		_, err = env.Anonymous().Login(ctx, connect.NewRequest(&authentication.LoginRequest{LinkToken: linkToken}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected link from another device to be rejected, got: %v", err)
+		}
+		resp, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{LinkToken: linkToken}))
+		if err != nil {
+			t.Fatalf("failed to login with sign-in link: %v", err)
+		} else if resp.Msg.Token == "" || env.RefreshCookie() == "" {
+			t.Fatalf("expected sign-in link to issue a token and refresh cookie")
+		}
+		if env.Cookie("link_device") != "" {
+			t.Fatalf("login did not expire the device cookie")
+		}
+
+		// links are single use, the code of the link is consumed as well.
+		_, err = env.Anonymous().Login(ctx, testenv.WithCookie(&authentication.LoginRequest{LinkToken: linkToken}, "link_device", device))
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			t.Fatalf("expected used link to be rejected, got: %v", err)
+		}
+		_, err = env.Anonymous().Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+		}))
+		if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Fatalf("expected code of the used link to be rejected, got: %v", err)
+		}
+
+		// access tokens are not accepted as link token.
+		_, err = env.Anonymous().Login(ctx, testenv.WithCookie(&authentication.LoginRequest{LinkToken: resp.Msg.Token}, "link_device", device))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected access token to be rejected as link token, got: %v", err)
+		}
+	})
+}
+
+func TestJwks(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		accessToken := env.Login(t, "monk@zen.test", false)
+
+		discovery := &wellknown.Discovery{}
+		getJson(t, env, "/.well-known/openid-configuration", discovery)
+		if discovery.Issuer != env.Token.Issuer {
+			t.Fatalf("expected issuer '%s'; got '%s'", env.Token.Issuer, discovery.Issuer)
+		} else if !strings.HasSuffix(discovery.JwksUri, "/.well-known/jwks.json") {
+			t.Fatalf("unexpected jwks uri '%s'", discovery.JwksUri)
+		}
+		set := &token.JWKSet{}
+		getJson(t, env, "/.well-known/jwks.json", set)
+		if len(set.Keys) != 1 || set.Keys[0].Kty != "EC" || set.Keys[0].Alg != "ES256" || set.Keys[0].Kid == "" {
+			t.Fatalf("unexpected key set: %+v", set.Keys)
+		}
+
+		// verify the token like an external service that only knows the published key.
+		jwk := set.Keys[0]
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			t.Fatalf("failed to decode x: %v", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			t.Fatalf("failed to decode y: %v", err)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		claims := &token.TokenClaims{}
+		_, err = jwt.ParseWithClaims(accessToken, claims, func(t *jwt.Token) (any, error) {
+			return key, nil
+		}, jwt.WithValidMethods([]string{jwk.Alg}), jwt.WithIssuer(discovery.Issuer), jwt.WithAudience(discovery.Issuer))
+		if err != nil {
+			t.Fatalf("failed to verify token with published key: %v", err)
+		} else if claims.Email != "monk@zen.test" {
+			t.Fatalf("expected email claim 'monk@zen.test'; got '%s'", claims.Email)
+		}
+	})
+}
+
+func getJson(t *testing.T, env *testenv.Env, path string, document any) {
+	t.Helper()
+	resp, err := env.Client.Get(env.Server.URL + path)
+	if err != nil {
+		t.Fatalf("failed to get '%s': %v", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to get '%s': status %d", path, resp.StatusCode)
+	} else if resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected content type '%s'", resp.Header.Get("Content-Type"))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(document); err != nil {
+		t.Fatalf("failed to decode '%s': %v", path, err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		setProvider := func(provider token.Provider) {
+			env.Token.Signer = provider
+			env.Token.Verifier = provider
+			env.Token.Publisher = provider
+		}
+		previousKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		previous, err := token.NewLocal(previousKey)
+		if err != nil {
+			t.Fatalf("failed to create provider: %v", err)
+		}
+		setProvider(previous)
+		previousToken := env.Login(t, "monk@zen.test", false)
+		// tokens issued before key ids were introduced have no kid header.
+		legacyClaims := &token.TokenClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(previousToken, legacyClaims); err != nil {
+			t.Fatalf("failed to parse token: %v", err)
+		}
+		legacyToken, err := jwt.NewWithClaims(jwt.SigningMethodES256, legacyClaims).SignedString(previousKey)
+		if err != nil {
+			t.Fatalf("failed to sign legacy token: %v", err)
+		}
+		next, err := token.GenerateLocal("ES256")
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+
+		// stage: the next key is published but does not sign tokens.
+		setProvider(token.NewKeyRing(previous, next))
+		set := &token.JWKSet{}
+		getJson(t, env, "/.well-known/jwks.json", set)
+		if len(set.Keys) != 2 {
+			t.Fatalf("expected 2 published keys; got %d", len(set.Keys))
+		}
+		if kid := keyId(t, env.Login(t, "monk@zen.test", false)); kid != set.Keys[0].Kid {
+			t.Fatalf("expected token to be signed by the active key '%s'; got '%s'", set.Keys[0].Kid, kid)
+		}
+
+		// switch: the next key signs tokens, tokens of the previous key are still accepted.
+		setProvider(token.NewKeyRing(next, previous))
+		nextToken := env.Login(t, "monk@zen.test", false)
+		if kid := keyId(t, nextToken); kid != set.Keys[1].Kid {
+			t.Fatalf("expected token to be signed by the next key '%s'; got '%s'", set.Keys[1].Kid, kid)
+		}
+		for _, accessToken := range []string{previousToken, legacyToken, nextToken} {
+			if _, err := env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{}, accessToken)); err != nil {
+				t.Fatalf("expected token to be accepted during rotation: %v", err)
+			}
+		}
+
+		// retire: tokens of the previous key are rejected.
+		setProvider(token.NewKeyRing(next))
+		if _, err := env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{}, nextToken)); err != nil {
+			t.Fatalf("expected token of the active key to be accepted: %v", err)
+		}
+		_, err = env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{}, previousToken))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected token of the retired key to be rejected, got: %v", err)
+		}
+	})
+}
+
+// keyId returns the kid header of the token (without verifying it).
+func keyId(t *testing.T, accessToken string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(accessToken, &token.TokenClaims{})
+	if err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
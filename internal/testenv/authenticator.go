@@ -0,0 +1,154 @@
+package testenv
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	// rpId and rpOrigin configure the webauthn relying party of the environment.
+	rpId     = "zen.test"
+	rpOrigin = "https://zen.test"
+
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+// Authenticator is a software webauthn authenticator holding a single p256 passkey (with none attestation).
+// It plays the part of navigator.credentials.create() and navigator.credentials.get() in the browser.
+type Authenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialId []byte
+	userHandle   []byte
+	counter      uint32
+}
+
+func NewAuthenticator(t testing.TB) *Authenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("cannot generate authenticator key: %v", err)
+	}
+	credentialId := make([]byte, 32)
+	rand.Read(credentialId)
+	return &Authenticator{key: key, credentialId: credentialId}
+}
+
+// Create creates the passkey from the json encoded creation options and returns the json encoded public key credential.
+func (a *Authenticator) Create(t testing.TB, options string) string {
+	t.Helper()
+	creation := struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+			User      struct {
+				Id string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}{}
+	if err := json.Unmarshal([]byte(options), &creation); err != nil {
+		t.Fatalf("invalid creation options: %v", err)
+	}
+	userHandle, err := base64.RawURLEncoding.DecodeString(creation.PublicKey.User.Id)
+	if err != nil {
+		t.Fatalf("invalid user handle: %v", err)
+	}
+	a.userHandle = userHandle
+
+	publicKey, err := cbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		-3: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatalf("cannot encode public key: %v", err)
+	}
+	authData := a.authData(flagUserPresent | flagUserVerified | flagAttested)
+	authData = append(authData, make([]byte, 16)...) // aaguid
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialId)))
+	authData = append(authData, a.credentialId...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := cbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatalf("cannot encode attestation: %v", err)
+	}
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    encode(clientData("webauthn.create", creation.PublicKey.Challenge)),
+		"attestationObject": encode(attestation),
+	})
+}
+
+// Get signs the challenge of the json encoded request options and returns the json encoded public key credential.
+func (a *Authenticator) Get(t testing.TB, options string) string {
+	t.Helper()
+	assertion := struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}{}
+	if err := json.Unmarshal([]byte(options), &assertion); err != nil {
+		t.Fatalf("invalid request options: %v", err)
+	}
+	a.counter++
+	authData := a.authData(flagUserPresent | flagUserVerified)
+	clientDataJson := clientData("webauthn.get", assertion.PublicKey.Challenge)
+	clientDataHash := sha256.Sum256(clientDataJson)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("cannot sign assertion: %v", err)
+	}
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    encode(clientDataJson),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(a.userHandle),
+	})
+}
+
+func (a *Authenticator) authData(flags byte) []byte {
+	rpIdHash := sha256.Sum256([]byte(rpId))
+	authData := append(rpIdHash[:], flags)
+	return binary.BigEndian.AppendUint32(authData, a.counter)
+}
+
+func (a *Authenticator) credential(t testing.TB, response map[string]string) string {
+	credential, err := json.Marshal(map[string]any{
+		"id":       encode(a.credentialId),
+		"rawId":    encode(a.credentialId),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatalf("cannot encode credential: %v", err)
+	}
+	return string(credential)
+}
+
+func clientData(ceremony, challenge string) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    rpOrigin,
+	})
+	return data
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
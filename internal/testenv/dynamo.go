@@ -0,0 +1,499 @@
+package testenv
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// dynamoTarget is the prefix of the operation header of the dynamodb json protocol.
+const dynamoTarget = "DynamoDB_20120810."
+
+// Dynamo is an in-memory dynamodb that speaks the json protocol of the aws sdk, the dynamodb models run unmodified against it.
+// Only the operations and the expression subset used by the models are implemented: items are keyed by pk and sk (strings),
+// there are no indexes, no size limits and no ttl sweeps (expired items stay until they are deleted, like in dynamodb).
+type Dynamo struct {
+	Server *httptest.Server
+
+	lock        sync.Mutex
+	tables      map[string]map[dynamoKey]dynamoItem
+	pageSize    int
+	unprocessed int
+	latency     time.Duration
+}
+
+// NewDynamo starts a new dynamodb stand-in, it is stopped automatically when the test finishes.
+func NewDynamo(t testing.TB) *Dynamo {
+	t.Helper()
+	d := &Dynamo{tables: map[string]map[dynamoKey]dynamoItem{}}
+	d.Server = httptest.NewServer(d)
+	t.Cleanup(d.Server.Close)
+	return d
+}
+
+// Client returns a dynamodb client that sends its requests to the stand-in.
+func (d *Dynamo) Client() *dynamodb.Client {
+	return dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(d.Server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("zen", "zen", ""),
+		HTTPClient:   d.Server.Client(),
+	})
+}
+
+// SetPageSize limits the items evaluated by one query or scan page (like the 1 MB limit of dynamodb), zero disables paging.
+func (d *Dynamo) SetPageSize(size int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.pageSize = size
+}
+
+// FailWrites returns the next n requests of batch writes as unprocessed (like an exceeded write capacity).
+func (d *Dynamo) FailWrites(n int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.unprocessed = n
+}
+
+// SetLatency delays every request (outside of the table lock), so concurrent requests interleave like on the real service.
+func (d *Dynamo) SetLatency(latency time.Duration) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.latency = latency
+}
+
+// dynamoValue is the wire format of an attribute value (exactly one field is set).
+type dynamoValue struct {
+	S    *string                  `json:"S,omitempty"`
+	N    *string                  `json:"N,omitempty"`
+	B    []byte                   `json:"B,omitempty"`
+	BOOL *bool                    `json:"BOOL,omitempty"`
+	NULL *bool                    `json:"NULL,omitempty"`
+	SS   []string                 `json:"SS,omitempty"`
+	NS   []string                 `json:"NS,omitempty"`
+	BS   [][]byte                 `json:"BS,omitempty"`
+	L    *[]*dynamoValue          `json:"L,omitempty"`
+	M    *map[string]*dynamoValue `json:"M,omitempty"`
+}
+
+// dynamoItem maps attribute names to values. Values are never modified in place, so copying the map copies the item.
+type dynamoItem map[string]*dynamoValue
+
+type dynamoKey struct {
+	pk string
+	sk string
+}
+
+// key extracts the primary key of the item.
+func (i dynamoItem) key() dynamoKey {
+	pk, sk := i["pk"], i["sk"]
+	if pk == nil || pk.S == nil || sk == nil || sk.S == nil {
+		panic(validationError("the key must contain the string attributes pk and sk"))
+	}
+	return dynamoKey{pk: *pk.S, sk: *sk.S}
+}
+
+// primary returns the item that only contains the primary key.
+func (k dynamoKey) primary() dynamoItem {
+	return dynamoItem{"pk": &dynamoValue{S: aws.String(k.pk)}, "sk": &dynamoValue{S: aws.String(k.sk)}}
+}
+
+// project returns a copy of the item that only contains the projected attributes (all if there is no projection).
+func (i dynamoItem) project(attributes []string) dynamoItem {
+	if i == nil {
+		return nil
+	}
+	projected := dynamoItem{}
+	for name, value := range i {
+		if attributes == nil || slices.Contains(attributes, name) {
+			projected[name] = value
+		}
+	}
+	return projected
+}
+
+// dynamoRequest contains the union of the fields of all implemented operations.
+type dynamoRequest struct {
+	TableName                           string
+	Key                                 dynamoItem
+	Item                                dynamoItem
+	ConditionExpression                 string
+	UpdateExpression                    string
+	KeyConditionExpression              string
+	FilterExpression                    string
+	ProjectionExpression                string
+	ExpressionAttributeNames            map[string]string
+	ExpressionAttributeValues           map[string]*dynamoValue
+	ReturnValues                        string
+	ReturnValuesOnConditionCheckFailure string
+	ScanIndexForward                    *bool
+	Limit                               int
+	ExclusiveStartKey                   dynamoItem
+	RequestItems                        map[string][]*dynamoWriteRequest
+	TransactItems                       []*dynamoTransactItem
+}
+
+type dynamoWriteRequest struct {
+	PutRequest    *struct{ Item dynamoItem } `json:",omitempty"`
+	DeleteRequest *struct{ Key dynamoItem }  `json:",omitempty"`
+}
+
+type dynamoTransactItem struct {
+	Put            *dynamoRequest
+	Delete         *dynamoRequest
+	Update         *dynamoRequest
+	ConditionCheck *dynamoRequest
+}
+
+// dynamoError is an error response, the type is the name of the exception in the aws sdk.
+type dynamoError struct {
+	Type                string                `json:"__type"`
+	Message             string                `json:"message"`
+	Item                dynamoItem            `json:"Item,omitempty"`
+	CancellationReasons []*dynamoCancellation `json:"CancellationReasons,omitempty"`
+}
+
+type dynamoCancellation struct {
+	Code    string
+	Message string     `json:",omitempty"`
+	Item    dynamoItem `json:",omitempty"`
+}
+
+func (e *dynamoError) Error() string {
+	return fmt.Sprint(e.Type, ": ", e.Message)
+}
+
+func validationError(format string, args ...any) *dynamoError {
+	return &dynamoError{Type: "ValidationException", Message: fmt.Sprintf(format, args...)}
+}
+
+func (d *Dynamo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := &dynamoRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		d.respond(w, http.StatusBadRequest, &dynamoError{Type: "SerializationException", Message: err.Error()})
+		return
+	}
+	d.lock.Lock()
+	latency := d.latency
+	d.lock.Unlock()
+	time.Sleep(latency)
+	resp, err := d.handle(strings.TrimPrefix(r.Header.Get("X-Amz-Target"), dynamoTarget), req)
+	if err != nil {
+		err.Type = "com.amazonaws.dynamodb.v20120810#" + err.Type
+		d.respond(w, http.StatusBadRequest, err)
+		return
+	}
+	d.respond(w, http.StatusOK, resp)
+}
+
+func (d *Dynamo) respond(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// handle executes the operation, errors are raised as *dynamoError panics by the operations and the expression evaluation.
+func (d *Dynamo) handle(op string, req *dynamoRequest) (resp map[string]any, err *dynamoError) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	defer func() {
+		if r := recover(); r != nil {
+			if rErr, ok := r.(*dynamoError); ok {
+				resp, err = nil, rErr
+				return
+			}
+			panic(r)
+		}
+	}()
+	switch op {
+	case "GetItem":
+		return d.getItem(req), nil
+	case "PutItem":
+		return d.putItem(req), nil
+	case "DeleteItem":
+		return d.deleteItem(req), nil
+	case "UpdateItem":
+		return d.updateItem(req), nil
+	case "Query":
+		return d.query(req), nil
+	case "Scan":
+		return d.scan(req), nil
+	case "BatchWriteItem":
+		return d.batchWriteItem(req), nil
+	case "TransactWriteItems":
+		return d.transactWriteItems(req), nil
+	default:
+		return nil, &dynamoError{Type: "UnknownOperationException", Message: fmt.Sprintf("operation '%s' is not implemented", op)}
+	}
+}
+
+func (d *Dynamo) table(name string) map[dynamoKey]dynamoItem {
+	table, ok := d.tables[name]
+	if !ok {
+		table = map[dynamoKey]dynamoItem{}
+		d.tables[name] = table
+	}
+	return table
+}
+
+// attributes returns the item as response field (omitted if there is no item).
+func attributes(resp map[string]any, field string, item dynamoItem) map[string]any {
+	if len(item) > 0 {
+		resp[field] = item
+	}
+	return resp
+}
+
+func (d *Dynamo) getItem(req *dynamoRequest) map[string]any {
+	e := newExpression(req)
+	item := d.table(req.TableName)[req.Key.key()]
+	return attributes(map[string]any{}, "Item", item.project(e.projection(req.ProjectionExpression)))
+}
+
+func (d *Dynamo) putItem(req *dynamoRequest) map[string]any {
+	key := req.Item.key()
+	old := d.check(req, key)
+	d.table(req.TableName)[key] = req.Item
+	if req.ReturnValues == "ALL_OLD" {
+		return attributes(map[string]any{}, "Attributes", old)
+	}
+	return map[string]any{}
+}
+
+func (d *Dynamo) deleteItem(req *dynamoRequest) map[string]any {
+	key := req.Key.key()
+	old := d.check(req, key)
+	delete(d.table(req.TableName), key)
+	if req.ReturnValues == "ALL_OLD" {
+		return attributes(map[string]any{}, "Attributes", old)
+	}
+	return map[string]any{}
+}
+
+func (d *Dynamo) updateItem(req *dynamoRequest) map[string]any {
+	key := req.Key.key()
+	old := d.check(req, key)
+	item, updated := d.update(req, key, old)
+	d.table(req.TableName)[key] = item
+	switch req.ReturnValues {
+	case "ALL_OLD":
+		return attributes(map[string]any{}, "Attributes", old)
+	case "ALL_NEW":
+		return attributes(map[string]any{}, "Attributes", item)
+	case "UPDATED_OLD":
+		return attributes(map[string]any{}, "Attributes", old.project(updated))
+	case "UPDATED_NEW":
+		return attributes(map[string]any{}, "Attributes", item.project(updated))
+	default:
+		return map[string]any{}
+	}
+}
+
+// check evaluates the condition against the stored item and returns it.
+func (d *Dynamo) check(req *dynamoRequest, key dynamoKey) dynamoItem {
+	old := d.table(req.TableName)[key]
+	if req.ConditionExpression == "" {
+		return old
+	}
+	e := newExpression(req)
+	if !e.condition(req.ConditionExpression)(old) {
+		err := &dynamoError{Type: "ConditionalCheckFailedException", Message: "The conditional request failed"}
+		if req.ReturnValuesOnConditionCheckFailure == "ALL_OLD" {
+			err.Item = old
+		}
+		panic(err)
+	}
+	return old
+}
+
+// update applies the update expression to a copy of the stored item (the item is created if it does not exist).
+func (d *Dynamo) update(req *dynamoRequest, key dynamoKey, old dynamoItem) (dynamoItem, []string) {
+	item := key.primary()
+	for name, value := range old {
+		item[name] = value
+	}
+	updated := newExpression(req).update(req.UpdateExpression, old, item)
+	return item, updated
+}
+
+func (d *Dynamo) query(req *dynamoRequest) map[string]any {
+	e := newExpression(req)
+	match := e.condition(req.KeyConditionExpression)
+	items := []dynamoItem{}
+	for _, item := range d.table(req.TableName) {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+	order := func(a, b dynamoItem) int {
+		return cmp.Compare(a.key().sk, b.key().sk)
+	}
+	if req.ScanIndexForward != nil && !*req.ScanIndexForward {
+		order = func(a, b dynamoItem) int {
+			return cmp.Compare(b.key().sk, a.key().sk)
+		}
+	}
+	return d.page(req, e, items, order)
+}
+
+func (d *Dynamo) scan(req *dynamoRequest) map[string]any {
+	items := []dynamoItem{}
+	for _, item := range d.table(req.TableName) {
+		items = append(items, item)
+	}
+	return d.page(req, newExpression(req), items, func(a, b dynamoItem) int {
+		return cmp.Or(cmp.Compare(a.key().pk, b.key().pk), cmp.Compare(a.key().sk, b.key().sk))
+	})
+}
+
+// page evaluates the items that follow the start key in the order until the limit is reached,
+// the filter is applied to the evaluated items. The start key does not have to exist anymore.
+func (d *Dynamo) page(req *dynamoRequest, e *expression, items []dynamoItem, order func(a, b dynamoItem) int) map[string]any {
+	slices.SortFunc(items, order)
+	if req.ExclusiveStartKey != nil {
+		start := req.ExclusiveStartKey.key().primary()
+		items = slices.DeleteFunc(items, func(item dynamoItem) bool {
+			return order(item, start) <= 0
+		})
+	}
+	limit := req.Limit
+	if d.pageSize > 0 && (limit < 1 || d.pageSize < limit) {
+		limit = d.pageSize
+	}
+	resp := map[string]any{}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+		resp["LastEvaluatedKey"] = items[limit-1].key().primary()
+	}
+	filter := func(dynamoItem) bool { return true }
+	if req.FilterExpression != "" {
+		filter = e.condition(req.FilterExpression)
+	}
+	projection := e.projection(req.ProjectionExpression)
+	results := []dynamoItem{}
+	for _, item := range items {
+		if filter(item) {
+			results = append(results, item.project(projection))
+		}
+	}
+	resp["Items"] = results
+	resp["Count"] = len(results)
+	resp["ScannedCount"] = len(items)
+	return resp
+}
+
+func (d *Dynamo) batchWriteItem(req *dynamoRequest) map[string]any {
+	unprocessed := map[string][]*dynamoWriteRequest{}
+	for name, requests := range req.RequestItems {
+		table := d.table(name)
+		for _, request := range requests {
+			if d.unprocessed > 0 {
+				d.unprocessed--
+				unprocessed[name] = append(unprocessed[name], request)
+			} else if request.PutRequest != nil {
+				table[request.PutRequest.Item.key()] = request.PutRequest.Item
+			} else if request.DeleteRequest != nil {
+				delete(table, request.DeleteRequest.Key.key())
+			}
+		}
+	}
+	return map[string]any{"UnprocessedItems": unprocessed}
+}
+
+// transactWriteItems checks all conditions before any item is written. If a condition fails, the transaction is
+// canceled with a reason for every action (in the order of the actions).
+func (d *Dynamo) transactWriteItems(req *dynamoRequest) map[string]any {
+	type write struct {
+		table string
+		key   dynamoKey
+		item  dynamoItem // nil deletes the item
+	}
+	writes := []write{}
+	reasons := []*dynamoCancellation{}
+	canceled := false
+	for _, action := range req.TransactItems {
+		var (
+			r   *dynamoRequest
+			key dynamoKey
+		)
+		switch {
+		case action.Put != nil:
+			r, key = action.Put, action.Put.Item.key()
+		case action.Delete != nil:
+			r, key = action.Delete, action.Delete.Key.key()
+		case action.Update != nil:
+			r, key = action.Update, action.Update.Key.key()
+		case action.ConditionCheck != nil:
+			r, key = action.ConditionCheck, action.ConditionCheck.Key.key()
+		default:
+			panic(validationError("transaction action is not set"))
+		}
+		for _, w := range writes {
+			if w.table == r.TableName && w.key == key {
+				panic(validationError("transaction request cannot include multiple operations on one item"))
+			}
+		}
+
+		old, reason := d.checkAction(r, key)
+		reasons = append(reasons, reason)
+		if reason.Code != "None" {
+			canceled = true
+			continue
+		}
+		switch {
+		case action.Put != nil:
+			writes = append(writes, write{r.TableName, key, r.Item})
+		case action.Delete != nil:
+			writes = append(writes, write{r.TableName, key, nil})
+		case action.Update != nil:
+			item, _ := d.update(r, key, old)
+			writes = append(writes, write{r.TableName, key, item})
+		case action.ConditionCheck != nil:
+			writes = append(writes, write{r.TableName, key, old})
+		}
+	}
+	if canceled {
+		codes := []string{}
+		for _, reason := range reasons {
+			codes = append(codes, reason.Code)
+		}
+		panic(&dynamoError{
+			Type:                "TransactionCanceledException",
+			Message:             fmt.Sprintf("Transaction cancelled, please refer cancellation reasons for specific reasons [%s]", strings.Join(codes, ", ")),
+			CancellationReasons: reasons,
+		})
+	}
+	for _, w := range writes {
+		if w.item == nil {
+			delete(d.table(w.table), w.key)
+		} else {
+			d.table(w.table)[w.key] = w.item
+		}
+	}
+	return map[string]any{}
+}
+
+// checkAction evaluates the condition of a transaction action and returns the stored item and the cancellation reason.
+func (d *Dynamo) checkAction(req *dynamoRequest, key dynamoKey) (old dynamoItem, reason *dynamoCancellation) {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(*dynamoError); ok && err.Type == "ConditionalCheckFailedException" {
+				reason = &dynamoCancellation{Code: "ConditionalCheckFailed", Message: err.Message, Item: err.Item}
+				return
+			}
+			panic(r)
+		}
+	}()
+	return d.check(req, key), &dynamoCancellation{Code: "None"}
+}
@@ -0,0 +1,460 @@
+package testenv
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// dynamoReserved contains the reserved words that are stored as attributes by the models (they must use a name placeholder).
+var dynamoReserved = []string{"COUNT", "DATA", "DATE", "KEY", "NAME", "SIZE", "STATUS", "TIMESTAMP", "TOKEN", "TYPE", "USER", "VALUE"}
+
+// expression evaluates the condition, update and projection expressions of a request. Only top-level attributes are
+// supported (no nested paths) and the subset of functions used by the models.
+type expression struct {
+	names  map[string]string
+	values map[string]*dynamoValue
+}
+
+func newExpression(req *dynamoRequest) *expression {
+	return &expression{names: req.ExpressionAttributeNames, values: req.ExpressionAttributeValues}
+}
+
+type (
+	condition func(dynamoItem) bool
+	operand   func(dynamoItem) *dynamoValue
+)
+
+// condition parses a condition, filter or key condition expression.
+func (e *expression) condition(src string) condition {
+	p := e.parse(src)
+	c := p.or()
+	p.end()
+	return c
+}
+
+// projection parses the projected attribute names, nil projects all attributes.
+func (e *expression) projection(src string) []string {
+	if src == "" {
+		return nil
+	}
+	p := e.parse(src)
+	attributes := []string{}
+	for more := true; more; more = p.accept(",") {
+		attributes = append(attributes, p.path())
+	}
+	p.end()
+	return attributes
+}
+
+// update applies the update expression to the item and returns the updated attribute names.
+// All operands are evaluated against the old item, like in dynamodb.
+func (e *expression) update(src string, old, item dynamoItem) []string {
+	p := e.parse(src)
+	updated := []string{}
+	for p.peek() != "" {
+		clause := strings.ToUpper(p.next())
+		for more := true; more; more = p.accept(",") {
+			name := p.path()
+			if name == "pk" || name == "sk" || slices.Contains(updated, name) {
+				panic(validationError("attribute '%s' cannot be updated in expression '%s'", name, src))
+			}
+			switch clause {
+			case "SET":
+				p.expect("=")
+				item[name] = p.value()(old)
+			case "REMOVE":
+				delete(item, name)
+			case "ADD":
+				if value := add(old[name], p.operand()(old)); value != nil {
+					item[name] = value
+				}
+			case "DELETE":
+				if value := remove(old[name], p.operand()(old)); value != nil {
+					item[name] = value
+				} else {
+					delete(item, name)
+				}
+			default:
+				panic(validationError("invalid clause '%s' in update expression '%s'", clause, src))
+			}
+			updated = append(updated, name)
+		}
+	}
+	if len(updated) < 1 {
+		panic(validationError("update expression '%s' is empty", src))
+	}
+	return updated
+}
+
+type parser struct {
+	e      *expression
+	src    string
+	tokens []string
+	pos    int
+}
+
+func (e *expression) parse(src string) *parser {
+	p := &parser{e: e, src: src}
+	for i := 0; i < len(src); {
+		switch c := src[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "<>") || strings.HasPrefix(src[i:], "<=") || strings.HasPrefix(src[i:], ">="):
+			p.tokens = append(p.tokens, src[i:i+2])
+			i += 2
+		case strings.IndexByte("=<>(),+-", c) >= 0:
+			p.tokens = append(p.tokens, src[i:i+1])
+			i++
+		default:
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '#' || src[j] == ':' ||
+				'a' <= src[j] && src[j] <= 'z' || 'A' <= src[j] && src[j] <= 'Z' || '0' <= src[j] && src[j] <= '9') {
+				j++
+			}
+			if j == i {
+				panic(validationError("invalid character '%c' in expression '%s'", c, src))
+			}
+			p.tokens = append(p.tokens, src[i:j])
+			i = j
+		}
+	}
+	return p
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	if token == "" {
+		panic(validationError("unexpected end of expression '%s'", p.src))
+	}
+	p.pos++
+	return token
+}
+
+// accept consumes the token if it matches (keywords are case insensitive).
+func (p *parser) accept(token string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], token) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(token string) {
+	if !p.accept(token) {
+		panic(validationError("expected '%s' at token %d of expression '%s'", token, p.pos, p.src))
+	}
+}
+
+func (p *parser) end() {
+	if p.pos < len(p.tokens) {
+		panic(validationError("unexpected token '%s' in expression '%s'", p.tokens[p.pos], p.src))
+	}
+}
+
+func (p *parser) or() condition {
+	left := p.and()
+	for p.accept("OR") {
+		l, r := left, p.and()
+		left = func(i dynamoItem) bool { return l(i) || r(i) }
+	}
+	return left
+}
+
+func (p *parser) and() condition {
+	left := p.not()
+	for p.accept("AND") {
+		l, r := left, p.not()
+		left = func(i dynamoItem) bool { return l(i) && r(i) }
+	}
+	return left
+}
+
+func (p *parser) not() condition {
+	if p.accept("NOT") {
+		c := p.not()
+		return func(i dynamoItem) bool { return !c(i) }
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() condition {
+	if p.accept("(") {
+		c := p.or()
+		p.expect(")")
+		return c
+	}
+	switch function := strings.ToLower(p.peek()); function {
+	case "attribute_exists", "attribute_not_exists":
+		p.pos++
+		p.expect("(")
+		name := p.path()
+		p.expect(")")
+		return func(i dynamoItem) bool {
+			_, ok := i[name]
+			return ok == (function == "attribute_exists")
+		}
+	case "begins_with", "contains":
+		p.pos++
+		p.expect("(")
+		left := p.operand()
+		p.expect(",")
+		right := p.operand()
+		p.expect(")")
+		if function == "begins_with" {
+			return func(i dynamoItem) bool { return beginsWith(left(i), right(i)) }
+		}
+		return func(i dynamoItem) bool { return contains(left(i), right(i)) }
+	}
+
+	left := p.operand()
+	if p.accept("BETWEEN") {
+		low := p.operand()
+		p.expect("AND")
+		high := p.operand()
+		return func(i dynamoItem) bool {
+			l, lOk := compare(left(i), low(i))
+			h, hOk := compare(left(i), high(i))
+			return lOk && hOk && l >= 0 && h <= 0
+		}
+	}
+	comparator := p.next()
+	if !slices.Contains([]string{"=", "<>", "<", "<=", ">", ">="}, comparator) {
+		panic(validationError("invalid comparator '%s' in expression '%s'", comparator, p.src))
+	}
+	right := p.operand()
+	return func(i dynamoItem) bool {
+		// comparisons with a missing attribute are false (also <>).
+		l, r := left(i), right(i)
+		if l == nil || r == nil {
+			return false
+		}
+		switch comparator {
+		case "=":
+			return equal(l, r)
+		case "<>":
+			return !equal(l, r)
+		}
+		c, ok := compare(l, r)
+		switch comparator {
+		case "<":
+			return ok && c < 0
+		case "<=":
+			return ok && c <= 0
+		case ">":
+			return ok && c > 0
+		default:
+			return ok && c >= 0
+		}
+	}
+}
+
+// value parses the value of a set action (operand, operand + operand or operand - operand).
+func (p *parser) value() operand {
+	left := p.operand()
+	negate := false
+	if p.accept("-") {
+		negate = true
+	} else if !p.accept("+") {
+		return left
+	}
+	right := p.operand()
+	return func(i dynamoItem) *dynamoValue {
+		l, r := left(i), right(i)
+		if l == nil || r == nil || l.N == nil || r.N == nil {
+			panic(validationError("an operand in the update expression has an incorrect data type"))
+		}
+		delta := number(*r.N)
+		if negate {
+			delta.Neg(delta)
+		}
+		return numberValue(new(big.Rat).Add(number(*l.N), delta))
+	}
+}
+
+func (p *parser) operand() operand {
+	token := p.peek()
+	if strings.EqualFold(token, "if_not_exists") {
+		p.pos++
+		p.expect("(")
+		name := p.path()
+		p.expect(",")
+		fallback := p.operand()
+		p.expect(")")
+		return func(i dynamoItem) *dynamoValue {
+			if value, ok := i[name]; ok {
+				return value
+			}
+			return fallback(i)
+		}
+	} else if strings.HasPrefix(token, ":") {
+		p.pos++
+		value, ok := p.e.values[token]
+		if !ok {
+			panic(validationError("value '%s' is not defined for expression '%s'", token, p.src))
+		}
+		return func(dynamoItem) *dynamoValue { return value }
+	}
+	name := p.path()
+	return func(i dynamoItem) *dynamoValue { return i[name] }
+}
+
+// path parses an attribute name, placeholders are replaced by the expression attribute names.
+func (p *parser) path() string {
+	token := p.next()
+	if strings.HasPrefix(token, "#") {
+		name, ok := p.e.names[token]
+		if !ok {
+			panic(validationError("name '%s' is not defined for expression '%s'", token, p.src))
+		}
+		return name
+	} else if strings.HasPrefix(token, ":") || strings.IndexByte("=<>(),+-", token[0]) >= 0 {
+		panic(validationError("expected attribute name instead of '%s' in expression '%s'", token, p.src))
+	} else if slices.Contains(dynamoReserved, strings.ToUpper(token)) {
+		panic(validationError("attribute name is a reserved keyword; reserved keyword: %s", token))
+	}
+	return token
+}
+
+func number(n string) *big.Rat {
+	r, ok := new(big.Rat).SetString(n)
+	if !ok {
+		panic(validationError("invalid number '%s'", n))
+	}
+	return r
+}
+
+func numberValue(r *big.Rat) *dynamoValue {
+	n := r.Num().String()
+	if !r.IsInt() {
+		n = strings.TrimRight(r.FloatString(38), "0")
+	}
+	return &dynamoValue{N: &n}
+}
+
+// compare orders two values of the same scalar type (number, string or binary), ok is false if they are not comparable.
+func compare(a, b *dynamoValue) (c int, ok bool) {
+	switch {
+	case a == nil || b == nil:
+		return 0, false
+	case a.N != nil && b.N != nil:
+		return number(*a.N).Cmp(number(*b.N)), true
+	case a.S != nil && b.S != nil:
+		return strings.Compare(*a.S, *b.S), true
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B), true
+	}
+	return 0, false
+}
+
+func equal(a, b *dynamoValue) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(canonical(a), canonical(b))
+}
+
+// canonical sorts the elements of sets, sets are unordered.
+func canonical(v *dynamoValue) *dynamoValue {
+	if v == nil || (v.SS == nil && v.NS == nil && v.BS == nil) {
+		return v
+	}
+	c := &dynamoValue{SS: slices.Sorted(slices.Values(v.SS)), BS: slices.SortedFunc(slices.Values(v.BS), bytes.Compare)}
+	for _, n := range v.NS {
+		c.NS = append(c.NS, *numberValue(number(n)).N)
+	}
+	slices.Sort(c.NS)
+	return c
+}
+
+func beginsWith(a, prefix *dynamoValue) bool {
+	switch {
+	case a == nil || prefix == nil:
+		return false
+	case a.S != nil && prefix.S != nil:
+		return strings.HasPrefix(*a.S, *prefix.S)
+	case a.B != nil && prefix.B != nil:
+		return bytes.HasPrefix(a.B, prefix.B)
+	}
+	return false
+}
+
+// contains checks if a string contains the substring, or if a set or list contains the element.
+func contains(a, element *dynamoValue) bool {
+	switch {
+	case a == nil || element == nil:
+		return false
+	case a.S != nil && element.S != nil:
+		return strings.Contains(*a.S, *element.S)
+	case a.SS != nil && element.S != nil:
+		return slices.Contains(a.SS, *element.S)
+	case a.NS != nil && element.N != nil:
+		return slices.ContainsFunc(a.NS, func(n string) bool { return number(n).Cmp(number(*element.N)) == 0 })
+	case a.BS != nil && element.B != nil:
+		return slices.ContainsFunc(a.BS, func(b []byte) bool { return bytes.Equal(b, element.B) })
+	case a.L != nil:
+		return slices.ContainsFunc(*a.L, func(v *dynamoValue) bool { return equal(v, element) })
+	}
+	return false
+}
+
+// add implements the add action for numbers and sets, the value is created if the attribute does not exist.
+func add(a, b *dynamoValue) *dynamoValue {
+	switch {
+	case b == nil:
+		return a
+	case a == nil:
+		return b
+	case a.N != nil && b.N != nil:
+		return numberValue(new(big.Rat).Add(number(*a.N), number(*b.N)))
+	case a.SS != nil && b.SS != nil:
+		union := slices.Clone(a.SS)
+		for _, s := range b.SS {
+			if !slices.Contains(union, s) {
+				union = append(union, s)
+			}
+		}
+		return &dynamoValue{SS: union}
+	case a.NS != nil && b.NS != nil:
+		union := slices.Clone(a.NS)
+		for _, n := range b.NS {
+			if !contains(&dynamoValue{NS: union}, &dynamoValue{N: &n}) {
+				union = append(union, n)
+			}
+		}
+		return &dynamoValue{NS: union}
+	}
+	panic(validationError("an operand in the update expression has an incorrect data type"))
+}
+
+// remove implements the delete action for sets, nil is returned if the set is empty afterwards.
+func remove(a, b *dynamoValue) *dynamoValue {
+	switch {
+	case a == nil:
+		return nil
+	case b == nil:
+		return a
+	case a.SS != nil && b.SS != nil:
+		remaining := slices.DeleteFunc(slices.Clone(a.SS), func(s string) bool { return slices.Contains(b.SS, s) })
+		if len(remaining) > 0 {
+			return &dynamoValue{SS: remaining}
+		}
+		return nil
+	case a.NS != nil && b.NS != nil:
+		remaining := slices.DeleteFunc(slices.Clone(a.NS), func(n string) bool { return contains(b, &dynamoValue{N: &n}) })
+		if len(remaining) > 0 {
+			return &dynamoValue{NS: remaining}
+		}
+		return nil
+	}
+	panic(validationError("an operand in the update expression has an incorrect data type"))
+}
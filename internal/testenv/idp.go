@@ -0,0 +1,194 @@
+package testenv
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// oidcClientId, oidcClientSecret and oidcRedirectUrl configure the client registered at the identity provider.
+	oidcClientId     = "zen"
+	oidcClientSecret = "zen-secret"
+	oidcRedirectUrl  = "https://zen.test/login/oidc"
+
+	idpKeyId = "idp"
+)
+
+// IdP is a minimal openid connect provider (discovery, authorization code flow with pkce and jwks).
+// The authorization endpoint does not authenticate, it approves the identity passed with Authorize().
+type IdP struct {
+	Server *httptest.Server
+	key    *rsa.PrivateKey
+
+	lock   sync.Mutex
+	grants map[string]*grant
+}
+
+type grant struct {
+	redirectUrl   string
+	nonce         string
+	challenge     string
+	email         string
+	emailVerified bool
+}
+
+func NewIdP(t testing.TB) *IdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate idp key: %v", err)
+	}
+	idp := &IdP{key: key, grants: map[string]*grant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("GET /jwks", idp.jwks)
+	mux.HandleFunc("GET /authorize", idp.authorize)
+	mux.HandleFunc("POST /token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Server.Close)
+	return idp
+}
+
+// Authorize follows the authorization url as the given identity and returns the state and code
+// the provider attached to the redirect url.
+func (i *IdP) Authorize(t testing.TB, authUrl, email string, emailVerified bool) (string, string) {
+	t.Helper()
+	target, err := url.Parse(authUrl)
+	if err != nil {
+		t.Fatalf("invalid authorization url: %v", err)
+	}
+	query := target.Query()
+	query.Set("login_hint", email)
+	if emailVerified {
+		query.Set("email_verified", "true")
+	}
+	target.RawQuery = query.Encode()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(target.String())
+	if err != nil {
+		t.Fatalf("authorization request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorization request was rejected: %s", resp.Status)
+	}
+	redirect, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect url: %v", err)
+	}
+	return redirect.Query().Get("state"), redirect.Query().Get("code")
+}
+
+func (i *IdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"issuer":                                i.Server.URL,
+		"authorization_endpoint":                i.Server.URL + "/authorize",
+		"token_endpoint":                        i.Server.URL + "/token",
+		"jwks_uri":                              i.Server.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (i *IdP) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": idpKeyId,
+			"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (i *IdP) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != oidcClientId || query.Get("redirect_uri") != oidcRedirectUrl ||
+		query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	code := rand.Text()
+	i.lock.Lock()
+	i.grants[code] = &grant{
+		redirectUrl:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		challenge:     query.Get("code_challenge"),
+		email:         query.Get("login_hint"),
+		emailVerified: query.Get("email_verified") == "true",
+	}
+	i.lock.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (i *IdP) token(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientId, clientSecret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if clientId != oidcClientId || clientSecret != oidcClientSecret {
+		writeJson(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	i.lock.Lock()
+	g, found := i.grants[r.PostFormValue("code")]
+	delete(i.grants, r.PostFormValue("code"))
+	i.lock.Unlock()
+	challenge := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !found || r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("redirect_uri") != g.redirectUrl ||
+		base64.RawURLEncoding.EncodeToString(challenge[:]) != g.challenge {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            i.Server.URL,
+		"sub":            g.email,
+		"aud":            oidcClientId,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.email,
+		"email_verified": g.emailVerified,
+	})
+	idToken.Header["kid"] = idpKeyId
+	signedIdToken, err := idToken.SignedString(i.key)
+	if err != nil {
+		writeJson(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJson(w, http.StatusOK, map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signedIdToken,
+	})
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,73 @@
+package testenv
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/megakuul/zen/internal/mail"
+)
+
+// Mailer captures all messages instead of delivering them.
+type Mailer struct {
+	lock     sync.Mutex
+	messages []*mail.Message
+}
+
+func (m *Mailer) Send(ctx context.Context, msg *mail.Message) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns all messages sent to the address.
+func (m *Mailer) Messages(addr string) []*mail.Message {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	messages := []*mail.Message{}
+	for _, msg := range m.messages {
+		if slices.Contains(msg.To, addr) {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// Last returns the latest message sent to the address and fails the test if there is none.
+func (m *Mailer) Last(t testing.TB, addr string) *mail.Message {
+	t.Helper()
+	messages := m.Messages(addr)
+	if len(messages) < 1 {
+		t.Fatalf("no mail was sent to '%s'", addr)
+	}
+	return messages[len(messages)-1]
+}
+
+var (
+	codePattern = regexp.MustCompile(`[2-9A-Za-z]{3}-[2-9A-Za-z]{4}`)
+	linkPattern = regexp.MustCompile(regexp.QuoteMeta(loginLinkUrl) + `\?\S+`)
+)
+
+// Code returns the verification code of the latest message sent to the address.
+func (m *Mailer) Code(t testing.TB, addr string) string {
+	t.Helper()
+	code := codePattern.FindString(m.Last(t, addr).Text)
+	if code == "" {
+		t.Fatalf("latest mail to '%s' does not contain a verification code", addr)
+	}
+	return code
+}
+
+// LinkToken returns the token of the sign-in link in the latest message sent to the address.
+func (m *Mailer) LinkToken(t testing.TB, addr string) string {
+	t.Helper()
+	link, err := url.Parse(linkPattern.FindString(m.Last(t, addr).Text))
+	if err != nil || link.Query().Get("token") == "" {
+		t.Fatalf("latest mail to '%s' does not contain a sign-in link", addr)
+	}
+	return link.Query().Get("token")
+}
@@ -0,0 +1,641 @@
+package testenv_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/email"
+	"github.com/megakuul/zen/internal/model/leaderboard"
+	"github.com/megakuul/zen/internal/model/rating"
+	"github.com/megakuul/zen/internal/model/user"
+	"github.com/megakuul/zen/internal/reconcile"
+	"github.com/megakuul/zen/internal/testenv"
+	"github.com/megakuul/zen/pkg/api/v1/manager"
+	"github.com/megakuul/zen/pkg/api/v1/manager/authentication"
+	"github.com/megakuul/zen/pkg/api/v1/manager/management"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/planning"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/timing"
+)
+
+func TestSessionRevocation(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		token := env.Login(t, "monk@zen.test", true)
+		// second device, the refresh cookie is dropped because the anonymous client has no cookie jar.
+		device := env.Anonymous()
+		_, err := device.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+		}))
+		if err != nil {
+			t.Fatalf("failed to initiate login on second device: %v", err)
+		}
+		_, err = device.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier:    &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+			AutoRefresh: true,
+		}))
+		if err != nil {
+			t.Fatalf("failed to login second device: %v", err)
+		}
+
+		list, err := env.Management.ListSessions(ctx, testenv.Authorize(&management.ListSessionsRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to list sessions: %v", err)
+		} else if len(list.Msg.Sessions) != 2 {
+			t.Fatalf("expected 2 sessions, got %d", len(list.Msg.Sessions))
+		}
+		var other *manager.Session
+		for _, session := range list.Msg.Sessions {
+			if session.UserAgent == "" || session.Origin != "127.0.0.1" || session.LastUsedAt == 0 {
+				t.Fatalf("session '%s' does not record the device: %v", session.Id, session)
+			} else if !session.Current {
+				other = session
+			}
+		}
+		if other == nil {
+			t.Fatalf("expected exactly one session that is not the current one")
+		}
+
+		_, err = env.Management.RevokeSession(ctx, testenv.Authorize(&management.RevokeSessionRequest{Id: other.Id}, token))
+		if err != nil {
+			t.Fatalf("failed to revoke session: %v", err)
+		}
+		list, err = env.Management.ListSessions(ctx, testenv.Authorize(&management.ListSessionsRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to list sessions: %v", err)
+		} else if len(list.Msg.Sessions) != 1 || !list.Msg.Sessions[0].Current {
+			t.Fatalf("expected only the current session to remain, got: %v", list.Msg.Sessions)
+		}
+
+		_, err = env.Management.RevokeSession(ctx, testenv.Authorize(&management.RevokeSessionRequest{All: true}, token))
+		if err != nil {
+			t.Fatalf("failed to revoke all sessions: %v", err)
+		}
+		_, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{Verifier: &manager.Verifier{}}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected revoked session to be rejected, got: %v", err)
+		}
+	})
+}
+
+func TestPersonalTokenScopes(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		token := env.Login(t, "monk@zen.test", false)
+
+		_, err := env.Management.CreatePersonalToken(ctx, testenv.Authorize(&management.CreatePersonalTokenRequest{
+			Name: "dashboard", Scopes: []string{"planning:admin"},
+		}, token))
+		if connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Fatalf("expected unknown scope to be rejected, got: %v", err)
+		}
+		// the lifetime is bounded, large values must not overflow into a valid duration.
+		for _, days := range []int64{-1, 366, math.MaxInt64 / 1000} {
+			_, err = env.Management.CreatePersonalToken(ctx, testenv.Authorize(&management.CreatePersonalTokenRequest{
+				Name: "dashboard", Scopes: []string{"timing"}, ExpiresInDays: days,
+			}, token))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Fatalf("expected lifetime of %d days to be rejected, got: %v", days, err)
+			}
+		}
+		created, err := env.Management.CreatePersonalToken(ctx, testenv.Authorize(&management.CreatePersonalTokenRequest{
+			Name: "dashboard", Scopes: []string{"timing", "planning:read"},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to create personal token: %v", err)
+		}
+		pat := created.Msg.Token
+
+		start := time.Now().Unix()
+		_, err = env.Planning.Upsert(ctx, testenv.Authorize(&planning.UpsertRequest{
+			Events: []*scheduler.Event{{Name: "meditate", StartTime: start, StopTime: start + 60}},
+		}, pat))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected write without planning:write scope to be rejected, got: %v", err)
+		}
+		_, err = env.Planning.Upsert(ctx, testenv.Authorize(&planning.UpsertRequest{
+			Events: []*scheduler.Event{{Name: "meditate", StartTime: start, StopTime: start + 60}},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to upsert events: %v", err)
+		}
+		events, err := env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{Since: start - 60, Until: start + 60}, pat))
+		if err != nil {
+			t.Fatalf("failed to get events with personal token: %v", err)
+		} else if len(events.Msg.Events) != 1 {
+			t.Fatalf("unexpected events: %v", events.Msg.Events)
+		}
+		if _, err := env.Timing.Start(ctx, testenv.Authorize(&timing.StartRequest{Id: events.Msg.Events[0].Id}, pat)); err != nil {
+			t.Fatalf("failed to start timer with personal token: %v", err)
+		}
+		_, err = env.Management.Get(ctx, testenv.Authorize(&management.GetRequest{}, pat))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected profile read without profile:read scope to be rejected, got: %v", err)
+		}
+		// personal tokens cannot manage the account (e.g. mint more tokens).
+		_, err = env.Management.CreatePersonalToken(ctx, testenv.Authorize(&management.CreatePersonalTokenRequest{
+			Name: "escalation", Scopes: []string{"profile:read"},
+		}, pat))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected personal token to be rejected by account management, got: %v", err)
+		}
+
+		list, err := env.Management.ListPersonalTokens(ctx, testenv.Authorize(&management.ListPersonalTokensRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to list personal tokens: %v", err)
+		} else if len(list.Msg.PersonalTokens) != 1 || list.Msg.PersonalTokens[0].Name != "dashboard" {
+			t.Fatalf("unexpected personal tokens: %v", list.Msg.PersonalTokens)
+		}
+		_, err = env.Management.RevokePersonalToken(ctx, testenv.Authorize(&management.RevokePersonalTokenRequest{Id: list.Msg.PersonalTokens[0].Id}, token))
+		if err != nil {
+			t.Fatalf("failed to revoke personal token: %v", err)
+		}
+		_, err = env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{Since: start - 60, Until: start + 60}, pat))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected revoked personal token to be rejected, got: %v", err)
+		}
+	})
+}
+
+func TestChangeEmail(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		sub := env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		env.Register(t, &manager.User{Email: "sage@zen.test", Username: "sage"})
+		token := env.Login(t, "monk@zen.test", true)
+		created, err := env.Management.CreatePersonalToken(ctx, testenv.Authorize(&management.CreatePersonalTokenRequest{
+			Name: "dashboard", Scopes: []string{"planning:read"},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to create personal token: %v", err)
+		}
+
+		_, err = env.Management.ChangeEmail(ctx, testenv.Authorize(&management.ChangeEmailRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "sage@zen.test"},
+		}, token))
+		if connect.CodeOf(err) != connect.CodeAlreadyExists {
+			t.Fatalf("expected registered email to be rejected, got: %v", err)
+		}
+		// the change must be confirmed by the current email, a verifier of another mailbox is rejected.
+		_, err = env.Management.ChangeEmail(ctx, testenv.Authorize(&management.ChangeEmailRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "nomad@zen.test"},
+		}, token))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected email change without current verifier to be rejected, got: %v", err)
+		}
+		_, err = env.Management.ChangeEmail(ctx, testenv.Authorize(&management.ChangeEmailRequest{
+			Verifier:        &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "nomad@zen.test"},
+			CurrentVerifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "sage@zen.test"},
+		}, token))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected email change with foreign current verifier to be rejected, got: %v", err)
+		}
+		_, err = env.Management.ChangeEmail(ctx, testenv.Authorize(&management.ChangeEmailRequest{
+			Verifier:        &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "nomad@zen.test"},
+			CurrentVerifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to initiate email change: %v", err)
+		}
+		_, err = env.Management.ChangeEmail(ctx, testenv.Authorize(&management.ChangeEmailRequest{
+			Verifier:        &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "nomad@zen.test", Code: env.Mailer.Code(t, "nomad@zen.test")},
+			CurrentVerifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to complete email change: %v", err)
+		}
+
+		// every token carrying the old email is rejected.
+		_, err = env.Management.Get(ctx, testenv.Authorize(&management.GetRequest{}, token))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected access token with old email to be rejected, got: %v", err)
+		}
+		_, err = env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{}, created.Msg.Token))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected personal token with old email to be rejected, got: %v", err)
+		}
+		_, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{Verifier: &manager.Verifier{}}))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected refresh token with old email to be rejected, got: %v", err)
+		}
+		// the rejected refresh cookie is removed by the logout.
+		if _, err := env.Authentication.Logout(ctx, connect.NewRequest(&authentication.LogoutRequest{})); err != nil {
+			t.Fatalf("failed to logout: %v", err)
+		}
+		_, err = env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+		}))
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			t.Fatalf("expected old email to be unregistered, got: %v", err)
+		}
+
+		token = env.Login(t, "nomad@zen.test", false)
+		profile, err := env.Management.Get(ctx, testenv.Authorize(&management.GetRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to get profile: %v", err)
+		} else if profile.Msg.User.Id != sub || profile.Msg.User.Email != "nomad@zen.test" {
+			t.Fatalf("unexpected profile after email change: %v", profile.Msg.User)
+		}
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		sub := env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		sageSub := env.Register(t, &manager.User{Email: "sage@zen.test", Username: "sage"})
+		token := env.Login(t, "monk@zen.test", true)
+		_, err := env.Management.Update(ctx, testenv.Authorize(&management.UpdateRequest{}, token))
+		if connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Fatalf("expected update without user to be rejected, got: %v", err)
+		}
+		updateReq := testenv.Authorize(&management.UpdateRequest{
+			User: &manager.User{Username: "monk", Description: "silent"},
+		}, token)
+		// without trusted proxy the forwarded address is controlled by the client and must not be recorded.
+		updateReq.Header().Set("X-Forwarded-For", "203.0.113.7")
+		if _, err = env.Management.Update(ctx, updateReq); err != nil {
+			t.Fatalf("failed to update profile: %v", err)
+		}
+		if _, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{Verifier: &manager.Verifier{}})); err != nil {
+			t.Fatalf("failed to refresh: %v", err)
+		}
+		if _, err := env.Authentication.Logout(ctx, connect.NewRequest(&authentication.LogoutRequest{})); err != nil {
+			t.Fatalf("failed to logout: %v", err)
+		}
+
+		// until defaults to now.
+		listReq := &management.ListAuditEventsRequest{}
+		events, err := env.Management.ListAuditEvents(ctx, testenv.Authorize(listReq, token))
+		if err != nil {
+			t.Fatalf("failed to list audit events: %v", err)
+		}
+		types := []string{}
+		for _, event := range events.Msg.Events {
+			types = append(types, event.Type)
+		}
+		expected := []string{"logout", "refresh", "profile_update", "login", "code_sent"}
+		if !slices.Equal(types, expected) {
+			t.Fatalf("unexpected audit events: got %v, expected %v", types, expected)
+		}
+		if origin := events.Msg.Events[2].Origin; origin != "127.0.0.1" {
+			t.Fatalf("expected peer address as origin; got '%s'", origin)
+		}
+
+		sageToken := env.Login(t, "sage@zen.test", false)
+		_, err = env.Management.ListAuditEvents(ctx, testenv.Authorize(&management.ListAuditEventsRequest{
+			Subject: sub, Since: listReq.Since, Until: listReq.Until,
+		}, sageToken))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected foreign audit log to be denied, got: %v", err)
+		}
+		// support staff can investigate every user.
+		env.Audit.SupportSubjects = []string{sageSub}
+		events, err = env.Management.ListAuditEvents(ctx, testenv.Authorize(&management.ListAuditEventsRequest{
+			Subject: sub, Since: listReq.Since, Until: listReq.Until,
+		}, sageToken))
+		if err != nil {
+			t.Fatalf("failed to list audit events as support: %v", err)
+		} else if len(events.Msg.Events) != len(expected) {
+			t.Fatalf("unexpected audit events for support: %v", events.Msg.Events)
+		}
+
+		// the latest events stay reachable once the trail exceeds the list limit.
+		for range 100 {
+			if err := env.Audit.Record(ctx, sub, "refresh", "", nil); err != nil {
+				t.Fatalf("failed to record audit event: %v", err)
+			}
+		}
+		if err := env.Audit.Record(ctx, sub, "logout", "latest", nil); err != nil {
+			t.Fatalf("failed to record audit event: %v", err)
+		}
+		events, err = env.Management.ListAuditEvents(ctx, testenv.Authorize(listReq, token))
+		if err != nil {
+			t.Fatalf("failed to list audit events: %v", err)
+		} else if len(events.Msg.Events) != 100 || events.Msg.Events[0].Detail != "latest" {
+			t.Fatalf("expected the latest 100 events, got %d (first: %v)", len(events.Msg.Events), events.Msg.Events[0])
+		}
+	})
+}
+
+func TestAccountDeletion(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		sub := env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk", Leaderboard: true})
+		token := env.Login(t, "monk@zen.test", false)
+
+		// backdate the account so the board of last week is part of its history.
+		profile, _, err := env.Users.GetProfile(ctx, sub)
+		if err != nil {
+			t.Fatalf("failed to get profile: %v", err)
+		}
+		profile.CreatedAt = time.Now().AddDate(0, 0, -14).Unix()
+		if err := env.Users.DeleteProfile(ctx, sub); err != nil {
+			t.Fatalf("failed to delete profile: %v", err)
+		} else if err := env.Users.PutProfile(ctx, sub, profile); err != nil {
+			t.Fatalf("failed to backdate profile: %v", err)
+		}
+		lastWeek := time.Now().AddDate(0, 0, -7)
+		err = env.Boards.PutBoard(ctx, lastWeek, &leaderboard.Board{
+			Algorithms: map[string]int64{},
+			Entries: map[string]leaderboard.BoardEntry{
+				sub:    {UserId: sub, Username: "monk"},
+				"sage": {UserId: "sage", Username: "sage"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to put archived board: %v", err)
+		}
+
+		start := time.Now().Unix()
+		_, err = env.Planning.Upsert(ctx, testenv.Authorize(&planning.UpsertRequest{
+			Events: []*scheduler.Event{{Name: "meditate", StartTime: start, StopTime: start + 1}},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to upsert events: %v", err)
+		}
+		id := fmt.Sprintf("%d", start)
+		if _, err := env.Timing.Start(ctx, testenv.Authorize(&timing.StartRequest{Id: id}, token)); err != nil {
+			t.Fatalf("failed to start timer: %v", err)
+		} else if _, err := env.Timing.Stop(ctx, testenv.Authorize(&timing.StopRequest{Id: id}, token)); err != nil {
+			t.Fatalf("failed to stop timer: %v", err)
+		}
+		env.Board(t, func(b *leaderboard.Board) bool {
+			_, ok := b.Entries[sub]
+			return ok
+		})
+		created, err := env.Management.CreatePersonalToken(ctx, testenv.Authorize(&management.CreatePersonalTokenRequest{
+			Name: "dashboard", Scopes: []string{"planning:read"},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to create personal token: %v", err)
+		}
+
+		// a code of another registered email does not authorize the deletion (the verifier is bound to the account).
+		nomadSub := env.Register(t, &manager.User{Email: "nomad@zen.test", Username: "nomad"})
+		for _, verifier := range []*manager.Verifier{
+			{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "nomad@zen.test"},
+			{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "nomad@zen.test", Code: "000000"},
+		} {
+			_, err = env.Management.Delete(ctx, testenv.Authorize(&management.DeleteRequest{Verifier: verifier}, token))
+			if connect.CodeOf(err) != connect.CodePermissionDenied {
+				t.Fatalf("expected deletion with foreign verifier email to be rejected, got: %v", err)
+			}
+		}
+		if _, found, err := env.Emails.GetRegistration(ctx, "monk@zen.test"); err != nil || !found {
+			t.Fatalf("expected registration to survive the rejected deletion: %v", err)
+		}
+
+		if env.Dynamo != nil {
+			// small pages and unprocessed batch writes exercise the paging and the retries of the purge.
+			env.Dynamo.SetPageSize(2)
+			env.Dynamo.FailWrites(3)
+		}
+		_, err = env.Management.Delete(ctx, testenv.Authorize(&management.DeleteRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: "monk@zen.test"},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to initiate deletion: %v", err)
+		}
+		_, err = env.Management.Delete(ctx, testenv.Authorize(&management.DeleteRequest{
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: "monk@zen.test", Code: env.Mailer.Code(t, "monk@zen.test")},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to delete account: %v", err)
+		}
+
+		// all tokens are revoked immediately.
+		_, err = env.Management.Get(ctx, testenv.Authorize(&management.GetRequest{}, token))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected access token to be revoked, got: %v", err)
+		}
+		_, err = env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{}, created.Msg.Token))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected personal token to be revoked, got: %v", err)
+		}
+
+		// the purge runs on the rating bus, the archived board is the last step.
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			archived, _, err := env.Boards.GetBoard(ctx, lastWeek)
+			if err != nil {
+				t.Fatalf("failed to read archived board: %v", err)
+			} else if _, ok := archived.Entries[sub]; !ok {
+				if _, ok := archived.Entries["sage"]; !ok {
+					t.Fatalf("expected other entries of the archived board to be kept")
+				}
+				break
+			} else if time.Now().After(deadline) {
+				t.Fatalf("user was not removed from the archived board")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if env.Dynamo != nil {
+			// the list queries only read the first page.
+			env.Dynamo.SetPageSize(0)
+		}
+		board, _, err := env.Boards.GetBoard(ctx, time.Now())
+		if err != nil {
+			t.Fatalf("failed to read board: %v", err)
+		} else if _, ok := board.Entries[sub]; ok {
+			t.Fatalf("user was not removed from the current board")
+		}
+		if _, found, err := env.Users.GetProfile(ctx, sub); err != nil || found {
+			t.Fatalf("expected profile to be purged (found: %t, err: %v)", found, err)
+		}
+		// rating updates sent before the deletion can arrive in a later batch, they must not add the user again.
+		for _, update := range []*rating.Update{
+			{Time: time.Now(), UserId: sub, Username: "monk", Algorithm: "late", RatingChange: 1},
+			{Time: time.Now(), UserId: nomadSub, Username: "nomad", Algorithm: "late", RatingChange: 1},
+		} {
+			if err := env.Ratings.SendUpdate(ctx, update); err != nil {
+				t.Fatalf("failed to send rating update: %v", err)
+			}
+		}
+		board = env.Board(t, func(b *leaderboard.Board) bool {
+			_, ok := b.Entries[nomadSub]
+			return ok
+		})
+		if _, ok := board.Entries[sub]; ok {
+			t.Fatalf("expected late rating update of the deleted user to be dropped")
+		}
+		events, err := env.Users.ListEvents(ctx, sub, time.Unix(start-60, 0), time.Unix(start+60, 0))
+		if err != nil || len(events) > 0 {
+			t.Fatalf("expected events to be purged (events: %d, err: %v)", len(events), err)
+		}
+		tokens, err := env.Users.ListPersonalTokens(ctx, sub)
+		if err != nil || len(tokens) > 0 {
+			t.Fatalf("expected personal tokens to be purged (tokens: %d, err: %v)", len(tokens), err)
+		}
+		audits, err := env.Users.ListAuditEvents(ctx, sub, time.Unix(0, 0), time.Now().Add(time.Hour))
+		if err != nil || len(audits) > 0 {
+			t.Fatalf("expected audit trail to be purged (events: %d, err: %v)", len(audits), err)
+		}
+
+		// the email is free for a new account.
+		if newSub := env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"}); newSub == sub {
+			t.Fatalf("expected a new account for the released email")
+		}
+	})
+}
+
+func TestExport(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		sub := env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk", Leaderboard: true})
+		token := env.Login(t, "monk@zen.test", false)
+
+		start := time.Now().Unix()
+		_, err := env.Planning.Upsert(ctx, testenv.Authorize(&planning.UpsertRequest{
+			Events: []*scheduler.Event{
+				{Name: "meditate", StartTime: start, StopTime: start + 1},
+				{Name: "walk, slowly", StartTime: start + 3600, StopTime: start + 7200},
+			},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to upsert events: %v", err)
+		}
+		id := fmt.Sprintf("%d", start)
+		if _, err := env.Timing.Start(ctx, testenv.Authorize(&timing.StartRequest{Id: id}, token)); err != nil {
+			t.Fatalf("failed to start timer: %v", err)
+		} else if _, err := env.Timing.Stop(ctx, testenv.Authorize(&timing.StopRequest{Id: id}, token)); err != nil {
+			t.Fatalf("failed to stop timer: %v", err)
+		}
+		env.Board(t, func(b *leaderboard.Board) bool {
+			_, ok := b.Entries[sub]
+			return ok
+		})
+
+		// personal access tokens cannot export the account.
+		created, err := env.Management.CreatePersonalToken(ctx, testenv.Authorize(&management.CreatePersonalTokenRequest{
+			Name: "dashboard", Scopes: []string{"profile:read"},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to create personal token: %v", err)
+		}
+		_, err = env.Management.Export(ctx, testenv.Authorize(&management.ExportRequest{}, created.Msg.Token))
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("expected personal token to be rejected, got: %v", err)
+		}
+
+		resp, err := env.Management.Export(ctx, testenv.Authorize(&management.ExportRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to export: %v", err)
+		}
+		archive, err := zip.NewReader(bytes.NewReader(resp.Msg.Archive), int64(len(resp.Msg.Archive)))
+		if err != nil {
+			t.Fatalf("invalid export archive: %v", err)
+		}
+		files := map[string][]byte{}
+		for _, file := range archive.File {
+			r, err := file.Open()
+			if err != nil {
+				t.Fatalf("failed to open '%s': %v", file.Name, err)
+			}
+			files[file.Name], err = io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				t.Fatalf("failed to read '%s': %v", file.Name, err)
+			}
+		}
+
+		profile := struct {
+			Id    string `json:"id"`
+			Email string `json:"email"`
+		}{}
+		if err := json.Unmarshal(files["profile.json"], &profile); err != nil {
+			t.Fatalf("invalid profile.json: %v", err)
+		} else if profile.Id != sub || profile.Email != "monk@zen.test" {
+			t.Fatalf("unexpected exported profile: %+v", profile)
+		}
+		events, err := csv.NewReader(bytes.NewReader(files["events.csv"])).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid events.csv: %v", err)
+		} else if len(events) != 3 || events[1][2] != "meditate" || events[2][2] != "walk, slowly" {
+			t.Fatalf("unexpected exported events: %v", events)
+		}
+		entries := []struct {
+			Username string `json:"username"`
+			Ratings  []any  `json:"ratings"`
+		}{}
+		if err := json.Unmarshal(files["leaderboard.json"], &entries); err != nil {
+			t.Fatalf("invalid leaderboard.json: %v", err)
+		} else if len(entries) != 1 || entries[0].Username != "monk" || len(entries[0].Ratings) != 1 {
+			t.Fatalf("unexpected exported leaderboard entries: %+v", entries)
+		}
+		for _, name := range []string{"events.json", "profile.csv", "leaderboard.csv"} {
+			if len(files[name]) == 0 {
+				t.Fatalf("export archive is missing '%s'", name)
+			}
+		}
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		sub := env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+
+		// orphans as left behind by the former non-atomic registration and deletion.
+		createdAt := time.Now().AddDate(0, 0, -1).Unix()
+		if err := env.Users.PutProfile(ctx, "ghost", &user.Profile{Username: "ghost", CreatedAt: createdAt}); err != nil {
+			t.Fatalf("failed to put orphaned profile: %v", err)
+		}
+		if err := env.Users.PutEvents(ctx, "ghost", []user.Event{{Name: "haunt", StartTime: createdAt, StopTime: createdAt + 1}}, nil); err != nil {
+			t.Fatalf("failed to put orphaned events: %v", err)
+		}
+		if err := env.Users.PutProfile(ctx, "newbie", &user.Profile{Username: "newbie", CreatedAt: time.Now().Unix()}); err != nil {
+			t.Fatalf("failed to put recent profile: %v", err)
+		}
+		if err := env.Emails.PutRegistration(ctx, "lost@zen.test", &email.Registration{User: "lost"}); err != nil {
+			t.Fatalf("failed to put orphaned registration: %v", err)
+		}
+
+		if env.Dynamo != nil {
+			// single item pages exercise the paging of the table scan.
+			env.Dynamo.SetPageSize(1)
+		}
+		controller := reconcile.New(env.Accounts, env.Users, env.Users, env.Emails)
+		orphans, err := controller.Find(ctx)
+		if err != nil {
+			t.Fatalf("failed to find orphans: %v", err)
+		}
+		if !slices.Equal(orphans.Profiles, []string{"ghost"}) {
+			t.Fatalf("expected orphaned profiles [ghost]; got %v", orphans.Profiles)
+		} else if len(orphans.Registrations) != 1 || orphans.Registrations["lost@zen.test"] != "lost" {
+			t.Fatalf("expected orphaned registration lost@zen.test; got %v", orphans.Registrations)
+		}
+		if err := controller.Clean(ctx, orphans); err != nil {
+			t.Fatalf("failed to clean orphans: %v", err)
+		}
+
+		if _, found, err := env.Users.GetProfile(ctx, "ghost"); err != nil || found {
+			t.Fatalf("expected orphaned profile to be purged (found: %t, err: %v)", found, err)
+		}
+		if events, err := env.Users.ListEvents(ctx, "ghost", time.Unix(0, 0), time.Now()); err != nil || len(events) > 0 {
+			t.Fatalf("expected orphaned events to be purged (events: %d, err: %v)", len(events), err)
+		}
+		if _, found, err := env.Emails.GetRegistration(ctx, "lost@zen.test"); err != nil || found {
+			t.Fatalf("expected orphaned registration to be deleted (found: %t, err: %v)", found, err)
+		}
+		if _, found, err := env.Users.GetProfile(ctx, "newbie"); err != nil || !found {
+			t.Fatalf("expected recent profile to be kept (found: %t, err: %v)", found, err)
+		}
+		if registered, found, err := env.Emails.RegisteredUser(ctx, "monk@zen.test"); err != nil || !found || registered != sub {
+			t.Fatalf("expected registered account to be kept (found: %t, err: %v)", found, err)
+		}
+		env.Login(t, "monk@zen.test", false)
+	})
+}
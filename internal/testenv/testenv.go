@@ -0,0 +1,352 @@
+// package testenv provides an in-process zen environment for integration tests.
+// The services are wired by the server package like in cmd/zen, but every aws dependency is replaced by an embedded stand-in:
+// dynamodb (bolt table or the in-memory dynamodb), s3 (memory boards and captchas), sqs (rating bus), ses (capturing mailer)
+// and kms (local signer).
+// The oidc login is configured against a mock identity provider (IdP).
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	dchest "github.com/dchest/captcha"
+	"github.com/megakuul/zen/internal/audit"
+	"github.com/megakuul/zen/internal/captcha"
+	"github.com/megakuul/zen/internal/model/account"
+	"github.com/megakuul/zen/internal/model/bolt"
+	"github.com/megakuul/zen/internal/model/ceremony"
+	"github.com/megakuul/zen/internal/model/email"
+	leaderboardmodel "github.com/megakuul/zen/internal/model/leaderboard"
+	"github.com/megakuul/zen/internal/model/ratelimit"
+	"github.com/megakuul/zen/internal/model/rating"
+	"github.com/megakuul/zen/internal/model/user"
+	"github.com/megakuul/zen/internal/server"
+	"github.com/megakuul/zen/internal/server/v1/leaderboard"
+	"github.com/megakuul/zen/internal/token"
+	"github.com/megakuul/zen/pkg/api/v1/manager"
+	authenticationapi "github.com/megakuul/zen/pkg/api/v1/manager/authentication"
+	"github.com/megakuul/zen/pkg/api/v1/manager/authentication/authenticationconnect"
+	managementapi "github.com/megakuul/zen/pkg/api/v1/manager/management"
+	"github.com/megakuul/zen/pkg/api/v1/manager/management/managementconnect"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/planning/planningconnect"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/timing/timingconnect"
+)
+
+// Table is the name of the dynamodb table.
+const Table = "zen"
+
+const (
+	issuer = "https://zen.test"
+	// loginLinkUrl is the page that receives the token of sign-in links.
+	loginLinkUrl = "https://zen.test/login/link"
+)
+
+// Env is a running zen environment. The stand-ins are exposed so tests can inspect or manipulate the state.
+type Env struct {
+	Logger   *slog.Logger
+	Server   *httptest.Server
+	Mailer   *Mailer
+	Captchas dchest.Store
+	Token    *token.Controller
+	Audit    *audit.Controller
+	Accounts account.Store
+	Users    user.Store
+	Emails   email.Store
+	// Ceremonies is the ceremony store of the controllers (e.g. to create a standalone auth controller).
+	Ceremonies ceremony.Store
+	Boards     *leaderboardmodel.MemoryModel
+	// Ratings is the in-process rating bus processed by the leaderboard service.
+	Ratings rating.Sender
+	// Dynamo is only set on the dynamodb backend.
+	Dynamo *Dynamo
+	IdP    *IdP
+
+	// Client is the http client used by the api clients, it stores cookies like a browser.
+	Client         *http.Client
+	Authentication authenticationconnect.AuthenticationServiceClient
+	Management     managementconnect.ManagementServiceClient
+	Planning       planningconnect.PlanningServiceClient
+	Timing         timingconnect.TimingServiceClient
+}
+
+// Backends are the storage backends an environment can run on.
+var Backends = []string{"bolt", "dynamodb"}
+
+// Run runs the test in a subtest with a new environment for every storage backend.
+func Run(t *testing.T, test func(t *testing.T, env *Env)) {
+	for _, backend := range Backends {
+		t.Run(backend, func(t *testing.T) {
+			test(t, New(t, backend))
+		})
+	}
+}
+
+// New starts a new environment on the storage backend, it is torn down automatically when the test finishes.
+func New(t testing.TB, backend string) *Env {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(testWriter{t}, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	var (
+		dynamo         *Dynamo
+		accountModel   account.Store
+		emailModel     email.Store
+		userModel      user.Store
+		ceremonyModel  ceremony.Store
+		rateLimitModel ratelimit.Store
+	)
+	switch backend {
+	case "bolt":
+		table, err := bolt.Open(filepath.Join(t.TempDir(), "zen.db"), "zen")
+		if err != nil {
+			t.Fatalf("cannot open bolt storage: %v", err)
+		}
+		t.Cleanup(func() { table.Close() })
+		accountModel = account.NewBolt(table)
+		emailModel = email.NewBolt(table)
+		userModel = user.NewBolt(table)
+		ceremonyModel = ceremony.NewBolt(table)
+		rateLimitModel = ratelimit.NewMemory()
+	case "dynamodb":
+		dynamo = NewDynamo(t)
+		client := dynamo.Client()
+		accountModel = account.New(client, Table)
+		emailModel = email.New(client, Table)
+		userModel = user.New(client, Table)
+		ceremonyModel = ceremony.New(client, Table)
+		rateLimitModel = ratelimit.New(client, Table)
+	default:
+		t.Fatalf("invalid storage backend '%s'; expected 'bolt' or 'dynamodb'", backend)
+	}
+
+	boardModel := leaderboardmodel.NewMemory()
+	bus, err := rating.NewBus(logger, t.TempDir(),
+		rating.WithBatchWindow(10*time.Millisecond),
+		rating.WithRetryDelay(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("cannot create rating bus: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	busDone := make(chan struct{})
+	go func() {
+		defer close(busDone)
+		bus.Run(ctx, leaderboard.New(logger, boardModel, userModel, userModel, emailModel).Process)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-busDone
+	})
+
+	tokenProvider, err := token.GenerateLocal("ES256")
+	if err != nil {
+		t.Fatalf("cannot generate token key: %v", err)
+	}
+	mailer := &Mailer{}
+	captchaStore := captcha.NewMemory(10 * time.Minute)
+	idp := NewIdP(t)
+	srv := server.New(logger, &server.Config{
+		TokenIssuer:      issuer,
+		LoginLinkUrl:     loginLinkUrl,
+		PasskeyRpId:      rpId,
+		PasskeyRpName:    "Zen",
+		PasskeyRpOrigins: []string{rpOrigin},
+		TotpIssuer:       "Zen",
+		OidcIssuer:       idp.Server.URL,
+		OidcClientId:     oidcClientId,
+		OidcClientSecret: oidcClientSecret,
+		OidcRedirectUrl:  oidcRedirectUrl,
+		RatingAnchor:     2 * time.Minute,
+	}, &server.Models{
+		Accounts:   accountModel,
+		Emails:     emailModel,
+		Users:      userModel,
+		Ceremonies: ceremonyModel,
+		RateLimits: rateLimitModel,
+		Boards:     boardModel,
+		Ratings:    bus,
+	}, tokenProvider)
+	mux := http.NewServeMux()
+	if err := srv.RegisterManager(context.Background(), mux, mailer, captchaStore); err != nil {
+		t.Fatalf("cannot create manager api: %v", err)
+	}
+	srv.RegisterScheduler(mux)
+	srv.RegisterBoards(mux)
+
+	// tls is required because the refresh cookie is marked as secure.
+	server := httptest.NewUnstartedServer(mux)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	client := server.Client()
+	client.Jar, _ = cookiejar.New(nil)
+
+	return &Env{
+		Logger:         logger,
+		Server:         server,
+		Mailer:         mailer,
+		Captchas:       captchaStore,
+		Token:          srv.Token,
+		Audit:          srv.Audit,
+		Accounts:       accountModel,
+		Users:          userModel,
+		Emails:         emailModel,
+		Ceremonies:     ceremonyModel,
+		Boards:         boardModel,
+		Ratings:        bus,
+		Dynamo:         dynamo,
+		IdP:            idp,
+		Client:         client,
+		Authentication: authenticationconnect.NewAuthenticationServiceClient(client, server.URL),
+		Management:     managementconnect.NewManagementServiceClient(client, server.URL),
+		Planning:       planningconnect.NewPlanningServiceClient(client, server.URL),
+		Timing:         timingconnect.NewTimingServiceClient(client, server.URL),
+	}
+}
+
+// CaptchaSolution reads the digits of the captcha from the in-memory store without consuming it.
+func (e *Env) CaptchaSolution(id string) string {
+	digits := e.Captchas.Get(id, false)
+	solution := make([]byte, len(digits))
+	for i, digit := range digits {
+		solution[i] = '0' + digit
+	}
+	return string(solution)
+}
+
+// Register performs the full registration flow (captcha, email and code stage) and returns the user id.
+func (e *Env) Register(t testing.TB, u *manager.User) string {
+	t.Helper()
+	ctx := context.Background()
+	challenge, err := e.Management.Register(ctx, connect.NewRequest(&managementapi.RegisterRequest{
+		User:     u,
+		Verifier: &manager.Verifier{Email: u.Email},
+	}))
+	if err != nil {
+		t.Fatalf("failed to request captcha: %v", err)
+	}
+	_, err = e.Management.Register(ctx, connect.NewRequest(&managementapi.RegisterRequest{
+		User:          u,
+		CaptchaId:     challenge.Msg.CaptchaId,
+		CaptchaDigits: e.CaptchaSolution(challenge.Msg.CaptchaId),
+		Verifier:      &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: u.Email},
+	}))
+	if err != nil {
+		t.Fatalf("failed to initiate registration: %v", err)
+	}
+	_, err = e.Management.Register(ctx, connect.NewRequest(&managementapi.RegisterRequest{
+		User:      u,
+		CaptchaId: challenge.Msg.CaptchaId, // the consumed captcha is not checked again in the code stage
+		Verifier:  &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: u.Email, Code: e.Mailer.Code(t, u.Email)},
+	}))
+	if err != nil {
+		t.Fatalf("failed to complete registration: %v", err)
+	}
+	registration, found, err := e.Emails.GetRegistration(ctx, u.Email)
+	if err != nil || !found {
+		t.Fatalf("registration of '%s' was not stored: %v", u.Email, err)
+	}
+	return registration.User
+}
+
+// Login performs the email and code stage and returns the access token.
+// If autoRefresh is set, the refresh cookie is stored in the client cookie jar.
+func (e *Env) Login(t testing.TB, addr string, autoRefresh bool) string {
+	t.Helper()
+	ctx := context.Background()
+	_, err := e.Authentication.Login(ctx, connect.NewRequest(&authenticationapi.LoginRequest{
+		Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_EMAIL, Email: addr},
+	}))
+	// an unexpired code that was not used yet is reused.
+	if err != nil && connect.CodeOf(err) != connect.CodeAlreadyExists {
+		t.Fatalf("failed to initiate login: %v", err)
+	}
+	resp, err := e.Authentication.Login(ctx, connect.NewRequest(&authenticationapi.LoginRequest{
+		Verifier:    &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: addr, Code: e.Mailer.Code(t, addr)},
+		AutoRefresh: autoRefresh,
+	}))
+	if err != nil {
+		t.Fatalf("failed to complete login: %v", err)
+	} else if resp.Msg.Token == "" {
+		t.Fatalf("login did not return an access token")
+	}
+	return resp.Msg.Token
+}
+
+// Board polls the weekly board until the condition is met (the rating bus is processed asynchronously).
+func (e *Env) Board(t testing.TB, condition func(*leaderboardmodel.Board) bool) *leaderboardmodel.Board {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		board, found, err := e.Boards.GetBoard(context.Background(), time.Now())
+		if err != nil {
+			t.Fatalf("failed to read board: %v", err)
+		} else if found && condition(board) {
+			return board
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("board did not reach the expected state")
+	return nil
+}
+
+// RefreshCookie returns the refresh token currently stored in the client cookie jar (empty if there is none).
+func (e *Env) RefreshCookie() string {
+	return e.Cookie("refresh_token")
+}
+
+// Cookie returns the value of the cookie currently stored in the client cookie jar (empty if there is none).
+func (e *Env) Cookie(name string) string {
+	serverUrl, _ := url.Parse(e.Server.URL)
+	for _, cookie := range e.Client.Jar.Cookies(serverUrl) {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// WithRefreshCookie creates a request that carries the refresh token explicitly.
+// Must be used with a client without cookie jar (e.g. Env.Anonymous()), otherwise the jar overwrites the cookie.
+func WithRefreshCookie[T any](msg *T, refreshToken string) *connect.Request[T] {
+	return WithCookie(msg, "refresh_token", refreshToken)
+}
+
+// WithCookie creates a request that carries the cookie explicitly (see WithRefreshCookie).
+func WithCookie[T any](msg *T, name, value string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	req.Header().Set("Cookie", fmt.Sprint(name, "=", value))
+	return req
+}
+
+// Anonymous returns an authentication client that does not share the cookie jar.
+func (e *Env) Anonymous() authenticationconnect.AuthenticationServiceClient {
+	client := &http.Client{Transport: e.Client.Transport}
+	return authenticationconnect.NewAuthenticationServiceClient(client, e.Server.URL)
+}
+
+// Authorize creates a request with the bearer token set.
+func Authorize[T any](msg *T, token string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	req.Header().Set("Authorization", fmt.Sprint("Bearer ", token))
+	return req
+}
+
+// testWriter forwards the service logs to the test log.
+type testWriter struct {
+	t testing.TB
+}
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.t.Log(string(p))
+	return len(p), nil
+}
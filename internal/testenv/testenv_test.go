@@ -0,0 +1,240 @@
+package testenv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/model/leaderboard"
+	"github.com/megakuul/zen/internal/testenv"
+	"github.com/megakuul/zen/pkg/api/v1/manager"
+	"github.com/megakuul/zen/pkg/api/v1/manager/authentication"
+	"github.com/megakuul/zen/pkg/api/v1/manager/management"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/planning"
+	"github.com/megakuul/zen/pkg/api/v1/scheduler/timing"
+)
+
+func TestLifecycle(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+
+		userId := env.Register(t, &manager.User{
+			Email:       "monk@zen.test",
+			Username:    "monk",
+			Leaderboard: true,
+		})
+		token := env.Login(t, "monk@zen.test", true)
+
+		// refresh cookie is stored in the jar and can be exchanged for a new access token.
+		refreshed, err := env.Authentication.Login(ctx, connect.NewRequest(&authentication.LoginRequest{
+			Verifier: &manager.Verifier{},
+		}))
+		if err != nil {
+			t.Fatalf("failed to refresh token: %v", err)
+		} else if refreshed.Msg.Token == "" {
+			t.Fatalf("refresh did not return an access token")
+		}
+
+		profile, err := env.Management.Get(ctx, testenv.Authorize(&management.GetRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to get profile: %v", err)
+		} else if profile.Msg.User.Id != userId || profile.Msg.User.Username != "monk" {
+			t.Fatalf("unexpected profile: %v", profile.Msg.User)
+		}
+
+		start := time.Now().Unix()
+		_, err = env.Planning.Upsert(ctx, testenv.Authorize(&planning.UpsertRequest{
+			Events: []*scheduler.Event{{Name: "meditate", StartTime: start, StopTime: start + 1}},
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to upsert events: %v", err)
+		}
+		events, err := env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{
+			Since: start - 60, Until: start + 60,
+		}, token))
+		if err != nil {
+			t.Fatalf("failed to get events: %v", err)
+		} else if len(events.Msg.Events) != 1 || events.Msg.Events[0].Name != "meditate" {
+			t.Fatalf("unexpected events: %v", events.Msg.Events)
+		}
+		id := events.Msg.Events[0].Id
+
+		if _, err := env.Timing.Start(ctx, testenv.Authorize(&timing.StartRequest{Id: id}, token)); err != nil {
+			t.Fatalf("failed to start timer: %v", err)
+		}
+		stop, err := env.Timing.Stop(ctx, testenv.Authorize(&timing.StopRequest{Id: id}, token))
+		if err != nil {
+			t.Fatalf("failed to stop timer: %v", err)
+		}
+		if _, err := env.Timing.Stop(ctx, testenv.Authorize(&timing.StopRequest{Id: id}, token)); connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Fatalf("expected concluded event to be immutable, got: %v", err)
+		}
+
+		profile, err = env.Management.Get(ctx, testenv.Authorize(&management.GetRequest{}, token))
+		if err != nil {
+			t.Fatalf("failed to get profile: %v", err)
+		} else if profile.Msg.User.Score != stop.Msg.RatingChange {
+			t.Fatalf("expected score %f, got %f", stop.Msg.RatingChange, profile.Msg.User.Score)
+		}
+
+		board := env.Board(t, func(b *leaderboard.Board) bool {
+			_, ok := b.Entries[userId]
+			return ok
+		})
+		entry := board.Entries[userId]
+		if entry.Username != "monk" || len(entry.Rating) != 1 {
+			t.Fatalf("unexpected board entry: %v", entry)
+		}
+		for _, change := range entry.Rating {
+			if change != stop.Msg.RatingChange {
+				t.Fatalf("expected board rating %f, got %f", stop.Msg.RatingChange, change)
+			}
+		}
+	})
+}
+
+func TestUnauthenticated(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+
+		_, err := env.Planning.Get(ctx, connect.NewRequest(&planning.GetRequest{}))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected unauthenticated, got: %v", err)
+		}
+		_, err = env.Management.Get(ctx, testenv.Authorize(&management.GetRequest{}, "invalid"))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected unauthenticated, got: %v", err)
+		}
+
+		// refresh tokens are only accepted by the login.
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		env.Login(t, "monk@zen.test", true)
+		_, err = env.Planning.Get(ctx, testenv.Authorize(&planning.GetRequest{}, env.RefreshCookie()))
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("expected refresh token to be rejected, got: %v", err)
+		}
+	})
+}
+
+func TestRegisterCaptcha(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		u := &manager.User{Email: "monk@zen.test", Username: "monk"}
+
+		challenge, err := env.Management.Register(ctx, connect.NewRequest(&management.RegisterRequest{
+			User: u, Verifier: &manager.Verifier{Email: u.Email},
+		}))
+		if err != nil {
+			t.Fatalf("failed to request captcha: %v", err)
+		} else if len(challenge.Msg.CaptchaBlob) == 0 {
+			t.Fatalf("captcha image is empty")
+		}
+		_, err = env.Management.Register(ctx, connect.NewRequest(&management.RegisterRequest{
+			User: u, CaptchaId: challenge.Msg.CaptchaId, CaptchaDigits: "x",
+			Verifier: &manager.Verifier{Email: u.Email},
+		}))
+		if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+			t.Fatalf("expected incorrect captcha to be rejected, got: %v", err)
+		}
+		if len(env.Mailer.Messages(u.Email)) != 0 {
+			t.Fatalf("mail was sent without solved captcha")
+		}
+
+		env.Register(t, u)
+		_, err = env.Management.Register(ctx, connect.NewRequest(&management.RegisterRequest{
+			User: u, CaptchaId: challenge.Msg.CaptchaId,
+			Verifier: &manager.Verifier{Stage: manager.VerifierStage_VERIFIER_STAGE_CODE, Email: u.Email},
+		}))
+		if connect.CodeOf(err) != connect.CodeAlreadyExists {
+			t.Fatalf("expected duplicate registration to be rejected, got: %v", err)
+		}
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		env.Register(t, &manager.User{Email: "monk@zen.test", Username: "monk"})
+		env.Register(t, &manager.User{Email: "nomad@zen.test", Username: "nomad"})
+		monk := env.Login(t, "monk@zen.test", false)
+		nomad := env.Login(t, "nomad@zen.test", false)
+
+		// subject limits are tracked per user.
+		var err error
+		for range 11 {
+			_, err = env.Timing.Stop(ctx, testenv.Authorize(&timing.StopRequest{Id: "0"}, monk))
+		}
+		if connect.CodeOf(err) != connect.CodeResourceExhausted {
+			t.Fatalf("expected stop to be rate limited, got: %v", err)
+		}
+		_, err = env.Timing.Stop(ctx, testenv.Authorize(&timing.StopRequest{Id: "0"}, nomad))
+		if connect.CodeOf(err) == connect.CodeResourceExhausted {
+			t.Fatalf("expected other user not to be rate limited")
+		}
+
+		// ip limits are shared by all requests from the same address (registrations above included).
+		for range 10 {
+			_, err = env.Management.Register(ctx, connect.NewRequest(&management.RegisterRequest{
+				User:     &manager.User{Email: "spam@zen.test", Username: "spam"},
+				Verifier: &manager.Verifier{Email: "spam@zen.test"},
+			}))
+			if connect.CodeOf(err) == connect.CodeResourceExhausted {
+				break
+			}
+		}
+		var connectErr *connect.Error
+		if !errors.As(err, &connectErr) || connectErr.Code() != connect.CodeResourceExhausted {
+			t.Fatalf("expected register to be rate limited, got: %v", err)
+		} else if connectErr.Meta().Get("Retry-After") == "" {
+			t.Fatalf("expected rate limited response to carry retry-after metadata")
+		}
+	})
+}
+
+func TestBoards(t *testing.T) {
+	testenv.Run(t, func(t *testing.T, env *testenv.Env) {
+		ctx := context.Background()
+		now := time.Now()
+		err := env.Boards.PutBoard(ctx, now, &leaderboard.Board{
+			Algorithms: map[string]int64{},
+			Entries: map[string]leaderboard.BoardEntry{
+				"monk": {UserId: "monk", Username: "monk"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to put board: %v", err)
+		}
+		year, week := now.ISOWeek()
+		board := &leaderboard.Board{}
+		getJson(t, env, fmt.Sprintf("/leaderboard/%d-%d.json", year, week), board)
+		if board.Entries["monk"].Username != "monk" {
+			t.Fatalf("unexpected board entries: %+v", board.Entries)
+		}
+
+		// only existing boards with a canonical name are served (no listing, no other files).
+		for _, path := range []string{
+			"/leaderboard/",
+			"/leaderboard/.lock",
+			"/leaderboard/../go.mod",
+			"/leaderboard/%2e%2e%2fgo.mod",
+			fmt.Sprintf("/leaderboard/%d-0%d.json", year, week),
+			fmt.Sprintf("/leaderboard/%d-%d.json", year-1, week),
+			fmt.Sprintf("/leaderboard/%d-54.json", year),
+			"/leaderboard/2025-07.json",
+		} {
+			resp, err := env.Client.Get(env.Server.URL + path)
+			if err != nil {
+				t.Fatalf("failed to get '%s': %v", path, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Fatalf("expected '%s' to be rejected; got status %d", path, resp.StatusCode)
+			}
+		}
+	})
+}
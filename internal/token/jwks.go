@@ -0,0 +1,99 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Publisher provides the public keys of the signer, they are published so that other services can verify tokens offline.
+type Publisher interface {
+	PublicKeys(ctx context.Context) ([]PublicKey, error)
+}
+
+// PublicKey is a verification key together with the algorithm ("alg" header) of the tokens it verifies.
+type PublicKey struct {
+	Key       crypto.PublicKey
+	Algorithm string
+}
+
+// JWK is the json web key representation (RFC 7517) of an ECDSA or Ed25519 public key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK encodes the public key, the key id is the RFC 7638 thumbprint of the key.
+func NewJWK(key PublicKey) (*JWK, error) {
+	jwk := &JWK{Alg: key.Algorithm, Use: "sig"}
+	switch key := key.Key.(type) {
+	case *ecdsa.PublicKey:
+		// coordinates are padded to the full size of the curve.
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = key.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	default:
+		return nil, fmt.Errorf("unsupported key type '%T'; expected ecdsa or ed25519", key)
+	}
+	// the thumbprint covers the required members in lexicographic order (json.Marshal sorts map keys).
+	members := map[string]string{"kty": jwk.Kty, "crv": jwk.Crv, "x": jwk.X}
+	if jwk.Y != "" {
+		members["y"] = jwk.Y
+	}
+	rawMembers, err := json.Marshal(members)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode thumbprint: %v", err)
+	}
+	thumbprint := sha256.Sum256(rawMembers)
+	jwk.Kid = base64.RawURLEncoding.EncodeToString(thumbprint[:])
+	return jwk, nil
+}
+
+// KeyId returns the key id (kid header) of the public key, the RFC 7638 thumbprint.
+func KeyId(key crypto.PublicKey) (string, error) {
+	jwk, err := NewJWK(PublicKey{Key: key})
+	if err != nil {
+		return "", err
+	}
+	return jwk.Kid, nil
+}
+
+// JWKS returns the key set that verifies the tokens issued by the controller.
+func (c *Controller) JWKS(ctx context.Context) (*JWKSet, error) {
+	if c.Publisher == nil {
+		return nil, fmt.Errorf("controller has no publisher")
+	}
+	keys, err := c.Publisher.PublicKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set := &JWKSet{Keys: []JWK{}}
+	for _, key := range keys {
+		jwk, err := NewJWK(key)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, *jwk)
+	}
+	return set, nil
+}
@@ -0,0 +1,85 @@
+package token
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/matelang/jwt-go-aws-kms/v2/jwtkms"
+)
+
+// KmsProvider signs and verifies tokens with an ECDSA P-256 key stored in aws kms.
+type KmsProvider struct {
+	client jwtkms.KMSClient
+	keyId  string
+	config *jwtkms.Config
+
+	publicKeyLock sync.Mutex
+	publicKey     *PublicKey
+	kid           string
+}
+
+func NewKms(client jwtkms.KMSClient, keyId string) *KmsProvider {
+	return &KmsProvider{
+		client: client,
+		keyId:  keyId,
+		config: jwtkms.NewKMSConfig(client, keyId, false),
+	}
+}
+
+func (p *KmsProvider) Sign(ctx context.Context, claims jwt.Claims) (string, error) {
+	_, kid, err := p.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwtkms.SigningMethodECDSA256, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(p.config.WithContext(ctx))
+}
+
+func (p *KmsProvider) Methods() []string {
+	return []string{jwtkms.SigningMethodECDSA256.Alg()}
+}
+
+func (p *KmsProvider) Key(ctx context.Context, token *jwt.Token) (any, error) {
+	return p.config.WithContext(ctx), nil
+}
+
+func (p *KmsProvider) PublicKeys(ctx context.Context) ([]PublicKey, error) {
+	publicKey, _, err := p.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []PublicKey{*publicKey}, nil
+}
+
+// load returns the public key of the kms key and its key id,
+// the key is fetched once and cached for the lifetime of the provider.
+func (p *KmsProvider) load(ctx context.Context) (*PublicKey, string, error) {
+	p.publicKeyLock.Lock()
+	defer p.publicKeyLock.Unlock()
+	if p.publicKey != nil {
+		return p.publicKey, p.kid, nil
+	}
+	result, err := p.client.GetPublicKey(ctx, &kms.GetPublicKeyInput{
+		KeyId: aws.String(p.keyId),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get public key: %v", err)
+	}
+	key, err := x509.ParsePKIXPublicKey(result.PublicKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse public key: %v", err)
+	}
+	kid, err := KeyId(key)
+	if err != nil {
+		return nil, "", err
+	}
+	p.publicKey = &PublicKey{Key: key, Algorithm: jwtkms.SigningMethodECDSA256.Alg()}
+	p.kid = kid
+	return p.publicKey, p.kid, nil
+}
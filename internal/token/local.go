@@ -0,0 +1,150 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LocalProvider signs and verifies tokens with an in-process ECDSA or Ed25519 key.
+// It is intended for development, tests and self-hosted setups without aws kms.
+type LocalProvider struct {
+	key    crypto.Signer
+	kid    string
+	method jwt.SigningMethod
+}
+
+// NewLocal creates a provider from an ECDSA (P-256, P-384, P-521) or Ed25519 private key.
+func NewLocal(key crypto.Signer) (*LocalProvider, error) {
+	var method jwt.SigningMethod
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			method = jwt.SigningMethodES256
+		case elliptic.P384():
+			method = jwt.SigningMethodES384
+		case elliptic.P521():
+			method = jwt.SigningMethodES512
+		default:
+			return nil, fmt.Errorf("unsupported ecdsa curve '%s'", key.Curve.Params().Name)
+		}
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type '%T'; expected ecdsa or ed25519", key)
+	}
+	kid, err := KeyId(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &LocalProvider{key, kid, method}, nil
+}
+
+// GenerateLocal creates a provider with a new random key for the specified algorithm (ES256, ES384, ES512 or EdDSA).
+func GenerateLocal(algorithm string) (*LocalProvider, error) {
+	var key crypto.Signer
+	var err error
+	switch algorithm {
+	case "ES256":
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ES384":
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "ES512":
+		key, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case "EdDSA":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm '%s'; expected 'ES256', 'ES384', 'ES512' or 'EdDSA'", algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+	return NewLocal(key)
+}
+
+// LoadLocal creates a provider from the PEM encoded private key (PKCS#8 or SEC 1) at path.
+// If the file does not exist, a new key for the specified algorithm is generated and written to path.
+func LoadLocal(path, algorithm string) (*LocalProvider, error) {
+	rawKey, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		provider, err := GenerateLocal(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		if err := provider.writeKey(path); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read key: %v", err)
+	}
+
+	block, _ := pem.Decode(rawKey)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode key: no pem block found in '%s'", path)
+	}
+	var key any
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported pem block '%s'; expected 'PRIVATE KEY' or 'EC PRIVATE KEY'", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key: %v", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type '%T'", key)
+	}
+	return NewLocal(signer)
+}
+
+// writeKey stores the private key PKCS#8 encoded at path (the file must not exist).
+func (p *LocalProvider) writeKey(path string) error {
+	rawKey, err := x509.MarshalPKCS8PrivateKey(p.key)
+	if err != nil {
+		return fmt.Errorf("failed to encode key: %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %v", err)
+	}
+	defer file.Close()
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: rawKey}); err != nil {
+		return fmt.Errorf("failed to write key: %v", err)
+	}
+	return nil
+}
+
+func (p *LocalProvider) Sign(ctx context.Context, claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(p.method, claims)
+	token.Header["kid"] = p.kid
+	return token.SignedString(p.key)
+}
+
+func (p *LocalProvider) Methods() []string {
+	return []string{p.method.Alg()}
+}
+
+func (p *LocalProvider) Key(ctx context.Context, token *jwt.Token) (any, error) {
+	return p.key.Public(), nil
+}
+
+func (p *LocalProvider) PublicKeys(ctx context.Context) ([]PublicKey, error) {
+	return []PublicKey{{Key: p.key.Public(), Algorithm: p.method.Alg()}}, nil
+}
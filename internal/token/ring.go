@@ -0,0 +1,86 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyRing combines multiple providers to rotate the signing key without invalidating the issued tokens.
+// Tokens are signed by the active provider and verified by the provider whose key matches the "kid" header,
+// all keys are published (the next key before it becomes active, the previous key until it is retired).
+type KeyRing struct {
+	active    Provider
+	providers []Provider
+}
+
+// NewKeyRing creates a ring that signs with the active provider and additionally verifies with the others.
+func NewKeyRing(active Provider, others ...Provider) *KeyRing {
+	return &KeyRing{
+		active:    active,
+		providers: append([]Provider{active}, others...),
+	}
+}
+
+func (r *KeyRing) Sign(ctx context.Context, claims jwt.Claims) (string, error) {
+	return r.active.Sign(ctx, claims)
+}
+
+func (r *KeyRing) Methods() []string {
+	methods := []string{}
+	for _, provider := range r.providers {
+		for _, method := range provider.Methods() {
+			if !slices.Contains(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
+
+// Key returns the key of the provider matching the "kid" header.
+// Tokens without kid were issued before key ids were introduced, they are verified with the keys of all providers.
+func (r *KeyRing) Key(ctx context.Context, token *jwt.Token) (any, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		set := jwt.VerificationKeySet{Keys: []jwt.VerificationKey{}}
+		for _, provider := range r.providers {
+			key, err := provider.Key(ctx, token)
+			if err != nil {
+				return nil, err
+			}
+			set.Keys = append(set.Keys, key)
+		}
+		return set, nil
+	}
+	for _, provider := range r.providers {
+		keys, err := provider.PublicKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			id, err := KeyId(key.Key)
+			if err != nil {
+				return nil, err
+			}
+			if id == kid {
+				return provider.Key(ctx, token)
+			}
+		}
+	}
+	return nil, fmt.Errorf("token is signed with an unknown key '%s'", kid)
+}
+
+func (r *KeyRing) PublicKeys(ctx context.Context) ([]PublicKey, error) {
+	keys := []PublicKey{}
+	for _, provider := range r.providers {
+		providerKeys, err := provider.PublicKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, providerKeys...)
+	}
+	return keys, nil
+}
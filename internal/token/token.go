@@ -10,19 +10,67 @@ import (
 	"connectrpc.com/connect"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+)
+
+// Signer signs the tokens issued by the controller.
+type Signer interface {
+	Sign(ctx context.Context, claims jwt.Claims) (string, error)
+}
+
+// Verifier provides the key material used to verify tokens.
+type Verifier interface {
+	// Methods returns the accepted signing algorithms (the token "alg" header must match one of them).
+	Methods() []string
+	// Key returns the key used to verify the parsed (unverified) token.
+	Key(ctx context.Context, token *jwt.Token) (any, error)
+}
+
+// Provider combines a Signer, Verifier and Publisher backed by the same key.
+type Provider interface {
+	Signer
+	Verifier
+	Publisher
+}
 
-	"github.com/matelang/jwt-go-aws-kms/v2/jwtkms"
+// Registry tracks the issued personal access tokens, revoked (or expired) tokens are no longer registered.
+type Registry interface {
+	ExistsPersonalToken(ctx context.Context, sub, id string) (bool, error)
+}
+
+// Accounts resolves the user that is currently registered with an email.
+type Accounts interface {
+	RegisteredUser(ctx context.Context, email string) (string, bool, error)
+}
+
+const (
+	ScopePlanningRead  = "planning:read"
+	ScopePlanningWrite = "planning:write"
+	ScopeTiming        = "timing"
+	ScopeProfileRead   = "profile:read"
 )
 
+// Scopes lists the scopes that can be granted to personal access tokens.
+var Scopes = []string{ScopePlanningRead, ScopePlanningWrite, ScopeTiming, ScopeProfileRead}
+
 type Controller struct {
-	Issuer    string
-	KmsConfig *jwtkms.Config
+	Issuer   string
+	Signer   Signer
+	Verifier Verifier
+	// Publisher provides the public keys for the JWKS, optional.
+	Publisher Publisher
+	// Registry is used to check if personal access tokens are revoked, without registry they are rejected.
+	Registry Registry
+	// Accounts is used to check that the email claim is still registered to the subject,
+	// this invalidates the tokens after the email was changed (or the account deleted). Optional.
+	Accounts Accounts
 }
 
-func New(issuer string, kms *jwtkms.Config) *Controller {
+func New(issuer string, provider Provider) *Controller {
 	return &Controller{
 		Issuer:    issuer,
-		KmsConfig: kms,
+		Signer:    provider,
+		Verifier:  provider,
+		Publisher: provider,
 	}
 }
 
@@ -30,12 +78,78 @@ type TokenClaims struct {
 	jwt.RegisteredClaims
 	Email   string `json:"email,omitempty"`
 	Refresh bool   `json:"refresh,omitempty"`
+	// Session identifies the refresh token family the token was issued from.
+	Session string `json:"sid,omitempty"`
+	// Personal marks personal access tokens, they are restricted to the granted Scopes.
+	Personal bool     `json:"pat,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// Allow checks if the token grants the scope. Tokens from the interactive login grant all scopes.
+func (c *TokenClaims) Allow(scope string) error {
+	if c.Personal && !slices.Contains(c.Scopes, scope) {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("token does not grant the '%s' scope", scope))
+	}
+	return nil
+}
+
+// Interactive checks that the token was issued by the interactive login (rejects personal access tokens).
+func (c *TokenClaims) Interactive() error {
+	if c.Personal {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("operation is not permitted for personal access tokens"))
+	}
+	return nil
+}
+
+// Issue signs a new token and returns it together with its unique id (jti).
+func (c *Controller) Issue(ctx context.Context, subject, email, session string, refresh bool, expiresAt time.Time) (string, string, error) {
+	return c.sign(ctx, subject, expiresAt, func(claims *TokenClaims) {
+		claims.Email = email
+		claims.Refresh = refresh
+		claims.Session = session
+	})
 }
 
-func (c *Controller) Issue(ctx context.Context, subject, email string, refresh bool, expiresAt time.Time) (string, error) {
-	token := jwt.NewWithClaims(jwtkms.SigningMethodECDSA256, &TokenClaims{
+// IssuePersonal signs a new personal access token restricted to the scopes and returns it together with its unique id (jti).
+// The id must be registered in the Registry, otherwise the token is rejected.
+func (c *Controller) IssuePersonal(ctx context.Context, subject, email string, scopes []string, expiresAt time.Time) (string, string, error) {
+	return c.sign(ctx, subject, expiresAt, func(claims *TokenClaims) {
+		claims.Email = email
+		claims.Personal = true
+		claims.Scopes = scopes
+	})
+}
+
+// IssueLink signs a short-lived token that references the server-side state (id) of a link sent to the user.
+// The purpose is part of the audience, link tokens are therefore rejected by Verify (and by other purposes).
+func (c *Controller) IssueLink(ctx context.Context, purpose, id string, expiresAt time.Time) (string, error) {
+	token, _, err := c.sign(ctx, id, expiresAt, func(claims *TokenClaims) {
+		claims.Audience = jwt.ClaimStrings{linkAudience(c.Issuer, purpose)}
+	})
+	return token, err
+}
+
+// VerifyLink verifies a token issued by IssueLink for the purpose and returns the referenced id.
+func (c *Controller) VerifyLink(ctx context.Context, purpose, token string) (string, error) {
+	claims, err := c.parse(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(claims.Audience, linkAudience(c.Issuer, purpose)) {
+		return "", connect.NewError(connect.CodePermissionDenied, fmt.Errorf("token was not issued for this audience"))
+	}
+	return claims.Subject, nil
+}
+
+func linkAudience(issuer, purpose string) string {
+	return fmt.Sprintf("%s#%s", issuer, purpose)
+}
+
+func (c *Controller) sign(ctx context.Context, subject string, expiresAt time.Time, fn func(claims *TokenClaims)) (string, string, error) {
+	id := uuid.New().String()
+	claims := &TokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			ID:        uuid.New().String(),
+			ID:        id,
 			Audience:  jwt.ClaimStrings{c.Issuer}, // rp and resource server are the same entity, so aud == iss
 			Issuer:    c.Issuer,
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -43,26 +157,69 @@ func (c *Controller) Issue(ctx context.Context, subject, email string, refresh b
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			Subject:   subject,
 		},
-		Email:   email,
-		Refresh: refresh,
-	})
-	signedToken, err := token.SignedString(c.KmsConfig.WithContext(ctx))
+	}
+	fn(claims)
+	signedToken, err := c.Signer.Sign(ctx, claims)
 	if err != nil {
-		return "", connect.NewError(connect.CodeInternal, err)
+		return "", "", connect.NewError(connect.CodeInternal, err)
 	}
-	return signedToken, nil
+	return signedToken, id, nil
 }
 
 func (c *Controller) Verify(ctx context.Context, token string) (*TokenClaims, error) {
+	claims, err := c.parse(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(claims.Audience, c.Issuer) {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("token was not issued for this audience"))
+	}
+	if c.Accounts != nil {
+		sub, found, err := c.Accounts.RegisteredUser(ctx, claims.Email)
+		if err != nil {
+			return nil, err
+		} else if !found || sub != claims.Subject {
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("token email is no longer registered to the user"))
+		}
+	}
+	if claims.Personal {
+		if c.Registry == nil {
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("personal access tokens are not supported"))
+		}
+		registered, err := c.Registry.ExistsPersonalToken(ctx, claims.Subject, claims.ID)
+		if err != nil {
+			return nil, err
+		} else if !registered {
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("token was revoked"))
+		}
+	}
+	return claims, nil
+}
+
+// parse verifies the signature and the time based claims of the token.
+func (c *Controller) parse(ctx context.Context, token string) (*TokenClaims, error) {
 	claims := &TokenClaims{}
 	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
-		return c.KmsConfig, nil
-	})
+		return c.Verifier.Key(ctx, t)
+	}, jwt.WithValidMethods(c.Verifier.Methods()))
 	if err != nil {
 		return nil, connect.NewError(connect.CodePermissionDenied, err)
 	}
-	if !slices.Contains(claims.Audience, c.Issuer) {
-		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("token was not issued for this audience"))
+	return claims, nil
+}
+
+type claimsKey struct{}
+
+// NewContext returns a copy of the context that carries the verified claims of the request.
+func NewContext(ctx context.Context, claims *TokenClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims the request was authenticated with (see interceptor.NewAuth).
+func FromContext(ctx context.Context) (*TokenClaims, error) {
+	claims, ok := ctx.Value(claimsKey{}).(*TokenClaims)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("request is not authenticated"))
 	}
 	return claims, nil
 }
@@ -0,0 +1,243 @@
+// package totp implements the totp second factor (rfc 6238) including recovery codes.
+package totp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/megakuul/zen/internal/lockout"
+	"github.com/megakuul/zen/internal/model/ceremony"
+	"github.com/megakuul/zen/internal/model/user"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	period = 30 * time.Second
+	// number of adjacent steps accepted to compensate clock drift.
+	skew              = 1
+	recoveryCodeCount = 10
+	ceremonyTTL       = 5 * time.Minute
+
+	loginCeremony = "TOTP_LOGIN"
+)
+
+type Controller struct {
+	issuer        string
+	totpModel     user.TotpStore
+	ceremonyModel ceremony.Store
+}
+
+// New creates a totp controller, the issuer is shown next to the account in the authenticator app.
+func New(issuer string, totps user.TotpStore, ceremonies ceremony.Store) *Controller {
+	return &Controller{
+		issuer:        issuer,
+		totpModel:     totps,
+		ceremonyModel: ceremonies,
+	}
+}
+
+// Enrollment contains the secret of a pending enrollment in the formats accepted by authenticator apps.
+type Enrollment struct {
+	Uri    string
+	Secret string
+	Qr     []byte
+}
+
+// Enroll generates a new secret for the user, it is not enforced until the enrollment is confirmed.
+func (c *Controller) Enroll(ctx context.Context, sub, account string) (*Enrollment, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      c.issuer,
+		AccountName: account,
+		Period:      uint(period.Seconds()),
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	qr := &bytes.Buffer{}
+	if err := png.Encode(qr, img); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	err = c.totpModel.PutTotp(ctx, sub, &user.Totp{
+		Secret:    key.Secret(),
+		Enabled:   false,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Enrollment{
+		Uri:    key.URL(),
+		Secret: key.Secret(),
+		Qr:     qr.Bytes(),
+	}, nil
+}
+
+// Confirm enables the pending enrollment if the code matches. Returns the recovery codes (only shown once).
+func (c *Controller) Confirm(ctx context.Context, sub, code string) ([]string, error) {
+	secret, found, err := c.totpModel.GetTotp(ctx, sub)
+	if err != nil {
+		return nil, err
+	} else if !found || secret.Enabled {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("totp is not enrolled or already enabled"))
+	}
+	step, ok := validate(secret.Secret, code)
+	if !ok {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("incorrect code - permission denied"))
+	}
+
+	recoveryCodes := make([]string, recoveryCodeCount)
+	recoveryHashes := make([]string, recoveryCodeCount)
+	for i := range recoveryCodes {
+		text := strings.ToLower(rand.Text())
+		recoveryCodes[i] = fmt.Sprintf("%s-%s", text[:5], text[5:10])
+		recoveryHashes[i] = hashRecoveryCode(recoveryCodes[i])
+	}
+	if err := c.totpModel.EnableTotp(ctx, sub, step, recoveryHashes); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// Enabled reports whether the user must provide a totp code.
+func (c *Controller) Enabled(ctx context.Context, sub string) (bool, error) {
+	secret, found, err := c.totpModel.GetTotp(ctx, sub)
+	if err != nil {
+		return false, err
+	}
+	return found && secret.Enabled, nil
+}
+
+// Verify checks the totp or recovery code (recovery codes contain a dash) of the user.
+// Codes are single use, failed attempts lock the second factor with an exponential backoff.
+func (c *Controller) Verify(ctx context.Context, sub, code string) error {
+	secret, found, err := c.totpModel.GetTotp(ctx, sub)
+	if err != nil {
+		return err
+	} else if !found || !secret.Enabled {
+		return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("totp is not enabled"))
+	} else if secret.LockedUntil > time.Now().Unix() {
+		return lockout.Error(time.Unix(secret.LockedUntil, 0))
+	}
+
+	if strings.Contains(code, "-") {
+		err = c.totpModel.UseTotpRecoveryCode(ctx, sub, hashRecoveryCode(code))
+	} else if step, ok := validate(secret.Secret, code); ok {
+		err = c.totpModel.UseTotpStep(ctx, sub, step)
+	} else {
+		err = connect.NewError(connect.CodePermissionDenied, fmt.Errorf("incorrect code - permission denied"))
+	}
+	if err != nil {
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			return err
+		}
+		secret, failErr := c.totpModel.AddTotpFailure(ctx, sub)
+		if failErr != nil {
+			return failErr
+		}
+		if lockedUntil, locked := lockout.Until(secret.Failures); locked {
+			if lockErr := c.totpModel.LockTotp(ctx, sub, lockedUntil); lockErr != nil {
+				return lockErr
+			}
+			return lockout.Error(lockedUntil)
+		}
+		return err
+	}
+	return nil
+}
+
+// Disable removes the second factor after verifying the totp or recovery code.
+func (c *Controller) Disable(ctx context.Context, sub, code string) error {
+	if err := c.Verify(ctx, sub, code); err != nil {
+		return err
+	}
+	return c.totpModel.DeleteTotp(ctx, sub)
+}
+
+// LoginState is the state of a login that passed the first factor and awaits the totp code.
+type LoginState struct {
+	Subject string
+	Email   string
+	// Method describes the first factor of the login.
+	Method      string
+	AutoRefresh bool
+}
+
+// BeginLogin stores the login state and returns the ceremony id that must be presented with the totp code.
+func (c *Controller) BeginLogin(ctx context.Context, state *LoginState) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", connect.NewError(connect.CodeInternal, err)
+	}
+	id := rand.Text()
+	err = c.ceremonyModel.PutCeremony(ctx, loginCeremony, id, &ceremony.Ceremony{
+		Subject:   state.Subject,
+		Data:      string(data),
+		ExpiresAt: time.Now().Add(ceremonyTTL).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// FinishLogin verifies the totp code for the login ceremony and returns the login state.
+// An incorrect code keeps the ceremony open so the user can retry (until the second factor is locked).
+func (c *Controller) FinishLogin(ctx context.Context, id, code string) (*LoginState, error) {
+	cer, found, err := c.ceremonyModel.ConsumeCeremony(ctx, loginCeremony, id)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("ceremony does not exist or has expired"))
+	}
+	state := &LoginState{}
+	if err := json.Unmarshal([]byte(cer.Data), state); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	if err := c.Verify(ctx, state.Subject, code); err != nil {
+		if connect.CodeOf(err) == connect.CodePermissionDenied || connect.CodeOf(err) == connect.CodeFailedPrecondition {
+			if putErr := c.ceremonyModel.PutCeremony(ctx, loginCeremony, id, cer); putErr != nil {
+				return nil, putErr
+			}
+		}
+		return nil, err
+	}
+	return state, nil
+}
+
+// validate compares the code with the codes of the current and adjacent steps. Returns the matching step.
+func validate(secret, code string) (int64, bool) {
+	now := time.Now()
+	for i := -skew; i <= skew; i++ {
+		t := now.Add(time.Duration(i) * period)
+		expected, err := totp.GenerateCodeCustom(secret, t, totp.ValidateOpts{
+			Period:    uint(period.Seconds()),
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err == nil && expected == code {
+			return t.Unix() / int64(period.Seconds()), true
+		}
+	}
+	return 0, false
+}
+
+func hashRecoveryCode(code string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(hash[:])
+}
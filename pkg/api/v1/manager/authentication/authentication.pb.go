@@ -23,9 +23,13 @@ const (
 )
 
 type LoginRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Verifier      *manager.Verifier      `protobuf:"bytes,1,opt,name=verifier,proto3" json:"verifier,omitempty"`
-	AutoRefresh   bool                   `protobuf:"varint,2,opt,name=auto_refresh,json=autoRefresh,proto3" json:"auto_refresh,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Verifier    *manager.Verifier      `protobuf:"bytes,1,opt,name=verifier,proto3" json:"verifier,omitempty"`
+	AutoRefresh bool                   `protobuf:"varint,2,opt,name=auto_refresh,json=autoRefresh,proto3" json:"auto_refresh,omitempty"`
+	// sends a sign-in link along with the code (email stage), the link only works on the requesting device.
+	MagicLink bool `protobuf:"varint,3,opt,name=magic_link,json=magicLink,proto3" json:"magic_link,omitempty"`
+	// token of the opened sign-in link, completes the login instead of the verifier.
+	LinkToken     string `protobuf:"bytes,4,opt,name=link_token,json=linkToken,proto3" json:"link_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,11 +78,27 @@ func (x *LoginRequest) GetAutoRefresh() bool {
 	return false
 }
 
+func (x *LoginRequest) GetMagicLink() bool {
+	if x != nil {
+		return x.MagicLink
+	}
+	return false
+}
+
+func (x *LoginRequest) GetLinkToken() string {
+	if x != nil {
+		return x.LinkToken
+	}
+	return ""
+}
+
 type LoginResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// set instead of the token if the user has totp enabled, the login is completed with VerifyTotp.
+	TotpCeremonyId string `protobuf:"bytes,2,opt,name=totp_ceremony_id,json=totpCeremonyId,proto3" json:"totp_ceremony_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *LoginResponse) Reset() {
@@ -118,6 +138,13 @@ func (x *LoginResponse) GetToken() string {
 	return ""
 }
 
+func (x *LoginResponse) GetTotpCeremonyId() string {
+	if x != nil {
+		return x.TotpCeremonyId
+	}
+	return ""
+}
+
 type LogoutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -190,21 +217,549 @@ func (*LogoutResponse) Descriptor() ([]byte, []int) {
 	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{3}
 }
 
+type BeginPasskeyLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyLoginRequest) Reset() {
+	*x = BeginPasskeyLoginRequest{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyLoginRequest) ProtoMessage() {}
+
+func (x *BeginPasskeyLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyLoginRequest.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyLoginRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{4}
+}
+
+type BeginPasskeyLoginResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CeremonyId string                 `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	// json encoded credential request options for navigator.credentials.get().
+	Options       string `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyLoginResponse) Reset() {
+	*x = BeginPasskeyLoginResponse{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyLoginResponse) ProtoMessage() {}
+
+func (x *BeginPasskeyLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyLoginResponse.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyLoginResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BeginPasskeyLoginResponse) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+func (x *BeginPasskeyLoginResponse) GetOptions() string {
+	if x != nil {
+		return x.Options
+	}
+	return ""
+}
+
+type FinishPasskeyLoginRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CeremonyId string                 `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	// json encoded public key credential returned by navigator.credentials.get().
+	Credential    string `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"`
+	AutoRefresh   bool   `protobuf:"varint,3,opt,name=auto_refresh,json=autoRefresh,proto3" json:"auto_refresh,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyLoginRequest) Reset() {
+	*x = FinishPasskeyLoginRequest{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyLoginRequest) ProtoMessage() {}
+
+func (x *FinishPasskeyLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyLoginRequest.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyLoginRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FinishPasskeyLoginRequest) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginRequest) GetAutoRefresh() bool {
+	if x != nil {
+		return x.AutoRefresh
+	}
+	return false
+}
+
+type FinishPasskeyLoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyLoginResponse) Reset() {
+	*x = FinishPasskeyLoginResponse{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyLoginResponse) ProtoMessage() {}
+
+func (x *FinishPasskeyLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyLoginResponse.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyLoginResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FinishPasskeyLoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type VerifyTotpRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CeremonyId string                 `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	// totp code or one of the recovery codes.
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTotpRequest) Reset() {
+	*x = VerifyTotpRequest{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTotpRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTotpRequest) ProtoMessage() {}
+
+func (x *VerifyTotpRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTotpRequest.ProtoReflect.Descriptor instead.
+func (*VerifyTotpRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *VerifyTotpRequest) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+func (x *VerifyTotpRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type VerifyTotpResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTotpResponse) Reset() {
+	*x = VerifyTotpResponse{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTotpResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTotpResponse) ProtoMessage() {}
+
+func (x *VerifyTotpResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTotpResponse.ProtoReflect.Descriptor instead.
+func (*VerifyTotpResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VerifyTotpResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type BeginOidcLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AutoRefresh   bool                   `protobuf:"varint,1,opt,name=auto_refresh,json=autoRefresh,proto3" json:"auto_refresh,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginOidcLoginRequest) Reset() {
+	*x = BeginOidcLoginRequest{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginOidcLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginOidcLoginRequest) ProtoMessage() {}
+
+func (x *BeginOidcLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginOidcLoginRequest.ProtoReflect.Descriptor instead.
+func (*BeginOidcLoginRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BeginOidcLoginRequest) GetAutoRefresh() bool {
+	if x != nil {
+		return x.AutoRefresh
+	}
+	return false
+}
+
+type BeginOidcLoginResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// authorization url of the identity provider the user agent must be redirected to.
+	Url           string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginOidcLoginResponse) Reset() {
+	*x = BeginOidcLoginResponse{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginOidcLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginOidcLoginResponse) ProtoMessage() {}
+
+func (x *BeginOidcLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginOidcLoginResponse.ProtoReflect.Descriptor instead.
+func (*BeginOidcLoginResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BeginOidcLoginResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type FinishOidcLoginRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// state and code query parameters the identity provider attached to the redirect url.
+	// the state is only accepted from the user agent that started the login (device cookie set by BeginOidcLogin).
+	State         string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishOidcLoginRequest) Reset() {
+	*x = FinishOidcLoginRequest{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishOidcLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishOidcLoginRequest) ProtoMessage() {}
+
+func (x *FinishOidcLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishOidcLoginRequest.ProtoReflect.Descriptor instead.
+func (*FinishOidcLoginRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FinishOidcLoginRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *FinishOidcLoginRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type FinishOidcLoginResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// set instead of the token if the user has totp enabled, the login is completed with VerifyTotp.
+	TotpCeremonyId string `protobuf:"bytes,2,opt,name=totp_ceremony_id,json=totpCeremonyId,proto3" json:"totp_ceremony_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FinishOidcLoginResponse) Reset() {
+	*x = FinishOidcLoginResponse{}
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishOidcLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishOidcLoginResponse) ProtoMessage() {}
+
+func (x *FinishOidcLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_authentication_authentication_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishOidcLoginResponse.ProtoReflect.Descriptor instead.
+func (*FinishOidcLoginResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_authentication_authentication_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FinishOidcLoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *FinishOidcLoginResponse) GetTotpCeremonyId() string {
+	if x != nil {
+		return x.TotpCeremonyId
+	}
+	return ""
+}
+
 var File_v1_manager_authentication_authentication_proto protoreflect.FileDescriptor
 
 const file_v1_manager_authentication_authentication_proto_rawDesc = "" +
 	"\n" +
-	".v1/manager/authentication/authentication.proto\x12\x19v1.manager.authentication\x1a\x19v1/manager/verifier.proto\"c\n" +
+	".v1/manager/authentication/authentication.proto\x12\x19v1.manager.authentication\x1a\x19v1/manager/verifier.proto\"\xa1\x01\n" +
 	"\fLoginRequest\x120\n" +
 	"\bverifier\x18\x01 \x01(\v2\x14.v1.manager.VerifierR\bverifier\x12!\n" +
-	"\fauto_refresh\x18\x02 \x01(\bR\vautoRefresh\"%\n" +
+	"\fauto_refresh\x18\x02 \x01(\bR\vautoRefresh\x12\x1d\n" +
+	"\n" +
+	"magic_link\x18\x03 \x01(\bR\tmagicLink\x12\x1d\n" +
+	"\n" +
+	"link_token\x18\x04 \x01(\tR\tlinkToken\"O\n" +
 	"\rLoginResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"\x0f\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
+	"\x10totp_ceremony_id\x18\x02 \x01(\tR\x0etotpCeremonyId\"\x0f\n" +
 	"\rLogoutRequest\"\x10\n" +
-	"\x0eLogoutResponse2\xd6\x01\n" +
+	"\x0eLogoutResponse\"\x1a\n" +
+	"\x18BeginPasskeyLoginRequest\"V\n" +
+	"\x19BeginPasskeyLoginResponse\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\x12\x18\n" +
+	"\aoptions\x18\x02 \x01(\tR\aoptions\"\x7f\n" +
+	"\x19FinishPasskeyLoginRequest\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tR\n" +
+	"credential\x12!\n" +
+	"\fauto_refresh\x18\x03 \x01(\bR\vautoRefresh\"2\n" +
+	"\x1aFinishPasskeyLoginResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"H\n" +
+	"\x11VerifyTotpRequest\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"*\n" +
+	"\x12VerifyTotpResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\":\n" +
+	"\x15BeginOidcLoginRequest\x12!\n" +
+	"\fauto_refresh\x18\x01 \x01(\bR\vautoRefresh\"*\n" +
+	"\x16BeginOidcLoginResponse\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\"B\n" +
+	"\x16FinishOidcLoginRequest\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"Y\n" +
+	"\x17FinishOidcLoginResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12(\n" +
+	"\x10totp_ceremony_id\x18\x02 \x01(\tR\x0etotpCeremonyId2\xc1\x06\n" +
 	"\x15AuthenticationService\x12\\\n" +
 	"\x05Login\x12'.v1.manager.authentication.LoginRequest\x1a(.v1.manager.authentication.LoginResponse\"\x00\x12_\n" +
-	"\x06Logout\x12(.v1.manager.authentication.LogoutRequest\x1a).v1.manager.authentication.LogoutResponse\"\x00B;Z9github.com/megakuul/zen/pkg/api/v1/manager/authenticationb\x06proto3"
+	"\x06Logout\x12(.v1.manager.authentication.LogoutRequest\x1a).v1.manager.authentication.LogoutResponse\"\x00\x12\x80\x01\n" +
+	"\x11BeginPasskeyLogin\x123.v1.manager.authentication.BeginPasskeyLoginRequest\x1a4.v1.manager.authentication.BeginPasskeyLoginResponse\"\x00\x12\x83\x01\n" +
+	"\x12FinishPasskeyLogin\x124.v1.manager.authentication.FinishPasskeyLoginRequest\x1a5.v1.manager.authentication.FinishPasskeyLoginResponse\"\x00\x12k\n" +
+	"\n" +
+	"VerifyTotp\x12,.v1.manager.authentication.VerifyTotpRequest\x1a-.v1.manager.authentication.VerifyTotpResponse\"\x00\x12w\n" +
+	"\x0eBeginOidcLogin\x120.v1.manager.authentication.BeginOidcLoginRequest\x1a1.v1.manager.authentication.BeginOidcLoginResponse\"\x00\x12z\n" +
+	"\x0fFinishOidcLogin\x121.v1.manager.authentication.FinishOidcLoginRequest\x1a2.v1.manager.authentication.FinishOidcLoginResponse\"\x00B;Z9github.com/megakuul/zen/pkg/api/v1/manager/authenticationb\x06proto3"
 
 var (
 	file_v1_manager_authentication_authentication_proto_rawDescOnce sync.Once
@@ -218,25 +773,45 @@ func file_v1_manager_authentication_authentication_proto_rawDescGZIP() []byte {
 	return file_v1_manager_authentication_authentication_proto_rawDescData
 }
 
-var file_v1_manager_authentication_authentication_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_v1_manager_authentication_authentication_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_v1_manager_authentication_authentication_proto_goTypes = []any{
-	(*LoginRequest)(nil),     // 0: v1.manager.authentication.LoginRequest
-	(*LoginResponse)(nil),    // 1: v1.manager.authentication.LoginResponse
-	(*LogoutRequest)(nil),    // 2: v1.manager.authentication.LogoutRequest
-	(*LogoutResponse)(nil),   // 3: v1.manager.authentication.LogoutResponse
-	(*manager.Verifier)(nil), // 4: v1.manager.Verifier
+	(*LoginRequest)(nil),               // 0: v1.manager.authentication.LoginRequest
+	(*LoginResponse)(nil),              // 1: v1.manager.authentication.LoginResponse
+	(*LogoutRequest)(nil),              // 2: v1.manager.authentication.LogoutRequest
+	(*LogoutResponse)(nil),             // 3: v1.manager.authentication.LogoutResponse
+	(*BeginPasskeyLoginRequest)(nil),   // 4: v1.manager.authentication.BeginPasskeyLoginRequest
+	(*BeginPasskeyLoginResponse)(nil),  // 5: v1.manager.authentication.BeginPasskeyLoginResponse
+	(*FinishPasskeyLoginRequest)(nil),  // 6: v1.manager.authentication.FinishPasskeyLoginRequest
+	(*FinishPasskeyLoginResponse)(nil), // 7: v1.manager.authentication.FinishPasskeyLoginResponse
+	(*VerifyTotpRequest)(nil),          // 8: v1.manager.authentication.VerifyTotpRequest
+	(*VerifyTotpResponse)(nil),         // 9: v1.manager.authentication.VerifyTotpResponse
+	(*BeginOidcLoginRequest)(nil),      // 10: v1.manager.authentication.BeginOidcLoginRequest
+	(*BeginOidcLoginResponse)(nil),     // 11: v1.manager.authentication.BeginOidcLoginResponse
+	(*FinishOidcLoginRequest)(nil),     // 12: v1.manager.authentication.FinishOidcLoginRequest
+	(*FinishOidcLoginResponse)(nil),    // 13: v1.manager.authentication.FinishOidcLoginResponse
+	(*manager.Verifier)(nil),           // 14: v1.manager.Verifier
 }
 var file_v1_manager_authentication_authentication_proto_depIdxs = []int32{
-	4, // 0: v1.manager.authentication.LoginRequest.verifier:type_name -> v1.manager.Verifier
-	0, // 1: v1.manager.authentication.AuthenticationService.Login:input_type -> v1.manager.authentication.LoginRequest
-	2, // 2: v1.manager.authentication.AuthenticationService.Logout:input_type -> v1.manager.authentication.LogoutRequest
-	1, // 3: v1.manager.authentication.AuthenticationService.Login:output_type -> v1.manager.authentication.LoginResponse
-	3, // 4: v1.manager.authentication.AuthenticationService.Logout:output_type -> v1.manager.authentication.LogoutResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	14, // 0: v1.manager.authentication.LoginRequest.verifier:type_name -> v1.manager.Verifier
+	0,  // 1: v1.manager.authentication.AuthenticationService.Login:input_type -> v1.manager.authentication.LoginRequest
+	2,  // 2: v1.manager.authentication.AuthenticationService.Logout:input_type -> v1.manager.authentication.LogoutRequest
+	4,  // 3: v1.manager.authentication.AuthenticationService.BeginPasskeyLogin:input_type -> v1.manager.authentication.BeginPasskeyLoginRequest
+	6,  // 4: v1.manager.authentication.AuthenticationService.FinishPasskeyLogin:input_type -> v1.manager.authentication.FinishPasskeyLoginRequest
+	8,  // 5: v1.manager.authentication.AuthenticationService.VerifyTotp:input_type -> v1.manager.authentication.VerifyTotpRequest
+	10, // 6: v1.manager.authentication.AuthenticationService.BeginOidcLogin:input_type -> v1.manager.authentication.BeginOidcLoginRequest
+	12, // 7: v1.manager.authentication.AuthenticationService.FinishOidcLogin:input_type -> v1.manager.authentication.FinishOidcLoginRequest
+	1,  // 8: v1.manager.authentication.AuthenticationService.Login:output_type -> v1.manager.authentication.LoginResponse
+	3,  // 9: v1.manager.authentication.AuthenticationService.Logout:output_type -> v1.manager.authentication.LogoutResponse
+	5,  // 10: v1.manager.authentication.AuthenticationService.BeginPasskeyLogin:output_type -> v1.manager.authentication.BeginPasskeyLoginResponse
+	7,  // 11: v1.manager.authentication.AuthenticationService.FinishPasskeyLogin:output_type -> v1.manager.authentication.FinishPasskeyLoginResponse
+	9,  // 12: v1.manager.authentication.AuthenticationService.VerifyTotp:output_type -> v1.manager.authentication.VerifyTotpResponse
+	11, // 13: v1.manager.authentication.AuthenticationService.BeginOidcLogin:output_type -> v1.manager.authentication.BeginOidcLoginResponse
+	13, // 14: v1.manager.authentication.AuthenticationService.FinishOidcLogin:output_type -> v1.manager.authentication.FinishOidcLoginResponse
+	8,  // [8:15] is the sub-list for method output_type
+	1,  // [1:8] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_v1_manager_authentication_authentication_proto_init() }
@@ -250,7 +825,7 @@ func file_v1_manager_authentication_authentication_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1_manager_authentication_authentication_proto_rawDesc), len(file_v1_manager_authentication_authentication_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -39,6 +39,21 @@ const (
 	// AuthenticationServiceLogoutProcedure is the fully-qualified name of the AuthenticationService's
 	// Logout RPC.
 	AuthenticationServiceLogoutProcedure = "/v1.manager.authentication.AuthenticationService/Logout"
+	// AuthenticationServiceBeginPasskeyLoginProcedure is the fully-qualified name of the
+	// AuthenticationService's BeginPasskeyLogin RPC.
+	AuthenticationServiceBeginPasskeyLoginProcedure = "/v1.manager.authentication.AuthenticationService/BeginPasskeyLogin"
+	// AuthenticationServiceFinishPasskeyLoginProcedure is the fully-qualified name of the
+	// AuthenticationService's FinishPasskeyLogin RPC.
+	AuthenticationServiceFinishPasskeyLoginProcedure = "/v1.manager.authentication.AuthenticationService/FinishPasskeyLogin"
+	// AuthenticationServiceVerifyTotpProcedure is the fully-qualified name of the
+	// AuthenticationService's VerifyTotp RPC.
+	AuthenticationServiceVerifyTotpProcedure = "/v1.manager.authentication.AuthenticationService/VerifyTotp"
+	// AuthenticationServiceBeginOidcLoginProcedure is the fully-qualified name of the
+	// AuthenticationService's BeginOidcLogin RPC.
+	AuthenticationServiceBeginOidcLoginProcedure = "/v1.manager.authentication.AuthenticationService/BeginOidcLogin"
+	// AuthenticationServiceFinishOidcLoginProcedure is the fully-qualified name of the
+	// AuthenticationService's FinishOidcLogin RPC.
+	AuthenticationServiceFinishOidcLoginProcedure = "/v1.manager.authentication.AuthenticationService/FinishOidcLogin"
 )
 
 // AuthenticationServiceClient is a client for the v1.manager.authentication.AuthenticationService
@@ -46,6 +61,11 @@ const (
 type AuthenticationServiceClient interface {
 	Login(context.Context, *connect.Request[authentication.LoginRequest]) (*connect.Response[authentication.LoginResponse], error)
 	Logout(context.Context, *connect.Request[authentication.LogoutRequest]) (*connect.Response[authentication.LogoutResponse], error)
+	BeginPasskeyLogin(context.Context, *connect.Request[authentication.BeginPasskeyLoginRequest]) (*connect.Response[authentication.BeginPasskeyLoginResponse], error)
+	FinishPasskeyLogin(context.Context, *connect.Request[authentication.FinishPasskeyLoginRequest]) (*connect.Response[authentication.FinishPasskeyLoginResponse], error)
+	VerifyTotp(context.Context, *connect.Request[authentication.VerifyTotpRequest]) (*connect.Response[authentication.VerifyTotpResponse], error)
+	BeginOidcLogin(context.Context, *connect.Request[authentication.BeginOidcLoginRequest]) (*connect.Response[authentication.BeginOidcLoginResponse], error)
+	FinishOidcLogin(context.Context, *connect.Request[authentication.FinishOidcLoginRequest]) (*connect.Response[authentication.FinishOidcLoginResponse], error)
 }
 
 // NewAuthenticationServiceClient constructs a client for the
@@ -72,13 +92,48 @@ func NewAuthenticationServiceClient(httpClient connect.HTTPClient, baseURL strin
 			connect.WithSchema(authenticationServiceMethods.ByName("Logout")),
 			connect.WithClientOptions(opts...),
 		),
+		beginPasskeyLogin: connect.NewClient[authentication.BeginPasskeyLoginRequest, authentication.BeginPasskeyLoginResponse](
+			httpClient,
+			baseURL+AuthenticationServiceBeginPasskeyLoginProcedure,
+			connect.WithSchema(authenticationServiceMethods.ByName("BeginPasskeyLogin")),
+			connect.WithClientOptions(opts...),
+		),
+		finishPasskeyLogin: connect.NewClient[authentication.FinishPasskeyLoginRequest, authentication.FinishPasskeyLoginResponse](
+			httpClient,
+			baseURL+AuthenticationServiceFinishPasskeyLoginProcedure,
+			connect.WithSchema(authenticationServiceMethods.ByName("FinishPasskeyLogin")),
+			connect.WithClientOptions(opts...),
+		),
+		verifyTotp: connect.NewClient[authentication.VerifyTotpRequest, authentication.VerifyTotpResponse](
+			httpClient,
+			baseURL+AuthenticationServiceVerifyTotpProcedure,
+			connect.WithSchema(authenticationServiceMethods.ByName("VerifyTotp")),
+			connect.WithClientOptions(opts...),
+		),
+		beginOidcLogin: connect.NewClient[authentication.BeginOidcLoginRequest, authentication.BeginOidcLoginResponse](
+			httpClient,
+			baseURL+AuthenticationServiceBeginOidcLoginProcedure,
+			connect.WithSchema(authenticationServiceMethods.ByName("BeginOidcLogin")),
+			connect.WithClientOptions(opts...),
+		),
+		finishOidcLogin: connect.NewClient[authentication.FinishOidcLoginRequest, authentication.FinishOidcLoginResponse](
+			httpClient,
+			baseURL+AuthenticationServiceFinishOidcLoginProcedure,
+			connect.WithSchema(authenticationServiceMethods.ByName("FinishOidcLogin")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // authenticationServiceClient implements AuthenticationServiceClient.
 type authenticationServiceClient struct {
-	login  *connect.Client[authentication.LoginRequest, authentication.LoginResponse]
-	logout *connect.Client[authentication.LogoutRequest, authentication.LogoutResponse]
+	login              *connect.Client[authentication.LoginRequest, authentication.LoginResponse]
+	logout             *connect.Client[authentication.LogoutRequest, authentication.LogoutResponse]
+	beginPasskeyLogin  *connect.Client[authentication.BeginPasskeyLoginRequest, authentication.BeginPasskeyLoginResponse]
+	finishPasskeyLogin *connect.Client[authentication.FinishPasskeyLoginRequest, authentication.FinishPasskeyLoginResponse]
+	verifyTotp         *connect.Client[authentication.VerifyTotpRequest, authentication.VerifyTotpResponse]
+	beginOidcLogin     *connect.Client[authentication.BeginOidcLoginRequest, authentication.BeginOidcLoginResponse]
+	finishOidcLogin    *connect.Client[authentication.FinishOidcLoginRequest, authentication.FinishOidcLoginResponse]
 }
 
 // Login calls v1.manager.authentication.AuthenticationService.Login.
@@ -91,11 +146,41 @@ func (c *authenticationServiceClient) Logout(ctx context.Context, req *connect.R
 	return c.logout.CallUnary(ctx, req)
 }
 
+// BeginPasskeyLogin calls v1.manager.authentication.AuthenticationService.BeginPasskeyLogin.
+func (c *authenticationServiceClient) BeginPasskeyLogin(ctx context.Context, req *connect.Request[authentication.BeginPasskeyLoginRequest]) (*connect.Response[authentication.BeginPasskeyLoginResponse], error) {
+	return c.beginPasskeyLogin.CallUnary(ctx, req)
+}
+
+// FinishPasskeyLogin calls v1.manager.authentication.AuthenticationService.FinishPasskeyLogin.
+func (c *authenticationServiceClient) FinishPasskeyLogin(ctx context.Context, req *connect.Request[authentication.FinishPasskeyLoginRequest]) (*connect.Response[authentication.FinishPasskeyLoginResponse], error) {
+	return c.finishPasskeyLogin.CallUnary(ctx, req)
+}
+
+// VerifyTotp calls v1.manager.authentication.AuthenticationService.VerifyTotp.
+func (c *authenticationServiceClient) VerifyTotp(ctx context.Context, req *connect.Request[authentication.VerifyTotpRequest]) (*connect.Response[authentication.VerifyTotpResponse], error) {
+	return c.verifyTotp.CallUnary(ctx, req)
+}
+
+// BeginOidcLogin calls v1.manager.authentication.AuthenticationService.BeginOidcLogin.
+func (c *authenticationServiceClient) BeginOidcLogin(ctx context.Context, req *connect.Request[authentication.BeginOidcLoginRequest]) (*connect.Response[authentication.BeginOidcLoginResponse], error) {
+	return c.beginOidcLogin.CallUnary(ctx, req)
+}
+
+// FinishOidcLogin calls v1.manager.authentication.AuthenticationService.FinishOidcLogin.
+func (c *authenticationServiceClient) FinishOidcLogin(ctx context.Context, req *connect.Request[authentication.FinishOidcLoginRequest]) (*connect.Response[authentication.FinishOidcLoginResponse], error) {
+	return c.finishOidcLogin.CallUnary(ctx, req)
+}
+
 // AuthenticationServiceHandler is an implementation of the
 // v1.manager.authentication.AuthenticationService service.
 type AuthenticationServiceHandler interface {
 	Login(context.Context, *connect.Request[authentication.LoginRequest]) (*connect.Response[authentication.LoginResponse], error)
 	Logout(context.Context, *connect.Request[authentication.LogoutRequest]) (*connect.Response[authentication.LogoutResponse], error)
+	BeginPasskeyLogin(context.Context, *connect.Request[authentication.BeginPasskeyLoginRequest]) (*connect.Response[authentication.BeginPasskeyLoginResponse], error)
+	FinishPasskeyLogin(context.Context, *connect.Request[authentication.FinishPasskeyLoginRequest]) (*connect.Response[authentication.FinishPasskeyLoginResponse], error)
+	VerifyTotp(context.Context, *connect.Request[authentication.VerifyTotpRequest]) (*connect.Response[authentication.VerifyTotpResponse], error)
+	BeginOidcLogin(context.Context, *connect.Request[authentication.BeginOidcLoginRequest]) (*connect.Response[authentication.BeginOidcLoginResponse], error)
+	FinishOidcLogin(context.Context, *connect.Request[authentication.FinishOidcLoginRequest]) (*connect.Response[authentication.FinishOidcLoginResponse], error)
 }
 
 // NewAuthenticationServiceHandler builds an HTTP handler from the service implementation. It
@@ -117,12 +202,52 @@ func NewAuthenticationServiceHandler(svc AuthenticationServiceHandler, opts ...c
 		connect.WithSchema(authenticationServiceMethods.ByName("Logout")),
 		connect.WithHandlerOptions(opts...),
 	)
+	authenticationServiceBeginPasskeyLoginHandler := connect.NewUnaryHandler(
+		AuthenticationServiceBeginPasskeyLoginProcedure,
+		svc.BeginPasskeyLogin,
+		connect.WithSchema(authenticationServiceMethods.ByName("BeginPasskeyLogin")),
+		connect.WithHandlerOptions(opts...),
+	)
+	authenticationServiceFinishPasskeyLoginHandler := connect.NewUnaryHandler(
+		AuthenticationServiceFinishPasskeyLoginProcedure,
+		svc.FinishPasskeyLogin,
+		connect.WithSchema(authenticationServiceMethods.ByName("FinishPasskeyLogin")),
+		connect.WithHandlerOptions(opts...),
+	)
+	authenticationServiceVerifyTotpHandler := connect.NewUnaryHandler(
+		AuthenticationServiceVerifyTotpProcedure,
+		svc.VerifyTotp,
+		connect.WithSchema(authenticationServiceMethods.ByName("VerifyTotp")),
+		connect.WithHandlerOptions(opts...),
+	)
+	authenticationServiceBeginOidcLoginHandler := connect.NewUnaryHandler(
+		AuthenticationServiceBeginOidcLoginProcedure,
+		svc.BeginOidcLogin,
+		connect.WithSchema(authenticationServiceMethods.ByName("BeginOidcLogin")),
+		connect.WithHandlerOptions(opts...),
+	)
+	authenticationServiceFinishOidcLoginHandler := connect.NewUnaryHandler(
+		AuthenticationServiceFinishOidcLoginProcedure,
+		svc.FinishOidcLogin,
+		connect.WithSchema(authenticationServiceMethods.ByName("FinishOidcLogin")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/v1.manager.authentication.AuthenticationService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case AuthenticationServiceLoginProcedure:
 			authenticationServiceLoginHandler.ServeHTTP(w, r)
 		case AuthenticationServiceLogoutProcedure:
 			authenticationServiceLogoutHandler.ServeHTTP(w, r)
+		case AuthenticationServiceBeginPasskeyLoginProcedure:
+			authenticationServiceBeginPasskeyLoginHandler.ServeHTTP(w, r)
+		case AuthenticationServiceFinishPasskeyLoginProcedure:
+			authenticationServiceFinishPasskeyLoginHandler.ServeHTTP(w, r)
+		case AuthenticationServiceVerifyTotpProcedure:
+			authenticationServiceVerifyTotpHandler.ServeHTTP(w, r)
+		case AuthenticationServiceBeginOidcLoginProcedure:
+			authenticationServiceBeginOidcLoginHandler.ServeHTTP(w, r)
+		case AuthenticationServiceFinishOidcLoginProcedure:
+			authenticationServiceFinishOidcLoginHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -139,3 +264,23 @@ func (UnimplementedAuthenticationServiceHandler) Login(context.Context, *connect
 func (UnimplementedAuthenticationServiceHandler) Logout(context.Context, *connect.Request[authentication.LogoutRequest]) (*connect.Response[authentication.LogoutResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.authentication.AuthenticationService.Logout is not implemented"))
 }
+
+func (UnimplementedAuthenticationServiceHandler) BeginPasskeyLogin(context.Context, *connect.Request[authentication.BeginPasskeyLoginRequest]) (*connect.Response[authentication.BeginPasskeyLoginResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.authentication.AuthenticationService.BeginPasskeyLogin is not implemented"))
+}
+
+func (UnimplementedAuthenticationServiceHandler) FinishPasskeyLogin(context.Context, *connect.Request[authentication.FinishPasskeyLoginRequest]) (*connect.Response[authentication.FinishPasskeyLoginResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.authentication.AuthenticationService.FinishPasskeyLogin is not implemented"))
+}
+
+func (UnimplementedAuthenticationServiceHandler) VerifyTotp(context.Context, *connect.Request[authentication.VerifyTotpRequest]) (*connect.Response[authentication.VerifyTotpResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.authentication.AuthenticationService.VerifyTotp is not implemented"))
+}
+
+func (UnimplementedAuthenticationServiceHandler) BeginOidcLogin(context.Context, *connect.Request[authentication.BeginOidcLoginRequest]) (*connect.Response[authentication.BeginOidcLoginResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.authentication.AuthenticationService.BeginOidcLogin is not implemented"))
+}
+
+func (UnimplementedAuthenticationServiceHandler) FinishOidcLogin(context.Context, *connect.Request[authentication.FinishOidcLoginRequest]) (*connect.Response[authentication.FinishOidcLoginResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.authentication.AuthenticationService.FinishOidcLogin is not implemented"))
+}
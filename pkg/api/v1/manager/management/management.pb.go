@@ -303,27 +303,1165 @@ func (*UpdateResponse) Descriptor() ([]byte, []int) {
 }
 
 type DeleteRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Verifier *manager.Verifier      `protobuf:"bytes,1,opt,name=verifier,proto3" json:"verifier,omitempty"`
+	// bool delete_history = 2;
+	// totp code or one of the recovery codes, required if the user has totp enabled.
+	TotpCode      string `protobuf:"bytes,3,opt,name=totp_code,json=totpCode,proto3" json:"totp_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteRequest) GetVerifier() *manager.Verifier {
+	if x != nil {
+		return x.Verifier
+	}
+	return nil
+}
+
+func (x *DeleteRequest) GetTotpCode() string {
+	if x != nil {
+		return x.TotpCode
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{7}
+}
+
+type ChangeEmailRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// verifier of the new email.
+	Verifier *manager.Verifier `protobuf:"bytes,1,opt,name=verifier,proto3" json:"verifier,omitempty"`
+	// totp code or one of the recovery codes, required if the user has totp enabled.
+	TotpCode string `protobuf:"bytes,2,opt,name=totp_code,json=totpCode,proto3" json:"totp_code,omitempty"`
+	// verifier of the current email, must be in the same stage as the verifier of the new email.
+	CurrentVerifier *manager.Verifier `protobuf:"bytes,3,opt,name=current_verifier,json=currentVerifier,proto3" json:"current_verifier,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeEmailRequest) Reset() {
+	*x = ChangeEmailRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmailRequest) ProtoMessage() {}
+
+func (x *ChangeEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmailRequest.ProtoReflect.Descriptor instead.
+func (*ChangeEmailRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChangeEmailRequest) GetVerifier() *manager.Verifier {
+	if x != nil {
+		return x.Verifier
+	}
+	return nil
+}
+
+func (x *ChangeEmailRequest) GetTotpCode() string {
+	if x != nil {
+		return x.TotpCode
+	}
+	return ""
+}
+
+func (x *ChangeEmailRequest) GetCurrentVerifier() *manager.Verifier {
+	if x != nil {
+		return x.CurrentVerifier
+	}
+	return nil
+}
+
+type ChangeEmailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEmailResponse) Reset() {
+	*x = ChangeEmailResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmailResponse) ProtoMessage() {}
+
+func (x *ChangeEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmailResponse.ProtoReflect.Descriptor instead.
+func (*ChangeEmailResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{9}
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{10}
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*manager.Session     `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*manager.Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type RevokeSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// revokes all sessions of the user (including the current one), id is ignored.
+	All           bool `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionRequest) ProtoMessage() {}
+
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RevokeSessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevokeSessionRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type RevokeSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionResponse) Reset() {
+	*x = RevokeSessionResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionResponse) ProtoMessage() {}
+
+func (x *RevokeSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{13}
+}
+
+type ExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRequest) ProtoMessage() {}
+
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{14}
+}
+
+type ExportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// zip archive containing the personal data (profile, events and leaderboard entries) as json and csv.
+	Archive       []byte `protobuf:"bytes,1,opt,name=archive,proto3" json:"archive,omitempty"`
+	Filename      string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportResponse) Reset() {
+	*x = ExportResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportResponse) ProtoMessage() {}
+
+func (x *ExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportResponse.ProtoReflect.Descriptor instead.
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportResponse) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+func (x *ExportResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+type ListAuditEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// subject of the investigated user, defaults to the caller (other users require support permissions).
+	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	// time range in unix seconds (until defaults to now), the latest 100 events of the range are returned (newest first).
+	Since         int64 `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"`
+	Until         int64 `protobuf:"varint,3,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListAuditEventsRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+type ListAuditEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*manager.AuditEvent  `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListAuditEventsResponse) GetEvents() []*manager.AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type BeginPasskeyRegistrationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyRegistrationRequest) Reset() {
+	*x = BeginPasskeyRegistrationRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyRegistrationRequest) ProtoMessage() {}
+
+func (x *BeginPasskeyRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{18}
+}
+
+type BeginPasskeyRegistrationResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CeremonyId string                 `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	// json encoded credential creation options for navigator.credentials.create().
+	Options       string `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyRegistrationResponse) Reset() {
+	*x = BeginPasskeyRegistrationResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyRegistrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyRegistrationResponse) ProtoMessage() {}
+
+func (x *BeginPasskeyRegistrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyRegistrationResponse.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyRegistrationResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BeginPasskeyRegistrationResponse) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+func (x *BeginPasskeyRegistrationResponse) GetOptions() string {
+	if x != nil {
+		return x.Options
+	}
+	return ""
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CeremonyId string                 `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// json encoded public key credential returned by navigator.credentials.create().
+	Credential    string `protobuf:"bytes,3,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyRegistrationRequest) Reset() {
+	*x = FinishPasskeyRegistrationRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyRegistrationRequest) ProtoMessage() {}
+
+func (x *FinishPasskeyRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+type FinishPasskeyRegistrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passkey       *manager.Passkey       `protobuf:"bytes,1,opt,name=passkey,proto3" json:"passkey,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyRegistrationResponse) Reset() {
+	*x = FinishPasskeyRegistrationResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyRegistrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyRegistrationResponse) ProtoMessage() {}
+
+func (x *FinishPasskeyRegistrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyRegistrationResponse.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyRegistrationResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FinishPasskeyRegistrationResponse) GetPasskey() *manager.Passkey {
+	if x != nil {
+		return x.Passkey
+	}
+	return nil
+}
+
+type ListPasskeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPasskeysRequest) Reset() {
+	*x = ListPasskeysRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPasskeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPasskeysRequest) ProtoMessage() {}
+
+func (x *ListPasskeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPasskeysRequest.ProtoReflect.Descriptor instead.
+func (*ListPasskeysRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{22}
+}
+
+type ListPasskeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passkeys      []*manager.Passkey     `protobuf:"bytes,1,rep,name=passkeys,proto3" json:"passkeys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPasskeysResponse) Reset() {
+	*x = ListPasskeysResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPasskeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPasskeysResponse) ProtoMessage() {}
+
+func (x *ListPasskeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPasskeysResponse.ProtoReflect.Descriptor instead.
+func (*ListPasskeysResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListPasskeysResponse) GetPasskeys() []*manager.Passkey {
+	if x != nil {
+		return x.Passkeys
+	}
+	return nil
+}
+
+type DeletePasskeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePasskeyRequest) Reset() {
+	*x = DeletePasskeyRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePasskeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePasskeyRequest) ProtoMessage() {}
+
+func (x *DeletePasskeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePasskeyRequest.ProtoReflect.Descriptor instead.
+func (*DeletePasskeyRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeletePasskeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeletePasskeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePasskeyResponse) Reset() {
+	*x = DeletePasskeyResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePasskeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePasskeyResponse) ProtoMessage() {}
+
+func (x *DeletePasskeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePasskeyResponse.ProtoReflect.Descriptor instead.
+func (*DeletePasskeyResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{25}
+}
+
+type EnrollTotpRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTotpRequest) Reset() {
+	*x = EnrollTotpRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTotpRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTotpRequest) ProtoMessage() {}
+
+func (x *EnrollTotpRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTotpRequest.ProtoReflect.Descriptor instead.
+func (*EnrollTotpRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{26}
+}
+
+type EnrollTotpResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// otpauth:// uri for authenticator apps.
+	Uri    string `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
+	Secret string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	// png encoded qr code of the uri.
+	Qr            []byte `protobuf:"bytes,3,opt,name=qr,proto3" json:"qr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTotpResponse) Reset() {
+	*x = EnrollTotpResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTotpResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTotpResponse) ProtoMessage() {}
+
+func (x *EnrollTotpResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTotpResponse.ProtoReflect.Descriptor instead.
+func (*EnrollTotpResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *EnrollTotpResponse) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *EnrollTotpResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *EnrollTotpResponse) GetQr() []byte {
+	if x != nil {
+		return x.Qr
+	}
+	return nil
+}
+
+type ConfirmTotpRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTotpRequest) Reset() {
+	*x = ConfirmTotpRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTotpRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTotpRequest) ProtoMessage() {}
+
+func (x *ConfirmTotpRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTotpRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmTotpRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ConfirmTotpRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmTotpResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// single use recovery codes, they are only returned once.
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTotpResponse) Reset() {
+	*x = ConfirmTotpResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTotpResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTotpResponse) ProtoMessage() {}
+
+func (x *ConfirmTotpResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTotpResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmTotpResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ConfirmTotpResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+type DisableTotpRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// totp code or one of the recovery codes.
+	Code          string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableTotpRequest) Reset() {
+	*x = DisableTotpRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTotpRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTotpRequest) ProtoMessage() {}
+
+func (x *DisableTotpRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTotpRequest.ProtoReflect.Descriptor instead.
+func (*DisableTotpRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DisableTotpRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DisableTotpResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Verifier      *manager.Verifier      `protobuf:"bytes,1,opt,name=verifier,proto3" json:"verifier,omitempty"` // bool delete_history = 2;
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
-	mi := &file_v1_manager_management_management_proto_msgTypes[6]
+func (x *DisableTotpResponse) Reset() {
+	*x = DisableTotpResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRequest) String() string {
+func (x *DisableTotpResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*DisableTotpResponse) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_manager_management_management_proto_msgTypes[6]
+func (x *DisableTotpResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,39 +1472,96 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use DisableTotpResponse.ProtoReflect.Descriptor instead.
+func (*DisableTotpResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *DeleteRequest) GetVerifier() *manager.Verifier {
+type CreatePersonalTokenRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// lifetime of the token in days (defaults to 90, at most 365).
+	ExpiresInDays int64 `protobuf:"varint,3,opt,name=expires_in_days,json=expiresInDays,proto3" json:"expires_in_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePersonalTokenRequest) Reset() {
+	*x = CreatePersonalTokenRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePersonalTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePersonalTokenRequest) ProtoMessage() {}
+
+func (x *CreatePersonalTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[32]
 	if x != nil {
-		return x.Verifier
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePersonalTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreatePersonalTokenRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CreatePersonalTokenRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreatePersonalTokenRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
 	}
 	return nil
 }
 
-type DeleteResponse struct {
+func (x *CreatePersonalTokenRequest) GetExpiresInDays() int64 {
+	if x != nil {
+		return x.ExpiresInDays
+	}
+	return 0
+}
+
+type CreatePersonalTokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonalToken *manager.PersonalToken `protobuf:"bytes,1,opt,name=personal_token,json=personalToken,proto3" json:"personal_token,omitempty"`
+	// the signed token, it is only returned once.
+	Token         string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
-	mi := &file_v1_manager_management_management_proto_msgTypes[7]
+func (x *CreatePersonalTokenResponse) Reset() {
+	*x = CreatePersonalTokenResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteResponse) String() string {
+func (x *CreatePersonalTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (*CreatePersonalTokenResponse) ProtoMessage() {}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_manager_management_management_proto_msgTypes[7]
+func (x *CreatePersonalTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -377,16 +1572,190 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use CreatePersonalTokenResponse.ProtoReflect.Descriptor instead.
+func (*CreatePersonalTokenResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CreatePersonalTokenResponse) GetPersonalToken() *manager.PersonalToken {
+	if x != nil {
+		return x.PersonalToken
+	}
+	return nil
+}
+
+func (x *CreatePersonalTokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ListPersonalTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPersonalTokensRequest) Reset() {
+	*x = ListPersonalTokensRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPersonalTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPersonalTokensRequest) ProtoMessage() {}
+
+func (x *ListPersonalTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPersonalTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListPersonalTokensRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{34}
+}
+
+type ListPersonalTokensResponse struct {
+	state          protoimpl.MessageState   `protogen:"open.v1"`
+	PersonalTokens []*manager.PersonalToken `protobuf:"bytes,1,rep,name=personal_tokens,json=personalTokens,proto3" json:"personal_tokens,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListPersonalTokensResponse) Reset() {
+	*x = ListPersonalTokensResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPersonalTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPersonalTokensResponse) ProtoMessage() {}
+
+func (x *ListPersonalTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPersonalTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListPersonalTokensResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListPersonalTokensResponse) GetPersonalTokens() []*manager.PersonalToken {
+	if x != nil {
+		return x.PersonalTokens
+	}
+	return nil
+}
+
+type RevokePersonalTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokePersonalTokenRequest) Reset() {
+	*x = RevokePersonalTokenRequest{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokePersonalTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokePersonalTokenRequest) ProtoMessage() {}
+
+func (x *RevokePersonalTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokePersonalTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokePersonalTokenRequest) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RevokePersonalTokenRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokePersonalTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokePersonalTokenResponse) Reset() {
+	*x = RevokePersonalTokenResponse{}
+	mi := &file_v1_manager_management_management_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokePersonalTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokePersonalTokenResponse) ProtoMessage() {}
+
+func (x *RevokePersonalTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_manager_management_management_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokePersonalTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokePersonalTokenResponse) Descriptor() ([]byte, []int) {
+	return file_v1_manager_management_management_proto_rawDescGZIP(), []int{37}
 }
 
 var File_v1_manager_management_management_proto protoreflect.FileDescriptor
 
 const file_v1_manager_management_management_proto_rawDesc = "" +
 	"\n" +
-	"&v1/manager/management/management.proto\x12\x15v1.manager.management\x1a\x15v1/manager/user.proto\x1a\x19v1/manager/verifier.proto\"\xaf\x01\n" +
+	"&v1/manager/management/management.proto\x12\x15v1.manager.management\x1a\x16v1/manager/audit.proto\x1a\x18v1/manager/passkey.proto\x1a\x1fv1/manager/personal_token.proto\x1a\x18v1/manager/session.proto\x1a\x15v1/manager/user.proto\x1a\x19v1/manager/verifier.proto\"\xaf\x01\n" +
 	"\x0fRegisterRequest\x12$\n" +
 	"\x04user\x18\x01 \x01(\v2\x10.v1.manager.UserR\x04user\x12\x1d\n" +
 	"\n" +
@@ -403,15 +1772,99 @@ const file_v1_manager_management_management_proto_rawDesc = "" +
 	"\x04user\x18\x01 \x01(\v2\x10.v1.manager.UserR\x04user\"5\n" +
 	"\rUpdateRequest\x12$\n" +
 	"\x04user\x18\x01 \x01(\v2\x10.v1.manager.UserR\x04user\"\x10\n" +
-	"\x0eUpdateResponse\"A\n" +
+	"\x0eUpdateResponse\"^\n" +
 	"\rDeleteRequest\x120\n" +
-	"\bverifier\x18\x01 \x01(\v2\x14.v1.manager.VerifierR\bverifier\"\x10\n" +
-	"\x0eDeleteResponse2\xf4\x02\n" +
+	"\bverifier\x18\x01 \x01(\v2\x14.v1.manager.VerifierR\bverifier\x12\x1b\n" +
+	"\ttotp_code\x18\x03 \x01(\tR\btotpCode\"\x10\n" +
+	"\x0eDeleteResponse\"\xa4\x01\n" +
+	"\x12ChangeEmailRequest\x120\n" +
+	"\bverifier\x18\x01 \x01(\v2\x14.v1.manager.VerifierR\bverifier\x12\x1b\n" +
+	"\ttotp_code\x18\x02 \x01(\tR\btotpCode\x12?\n" +
+	"\x10current_verifier\x18\x03 \x01(\v2\x14.v1.manager.VerifierR\x0fcurrentVerifier\"\x15\n" +
+	"\x13ChangeEmailResponse\"\x15\n" +
+	"\x13ListSessionsRequest\"G\n" +
+	"\x14ListSessionsResponse\x12/\n" +
+	"\bsessions\x18\x01 \x03(\v2\x13.v1.manager.SessionR\bsessions\"8\n" +
+	"\x14RevokeSessionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\"\x17\n" +
+	"\x15RevokeSessionResponse\"\x0f\n" +
+	"\rExportRequest\"F\n" +
+	"\x0eExportResponse\x12\x18\n" +
+	"\aarchive\x18\x01 \x01(\fR\aarchive\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\"^\n" +
+	"\x16ListAuditEventsRequest\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12\x14\n" +
+	"\x05since\x18\x02 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05until\x18\x03 \x01(\x03R\x05until\"I\n" +
+	"\x17ListAuditEventsResponse\x12.\n" +
+	"\x06events\x18\x01 \x03(\v2\x16.v1.manager.AuditEventR\x06events\"!\n" +
+	"\x1fBeginPasskeyRegistrationRequest\"]\n" +
+	" BeginPasskeyRegistrationResponse\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\x12\x18\n" +
+	"\aoptions\x18\x02 \x01(\tR\aoptions\"w\n" +
+	" FinishPasskeyRegistrationRequest\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x03 \x01(\tR\n" +
+	"credential\"R\n" +
+	"!FinishPasskeyRegistrationResponse\x12-\n" +
+	"\apasskey\x18\x01 \x01(\v2\x13.v1.manager.PasskeyR\apasskey\"\x15\n" +
+	"\x13ListPasskeysRequest\"G\n" +
+	"\x14ListPasskeysResponse\x12/\n" +
+	"\bpasskeys\x18\x01 \x03(\v2\x13.v1.manager.PasskeyR\bpasskeys\"&\n" +
+	"\x14DeletePasskeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x17\n" +
+	"\x15DeletePasskeyResponse\"\x13\n" +
+	"\x11EnrollTotpRequest\"N\n" +
+	"\x12EnrollTotpResponse\x12\x10\n" +
+	"\x03uri\x18\x01 \x01(\tR\x03uri\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\x12\x0e\n" +
+	"\x02qr\x18\x03 \x01(\fR\x02qr\"(\n" +
+	"\x12ConfirmTotpRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"<\n" +
+	"\x13ConfirmTotpResponse\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"(\n" +
+	"\x12DisableTotpRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x15\n" +
+	"\x13DisableTotpResponse\"p\n" +
+	"\x1aCreatePersonalTokenRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\x12&\n" +
+	"\x0fexpires_in_days\x18\x03 \x01(\x03R\rexpiresInDays\"u\n" +
+	"\x1bCreatePersonalTokenResponse\x12@\n" +
+	"\x0epersonal_token\x18\x01 \x01(\v2\x19.v1.manager.PersonalTokenR\rpersonalToken\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"\x1b\n" +
+	"\x19ListPersonalTokensRequest\"`\n" +
+	"\x1aListPersonalTokensResponse\x12B\n" +
+	"\x0fpersonal_tokens\x18\x01 \x03(\v2\x19.v1.manager.PersonalTokenR\x0epersonalTokens\",\n" +
+	"\x1aRevokePersonalTokenRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1d\n" +
+	"\x1bRevokePersonalTokenResponse2\xb0\x10\n" +
 	"\x11ManagementService\x12]\n" +
 	"\bRegister\x12&.v1.manager.management.RegisterRequest\x1a'.v1.manager.management.RegisterResponse\"\x00\x12N\n" +
 	"\x03Get\x12!.v1.manager.management.GetRequest\x1a\".v1.manager.management.GetResponse\"\x00\x12W\n" +
 	"\x06Update\x12$.v1.manager.management.UpdateRequest\x1a%.v1.manager.management.UpdateResponse\"\x00\x12W\n" +
-	"\x06Delete\x12$.v1.manager.management.DeleteRequest\x1a%.v1.manager.management.DeleteResponse\"\x00B7Z5github.com/megakuul/zen/pkg/api/v1/manager/managementb\x06proto3"
+	"\x06Delete\x12$.v1.manager.management.DeleteRequest\x1a%.v1.manager.management.DeleteResponse\"\x00\x12f\n" +
+	"\vChangeEmail\x12).v1.manager.management.ChangeEmailRequest\x1a*.v1.manager.management.ChangeEmailResponse\"\x00\x12W\n" +
+	"\x06Export\x12$.v1.manager.management.ExportRequest\x1a%.v1.manager.management.ExportResponse\"\x00\x12i\n" +
+	"\fListSessions\x12*.v1.manager.management.ListSessionsRequest\x1a+.v1.manager.management.ListSessionsResponse\"\x00\x12l\n" +
+	"\rRevokeSession\x12+.v1.manager.management.RevokeSessionRequest\x1a,.v1.manager.management.RevokeSessionResponse\"\x00\x12r\n" +
+	"\x0fListAuditEvents\x12-.v1.manager.management.ListAuditEventsRequest\x1a..v1.manager.management.ListAuditEventsResponse\"\x00\x12\x8d\x01\n" +
+	"\x18BeginPasskeyRegistration\x126.v1.manager.management.BeginPasskeyRegistrationRequest\x1a7.v1.manager.management.BeginPasskeyRegistrationResponse\"\x00\x12\x90\x01\n" +
+	"\x19FinishPasskeyRegistration\x127.v1.manager.management.FinishPasskeyRegistrationRequest\x1a8.v1.manager.management.FinishPasskeyRegistrationResponse\"\x00\x12i\n" +
+	"\fListPasskeys\x12*.v1.manager.management.ListPasskeysRequest\x1a+.v1.manager.management.ListPasskeysResponse\"\x00\x12l\n" +
+	"\rDeletePasskey\x12+.v1.manager.management.DeletePasskeyRequest\x1a,.v1.manager.management.DeletePasskeyResponse\"\x00\x12c\n" +
+	"\n" +
+	"EnrollTotp\x12(.v1.manager.management.EnrollTotpRequest\x1a).v1.manager.management.EnrollTotpResponse\"\x00\x12f\n" +
+	"\vConfirmTotp\x12).v1.manager.management.ConfirmTotpRequest\x1a*.v1.manager.management.ConfirmTotpResponse\"\x00\x12f\n" +
+	"\vDisableTotp\x12).v1.manager.management.DisableTotpRequest\x1a*.v1.manager.management.DisableTotpResponse\"\x00\x12~\n" +
+	"\x13CreatePersonalToken\x121.v1.manager.management.CreatePersonalTokenRequest\x1a2.v1.manager.management.CreatePersonalTokenResponse\"\x00\x12{\n" +
+	"\x12ListPersonalTokens\x120.v1.manager.management.ListPersonalTokensRequest\x1a1.v1.manager.management.ListPersonalTokensResponse\"\x00\x12~\n" +
+	"\x13RevokePersonalToken\x121.v1.manager.management.RevokePersonalTokenRequest\x1a2.v1.manager.management.RevokePersonalTokenResponse\"\x00B7Z5github.com/megakuul/zen/pkg/api/v1/manager/managementb\x06proto3"
 
 var (
 	file_v1_manager_management_management_proto_rawDescOnce sync.Once
@@ -425,38 +1878,110 @@ func file_v1_manager_management_management_proto_rawDescGZIP() []byte {
 	return file_v1_manager_management_management_proto_rawDescData
 }
 
-var file_v1_manager_management_management_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_v1_manager_management_management_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_v1_manager_management_management_proto_goTypes = []any{
-	(*RegisterRequest)(nil),  // 0: v1.manager.management.RegisterRequest
-	(*RegisterResponse)(nil), // 1: v1.manager.management.RegisterResponse
-	(*GetRequest)(nil),       // 2: v1.manager.management.GetRequest
-	(*GetResponse)(nil),      // 3: v1.manager.management.GetResponse
-	(*UpdateRequest)(nil),    // 4: v1.manager.management.UpdateRequest
-	(*UpdateResponse)(nil),   // 5: v1.manager.management.UpdateResponse
-	(*DeleteRequest)(nil),    // 6: v1.manager.management.DeleteRequest
-	(*DeleteResponse)(nil),   // 7: v1.manager.management.DeleteResponse
-	(*manager.User)(nil),     // 8: v1.manager.User
-	(*manager.Verifier)(nil), // 9: v1.manager.Verifier
+	(*RegisterRequest)(nil),                   // 0: v1.manager.management.RegisterRequest
+	(*RegisterResponse)(nil),                  // 1: v1.manager.management.RegisterResponse
+	(*GetRequest)(nil),                        // 2: v1.manager.management.GetRequest
+	(*GetResponse)(nil),                       // 3: v1.manager.management.GetResponse
+	(*UpdateRequest)(nil),                     // 4: v1.manager.management.UpdateRequest
+	(*UpdateResponse)(nil),                    // 5: v1.manager.management.UpdateResponse
+	(*DeleteRequest)(nil),                     // 6: v1.manager.management.DeleteRequest
+	(*DeleteResponse)(nil),                    // 7: v1.manager.management.DeleteResponse
+	(*ChangeEmailRequest)(nil),                // 8: v1.manager.management.ChangeEmailRequest
+	(*ChangeEmailResponse)(nil),               // 9: v1.manager.management.ChangeEmailResponse
+	(*ListSessionsRequest)(nil),               // 10: v1.manager.management.ListSessionsRequest
+	(*ListSessionsResponse)(nil),              // 11: v1.manager.management.ListSessionsResponse
+	(*RevokeSessionRequest)(nil),              // 12: v1.manager.management.RevokeSessionRequest
+	(*RevokeSessionResponse)(nil),             // 13: v1.manager.management.RevokeSessionResponse
+	(*ExportRequest)(nil),                     // 14: v1.manager.management.ExportRequest
+	(*ExportResponse)(nil),                    // 15: v1.manager.management.ExportResponse
+	(*ListAuditEventsRequest)(nil),            // 16: v1.manager.management.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),           // 17: v1.manager.management.ListAuditEventsResponse
+	(*BeginPasskeyRegistrationRequest)(nil),   // 18: v1.manager.management.BeginPasskeyRegistrationRequest
+	(*BeginPasskeyRegistrationResponse)(nil),  // 19: v1.manager.management.BeginPasskeyRegistrationResponse
+	(*FinishPasskeyRegistrationRequest)(nil),  // 20: v1.manager.management.FinishPasskeyRegistrationRequest
+	(*FinishPasskeyRegistrationResponse)(nil), // 21: v1.manager.management.FinishPasskeyRegistrationResponse
+	(*ListPasskeysRequest)(nil),               // 22: v1.manager.management.ListPasskeysRequest
+	(*ListPasskeysResponse)(nil),              // 23: v1.manager.management.ListPasskeysResponse
+	(*DeletePasskeyRequest)(nil),              // 24: v1.manager.management.DeletePasskeyRequest
+	(*DeletePasskeyResponse)(nil),             // 25: v1.manager.management.DeletePasskeyResponse
+	(*EnrollTotpRequest)(nil),                 // 26: v1.manager.management.EnrollTotpRequest
+	(*EnrollTotpResponse)(nil),                // 27: v1.manager.management.EnrollTotpResponse
+	(*ConfirmTotpRequest)(nil),                // 28: v1.manager.management.ConfirmTotpRequest
+	(*ConfirmTotpResponse)(nil),               // 29: v1.manager.management.ConfirmTotpResponse
+	(*DisableTotpRequest)(nil),                // 30: v1.manager.management.DisableTotpRequest
+	(*DisableTotpResponse)(nil),               // 31: v1.manager.management.DisableTotpResponse
+	(*CreatePersonalTokenRequest)(nil),        // 32: v1.manager.management.CreatePersonalTokenRequest
+	(*CreatePersonalTokenResponse)(nil),       // 33: v1.manager.management.CreatePersonalTokenResponse
+	(*ListPersonalTokensRequest)(nil),         // 34: v1.manager.management.ListPersonalTokensRequest
+	(*ListPersonalTokensResponse)(nil),        // 35: v1.manager.management.ListPersonalTokensResponse
+	(*RevokePersonalTokenRequest)(nil),        // 36: v1.manager.management.RevokePersonalTokenRequest
+	(*RevokePersonalTokenResponse)(nil),       // 37: v1.manager.management.RevokePersonalTokenResponse
+	(*manager.User)(nil),                      // 38: v1.manager.User
+	(*manager.Verifier)(nil),                  // 39: v1.manager.Verifier
+	(*manager.Session)(nil),                   // 40: v1.manager.Session
+	(*manager.AuditEvent)(nil),                // 41: v1.manager.AuditEvent
+	(*manager.Passkey)(nil),                   // 42: v1.manager.Passkey
+	(*manager.PersonalToken)(nil),             // 43: v1.manager.PersonalToken
 }
 var file_v1_manager_management_management_proto_depIdxs = []int32{
-	8, // 0: v1.manager.management.RegisterRequest.user:type_name -> v1.manager.User
-	9, // 1: v1.manager.management.RegisterRequest.verifier:type_name -> v1.manager.Verifier
-	8, // 2: v1.manager.management.GetResponse.user:type_name -> v1.manager.User
-	8, // 3: v1.manager.management.UpdateRequest.user:type_name -> v1.manager.User
-	9, // 4: v1.manager.management.DeleteRequest.verifier:type_name -> v1.manager.Verifier
-	0, // 5: v1.manager.management.ManagementService.Register:input_type -> v1.manager.management.RegisterRequest
-	2, // 6: v1.manager.management.ManagementService.Get:input_type -> v1.manager.management.GetRequest
-	4, // 7: v1.manager.management.ManagementService.Update:input_type -> v1.manager.management.UpdateRequest
-	6, // 8: v1.manager.management.ManagementService.Delete:input_type -> v1.manager.management.DeleteRequest
-	1, // 9: v1.manager.management.ManagementService.Register:output_type -> v1.manager.management.RegisterResponse
-	3, // 10: v1.manager.management.ManagementService.Get:output_type -> v1.manager.management.GetResponse
-	5, // 11: v1.manager.management.ManagementService.Update:output_type -> v1.manager.management.UpdateResponse
-	7, // 12: v1.manager.management.ManagementService.Delete:output_type -> v1.manager.management.DeleteResponse
-	9, // [9:13] is the sub-list for method output_type
-	5, // [5:9] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	38, // 0: v1.manager.management.RegisterRequest.user:type_name -> v1.manager.User
+	39, // 1: v1.manager.management.RegisterRequest.verifier:type_name -> v1.manager.Verifier
+	38, // 2: v1.manager.management.GetResponse.user:type_name -> v1.manager.User
+	38, // 3: v1.manager.management.UpdateRequest.user:type_name -> v1.manager.User
+	39, // 4: v1.manager.management.DeleteRequest.verifier:type_name -> v1.manager.Verifier
+	39, // 5: v1.manager.management.ChangeEmailRequest.verifier:type_name -> v1.manager.Verifier
+	39, // 6: v1.manager.management.ChangeEmailRequest.current_verifier:type_name -> v1.manager.Verifier
+	40, // 7: v1.manager.management.ListSessionsResponse.sessions:type_name -> v1.manager.Session
+	41, // 8: v1.manager.management.ListAuditEventsResponse.events:type_name -> v1.manager.AuditEvent
+	42, // 9: v1.manager.management.FinishPasskeyRegistrationResponse.passkey:type_name -> v1.manager.Passkey
+	42, // 10: v1.manager.management.ListPasskeysResponse.passkeys:type_name -> v1.manager.Passkey
+	43, // 11: v1.manager.management.CreatePersonalTokenResponse.personal_token:type_name -> v1.manager.PersonalToken
+	43, // 12: v1.manager.management.ListPersonalTokensResponse.personal_tokens:type_name -> v1.manager.PersonalToken
+	0,  // 13: v1.manager.management.ManagementService.Register:input_type -> v1.manager.management.RegisterRequest
+	2,  // 14: v1.manager.management.ManagementService.Get:input_type -> v1.manager.management.GetRequest
+	4,  // 15: v1.manager.management.ManagementService.Update:input_type -> v1.manager.management.UpdateRequest
+	6,  // 16: v1.manager.management.ManagementService.Delete:input_type -> v1.manager.management.DeleteRequest
+	8,  // 17: v1.manager.management.ManagementService.ChangeEmail:input_type -> v1.manager.management.ChangeEmailRequest
+	14, // 18: v1.manager.management.ManagementService.Export:input_type -> v1.manager.management.ExportRequest
+	10, // 19: v1.manager.management.ManagementService.ListSessions:input_type -> v1.manager.management.ListSessionsRequest
+	12, // 20: v1.manager.management.ManagementService.RevokeSession:input_type -> v1.manager.management.RevokeSessionRequest
+	16, // 21: v1.manager.management.ManagementService.ListAuditEvents:input_type -> v1.manager.management.ListAuditEventsRequest
+	18, // 22: v1.manager.management.ManagementService.BeginPasskeyRegistration:input_type -> v1.manager.management.BeginPasskeyRegistrationRequest
+	20, // 23: v1.manager.management.ManagementService.FinishPasskeyRegistration:input_type -> v1.manager.management.FinishPasskeyRegistrationRequest
+	22, // 24: v1.manager.management.ManagementService.ListPasskeys:input_type -> v1.manager.management.ListPasskeysRequest
+	24, // 25: v1.manager.management.ManagementService.DeletePasskey:input_type -> v1.manager.management.DeletePasskeyRequest
+	26, // 26: v1.manager.management.ManagementService.EnrollTotp:input_type -> v1.manager.management.EnrollTotpRequest
+	28, // 27: v1.manager.management.ManagementService.ConfirmTotp:input_type -> v1.manager.management.ConfirmTotpRequest
+	30, // 28: v1.manager.management.ManagementService.DisableTotp:input_type -> v1.manager.management.DisableTotpRequest
+	32, // 29: v1.manager.management.ManagementService.CreatePersonalToken:input_type -> v1.manager.management.CreatePersonalTokenRequest
+	34, // 30: v1.manager.management.ManagementService.ListPersonalTokens:input_type -> v1.manager.management.ListPersonalTokensRequest
+	36, // 31: v1.manager.management.ManagementService.RevokePersonalToken:input_type -> v1.manager.management.RevokePersonalTokenRequest
+	1,  // 32: v1.manager.management.ManagementService.Register:output_type -> v1.manager.management.RegisterResponse
+	3,  // 33: v1.manager.management.ManagementService.Get:output_type -> v1.manager.management.GetResponse
+	5,  // 34: v1.manager.management.ManagementService.Update:output_type -> v1.manager.management.UpdateResponse
+	7,  // 35: v1.manager.management.ManagementService.Delete:output_type -> v1.manager.management.DeleteResponse
+	9,  // 36: v1.manager.management.ManagementService.ChangeEmail:output_type -> v1.manager.management.ChangeEmailResponse
+	15, // 37: v1.manager.management.ManagementService.Export:output_type -> v1.manager.management.ExportResponse
+	11, // 38: v1.manager.management.ManagementService.ListSessions:output_type -> v1.manager.management.ListSessionsResponse
+	13, // 39: v1.manager.management.ManagementService.RevokeSession:output_type -> v1.manager.management.RevokeSessionResponse
+	17, // 40: v1.manager.management.ManagementService.ListAuditEvents:output_type -> v1.manager.management.ListAuditEventsResponse
+	19, // 41: v1.manager.management.ManagementService.BeginPasskeyRegistration:output_type -> v1.manager.management.BeginPasskeyRegistrationResponse
+	21, // 42: v1.manager.management.ManagementService.FinishPasskeyRegistration:output_type -> v1.manager.management.FinishPasskeyRegistrationResponse
+	23, // 43: v1.manager.management.ManagementService.ListPasskeys:output_type -> v1.manager.management.ListPasskeysResponse
+	25, // 44: v1.manager.management.ManagementService.DeletePasskey:output_type -> v1.manager.management.DeletePasskeyResponse
+	27, // 45: v1.manager.management.ManagementService.EnrollTotp:output_type -> v1.manager.management.EnrollTotpResponse
+	29, // 46: v1.manager.management.ManagementService.ConfirmTotp:output_type -> v1.manager.management.ConfirmTotpResponse
+	31, // 47: v1.manager.management.ManagementService.DisableTotp:output_type -> v1.manager.management.DisableTotpResponse
+	33, // 48: v1.manager.management.ManagementService.CreatePersonalToken:output_type -> v1.manager.management.CreatePersonalTokenResponse
+	35, // 49: v1.manager.management.ManagementService.ListPersonalTokens:output_type -> v1.manager.management.ListPersonalTokensResponse
+	37, // 50: v1.manager.management.ManagementService.RevokePersonalToken:output_type -> v1.manager.management.RevokePersonalTokenResponse
+	32, // [32:51] is the sub-list for method output_type
+	13, // [13:32] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_v1_manager_management_management_proto_init() }
@@ -470,7 +1995,7 @@ func file_v1_manager_management_management_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1_manager_management_management_proto_rawDesc), len(file_v1_manager_management_management_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
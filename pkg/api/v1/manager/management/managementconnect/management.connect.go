@@ -44,6 +44,51 @@ const (
 	// ManagementServiceDeleteProcedure is the fully-qualified name of the ManagementService's Delete
 	// RPC.
 	ManagementServiceDeleteProcedure = "/v1.manager.management.ManagementService/Delete"
+	// ManagementServiceChangeEmailProcedure is the fully-qualified name of the ManagementService's
+	// ChangeEmail RPC.
+	ManagementServiceChangeEmailProcedure = "/v1.manager.management.ManagementService/ChangeEmail"
+	// ManagementServiceExportProcedure is the fully-qualified name of the ManagementService's Export
+	// RPC.
+	ManagementServiceExportProcedure = "/v1.manager.management.ManagementService/Export"
+	// ManagementServiceListSessionsProcedure is the fully-qualified name of the ManagementService's
+	// ListSessions RPC.
+	ManagementServiceListSessionsProcedure = "/v1.manager.management.ManagementService/ListSessions"
+	// ManagementServiceRevokeSessionProcedure is the fully-qualified name of the ManagementService's
+	// RevokeSession RPC.
+	ManagementServiceRevokeSessionProcedure = "/v1.manager.management.ManagementService/RevokeSession"
+	// ManagementServiceListAuditEventsProcedure is the fully-qualified name of the ManagementService's
+	// ListAuditEvents RPC.
+	ManagementServiceListAuditEventsProcedure = "/v1.manager.management.ManagementService/ListAuditEvents"
+	// ManagementServiceBeginPasskeyRegistrationProcedure is the fully-qualified name of the
+	// ManagementService's BeginPasskeyRegistration RPC.
+	ManagementServiceBeginPasskeyRegistrationProcedure = "/v1.manager.management.ManagementService/BeginPasskeyRegistration"
+	// ManagementServiceFinishPasskeyRegistrationProcedure is the fully-qualified name of the
+	// ManagementService's FinishPasskeyRegistration RPC.
+	ManagementServiceFinishPasskeyRegistrationProcedure = "/v1.manager.management.ManagementService/FinishPasskeyRegistration"
+	// ManagementServiceListPasskeysProcedure is the fully-qualified name of the ManagementService's
+	// ListPasskeys RPC.
+	ManagementServiceListPasskeysProcedure = "/v1.manager.management.ManagementService/ListPasskeys"
+	// ManagementServiceDeletePasskeyProcedure is the fully-qualified name of the ManagementService's
+	// DeletePasskey RPC.
+	ManagementServiceDeletePasskeyProcedure = "/v1.manager.management.ManagementService/DeletePasskey"
+	// ManagementServiceEnrollTotpProcedure is the fully-qualified name of the ManagementService's
+	// EnrollTotp RPC.
+	ManagementServiceEnrollTotpProcedure = "/v1.manager.management.ManagementService/EnrollTotp"
+	// ManagementServiceConfirmTotpProcedure is the fully-qualified name of the ManagementService's
+	// ConfirmTotp RPC.
+	ManagementServiceConfirmTotpProcedure = "/v1.manager.management.ManagementService/ConfirmTotp"
+	// ManagementServiceDisableTotpProcedure is the fully-qualified name of the ManagementService's
+	// DisableTotp RPC.
+	ManagementServiceDisableTotpProcedure = "/v1.manager.management.ManagementService/DisableTotp"
+	// ManagementServiceCreatePersonalTokenProcedure is the fully-qualified name of the
+	// ManagementService's CreatePersonalToken RPC.
+	ManagementServiceCreatePersonalTokenProcedure = "/v1.manager.management.ManagementService/CreatePersonalToken"
+	// ManagementServiceListPersonalTokensProcedure is the fully-qualified name of the
+	// ManagementService's ListPersonalTokens RPC.
+	ManagementServiceListPersonalTokensProcedure = "/v1.manager.management.ManagementService/ListPersonalTokens"
+	// ManagementServiceRevokePersonalTokenProcedure is the fully-qualified name of the
+	// ManagementService's RevokePersonalToken RPC.
+	ManagementServiceRevokePersonalTokenProcedure = "/v1.manager.management.ManagementService/RevokePersonalToken"
 )
 
 // ManagementServiceClient is a client for the v1.manager.management.ManagementService service.
@@ -52,6 +97,21 @@ type ManagementServiceClient interface {
 	Get(context.Context, *connect.Request[management.GetRequest]) (*connect.Response[management.GetResponse], error)
 	Update(context.Context, *connect.Request[management.UpdateRequest]) (*connect.Response[management.UpdateResponse], error)
 	Delete(context.Context, *connect.Request[management.DeleteRequest]) (*connect.Response[management.DeleteResponse], error)
+	ChangeEmail(context.Context, *connect.Request[management.ChangeEmailRequest]) (*connect.Response[management.ChangeEmailResponse], error)
+	Export(context.Context, *connect.Request[management.ExportRequest]) (*connect.Response[management.ExportResponse], error)
+	ListSessions(context.Context, *connect.Request[management.ListSessionsRequest]) (*connect.Response[management.ListSessionsResponse], error)
+	RevokeSession(context.Context, *connect.Request[management.RevokeSessionRequest]) (*connect.Response[management.RevokeSessionResponse], error)
+	ListAuditEvents(context.Context, *connect.Request[management.ListAuditEventsRequest]) (*connect.Response[management.ListAuditEventsResponse], error)
+	BeginPasskeyRegistration(context.Context, *connect.Request[management.BeginPasskeyRegistrationRequest]) (*connect.Response[management.BeginPasskeyRegistrationResponse], error)
+	FinishPasskeyRegistration(context.Context, *connect.Request[management.FinishPasskeyRegistrationRequest]) (*connect.Response[management.FinishPasskeyRegistrationResponse], error)
+	ListPasskeys(context.Context, *connect.Request[management.ListPasskeysRequest]) (*connect.Response[management.ListPasskeysResponse], error)
+	DeletePasskey(context.Context, *connect.Request[management.DeletePasskeyRequest]) (*connect.Response[management.DeletePasskeyResponse], error)
+	EnrollTotp(context.Context, *connect.Request[management.EnrollTotpRequest]) (*connect.Response[management.EnrollTotpResponse], error)
+	ConfirmTotp(context.Context, *connect.Request[management.ConfirmTotpRequest]) (*connect.Response[management.ConfirmTotpResponse], error)
+	DisableTotp(context.Context, *connect.Request[management.DisableTotpRequest]) (*connect.Response[management.DisableTotpResponse], error)
+	CreatePersonalToken(context.Context, *connect.Request[management.CreatePersonalTokenRequest]) (*connect.Response[management.CreatePersonalTokenResponse], error)
+	ListPersonalTokens(context.Context, *connect.Request[management.ListPersonalTokensRequest]) (*connect.Response[management.ListPersonalTokensResponse], error)
+	RevokePersonalToken(context.Context, *connect.Request[management.RevokePersonalTokenRequest]) (*connect.Response[management.RevokePersonalTokenResponse], error)
 }
 
 // NewManagementServiceClient constructs a client for the v1.manager.management.ManagementService
@@ -89,15 +149,120 @@ func NewManagementServiceClient(httpClient connect.HTTPClient, baseURL string, o
 			connect.WithSchema(managementServiceMethods.ByName("Delete")),
 			connect.WithClientOptions(opts...),
 		),
+		changeEmail: connect.NewClient[management.ChangeEmailRequest, management.ChangeEmailResponse](
+			httpClient,
+			baseURL+ManagementServiceChangeEmailProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("ChangeEmail")),
+			connect.WithClientOptions(opts...),
+		),
+		export: connect.NewClient[management.ExportRequest, management.ExportResponse](
+			httpClient,
+			baseURL+ManagementServiceExportProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("Export")),
+			connect.WithClientOptions(opts...),
+		),
+		listSessions: connect.NewClient[management.ListSessionsRequest, management.ListSessionsResponse](
+			httpClient,
+			baseURL+ManagementServiceListSessionsProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("ListSessions")),
+			connect.WithClientOptions(opts...),
+		),
+		revokeSession: connect.NewClient[management.RevokeSessionRequest, management.RevokeSessionResponse](
+			httpClient,
+			baseURL+ManagementServiceRevokeSessionProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("RevokeSession")),
+			connect.WithClientOptions(opts...),
+		),
+		listAuditEvents: connect.NewClient[management.ListAuditEventsRequest, management.ListAuditEventsResponse](
+			httpClient,
+			baseURL+ManagementServiceListAuditEventsProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("ListAuditEvents")),
+			connect.WithClientOptions(opts...),
+		),
+		beginPasskeyRegistration: connect.NewClient[management.BeginPasskeyRegistrationRequest, management.BeginPasskeyRegistrationResponse](
+			httpClient,
+			baseURL+ManagementServiceBeginPasskeyRegistrationProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("BeginPasskeyRegistration")),
+			connect.WithClientOptions(opts...),
+		),
+		finishPasskeyRegistration: connect.NewClient[management.FinishPasskeyRegistrationRequest, management.FinishPasskeyRegistrationResponse](
+			httpClient,
+			baseURL+ManagementServiceFinishPasskeyRegistrationProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("FinishPasskeyRegistration")),
+			connect.WithClientOptions(opts...),
+		),
+		listPasskeys: connect.NewClient[management.ListPasskeysRequest, management.ListPasskeysResponse](
+			httpClient,
+			baseURL+ManagementServiceListPasskeysProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("ListPasskeys")),
+			connect.WithClientOptions(opts...),
+		),
+		deletePasskey: connect.NewClient[management.DeletePasskeyRequest, management.DeletePasskeyResponse](
+			httpClient,
+			baseURL+ManagementServiceDeletePasskeyProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("DeletePasskey")),
+			connect.WithClientOptions(opts...),
+		),
+		enrollTotp: connect.NewClient[management.EnrollTotpRequest, management.EnrollTotpResponse](
+			httpClient,
+			baseURL+ManagementServiceEnrollTotpProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("EnrollTotp")),
+			connect.WithClientOptions(opts...),
+		),
+		confirmTotp: connect.NewClient[management.ConfirmTotpRequest, management.ConfirmTotpResponse](
+			httpClient,
+			baseURL+ManagementServiceConfirmTotpProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("ConfirmTotp")),
+			connect.WithClientOptions(opts...),
+		),
+		disableTotp: connect.NewClient[management.DisableTotpRequest, management.DisableTotpResponse](
+			httpClient,
+			baseURL+ManagementServiceDisableTotpProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("DisableTotp")),
+			connect.WithClientOptions(opts...),
+		),
+		createPersonalToken: connect.NewClient[management.CreatePersonalTokenRequest, management.CreatePersonalTokenResponse](
+			httpClient,
+			baseURL+ManagementServiceCreatePersonalTokenProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("CreatePersonalToken")),
+			connect.WithClientOptions(opts...),
+		),
+		listPersonalTokens: connect.NewClient[management.ListPersonalTokensRequest, management.ListPersonalTokensResponse](
+			httpClient,
+			baseURL+ManagementServiceListPersonalTokensProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("ListPersonalTokens")),
+			connect.WithClientOptions(opts...),
+		),
+		revokePersonalToken: connect.NewClient[management.RevokePersonalTokenRequest, management.RevokePersonalTokenResponse](
+			httpClient,
+			baseURL+ManagementServiceRevokePersonalTokenProcedure,
+			connect.WithSchema(managementServiceMethods.ByName("RevokePersonalToken")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // managementServiceClient implements ManagementServiceClient.
 type managementServiceClient struct {
-	register *connect.Client[management.RegisterRequest, management.RegisterResponse]
-	get      *connect.Client[management.GetRequest, management.GetResponse]
-	update   *connect.Client[management.UpdateRequest, management.UpdateResponse]
-	delete   *connect.Client[management.DeleteRequest, management.DeleteResponse]
+	register                  *connect.Client[management.RegisterRequest, management.RegisterResponse]
+	get                       *connect.Client[management.GetRequest, management.GetResponse]
+	update                    *connect.Client[management.UpdateRequest, management.UpdateResponse]
+	delete                    *connect.Client[management.DeleteRequest, management.DeleteResponse]
+	changeEmail               *connect.Client[management.ChangeEmailRequest, management.ChangeEmailResponse]
+	export                    *connect.Client[management.ExportRequest, management.ExportResponse]
+	listSessions              *connect.Client[management.ListSessionsRequest, management.ListSessionsResponse]
+	revokeSession             *connect.Client[management.RevokeSessionRequest, management.RevokeSessionResponse]
+	listAuditEvents           *connect.Client[management.ListAuditEventsRequest, management.ListAuditEventsResponse]
+	beginPasskeyRegistration  *connect.Client[management.BeginPasskeyRegistrationRequest, management.BeginPasskeyRegistrationResponse]
+	finishPasskeyRegistration *connect.Client[management.FinishPasskeyRegistrationRequest, management.FinishPasskeyRegistrationResponse]
+	listPasskeys              *connect.Client[management.ListPasskeysRequest, management.ListPasskeysResponse]
+	deletePasskey             *connect.Client[management.DeletePasskeyRequest, management.DeletePasskeyResponse]
+	enrollTotp                *connect.Client[management.EnrollTotpRequest, management.EnrollTotpResponse]
+	confirmTotp               *connect.Client[management.ConfirmTotpRequest, management.ConfirmTotpResponse]
+	disableTotp               *connect.Client[management.DisableTotpRequest, management.DisableTotpResponse]
+	createPersonalToken       *connect.Client[management.CreatePersonalTokenRequest, management.CreatePersonalTokenResponse]
+	listPersonalTokens        *connect.Client[management.ListPersonalTokensRequest, management.ListPersonalTokensResponse]
+	revokePersonalToken       *connect.Client[management.RevokePersonalTokenRequest, management.RevokePersonalTokenResponse]
 }
 
 // Register calls v1.manager.management.ManagementService.Register.
@@ -120,6 +285,82 @@ func (c *managementServiceClient) Delete(ctx context.Context, req *connect.Reque
 	return c.delete.CallUnary(ctx, req)
 }
 
+// ChangeEmail calls v1.manager.management.ManagementService.ChangeEmail.
+func (c *managementServiceClient) ChangeEmail(ctx context.Context, req *connect.Request[management.ChangeEmailRequest]) (*connect.Response[management.ChangeEmailResponse], error) {
+	return c.changeEmail.CallUnary(ctx, req)
+}
+
+// Export calls v1.manager.management.ManagementService.Export.
+func (c *managementServiceClient) Export(ctx context.Context, req *connect.Request[management.ExportRequest]) (*connect.Response[management.ExportResponse], error) {
+	return c.export.CallUnary(ctx, req)
+}
+
+// ListSessions calls v1.manager.management.ManagementService.ListSessions.
+func (c *managementServiceClient) ListSessions(ctx context.Context, req *connect.Request[management.ListSessionsRequest]) (*connect.Response[management.ListSessionsResponse], error) {
+	return c.listSessions.CallUnary(ctx, req)
+}
+
+// RevokeSession calls v1.manager.management.ManagementService.RevokeSession.
+func (c *managementServiceClient) RevokeSession(ctx context.Context, req *connect.Request[management.RevokeSessionRequest]) (*connect.Response[management.RevokeSessionResponse], error) {
+	return c.revokeSession.CallUnary(ctx, req)
+}
+
+// ListAuditEvents calls v1.manager.management.ManagementService.ListAuditEvents.
+func (c *managementServiceClient) ListAuditEvents(ctx context.Context, req *connect.Request[management.ListAuditEventsRequest]) (*connect.Response[management.ListAuditEventsResponse], error) {
+	return c.listAuditEvents.CallUnary(ctx, req)
+}
+
+// BeginPasskeyRegistration calls v1.manager.management.ManagementService.BeginPasskeyRegistration.
+func (c *managementServiceClient) BeginPasskeyRegistration(ctx context.Context, req *connect.Request[management.BeginPasskeyRegistrationRequest]) (*connect.Response[management.BeginPasskeyRegistrationResponse], error) {
+	return c.beginPasskeyRegistration.CallUnary(ctx, req)
+}
+
+// FinishPasskeyRegistration calls
+// v1.manager.management.ManagementService.FinishPasskeyRegistration.
+func (c *managementServiceClient) FinishPasskeyRegistration(ctx context.Context, req *connect.Request[management.FinishPasskeyRegistrationRequest]) (*connect.Response[management.FinishPasskeyRegistrationResponse], error) {
+	return c.finishPasskeyRegistration.CallUnary(ctx, req)
+}
+
+// ListPasskeys calls v1.manager.management.ManagementService.ListPasskeys.
+func (c *managementServiceClient) ListPasskeys(ctx context.Context, req *connect.Request[management.ListPasskeysRequest]) (*connect.Response[management.ListPasskeysResponse], error) {
+	return c.listPasskeys.CallUnary(ctx, req)
+}
+
+// DeletePasskey calls v1.manager.management.ManagementService.DeletePasskey.
+func (c *managementServiceClient) DeletePasskey(ctx context.Context, req *connect.Request[management.DeletePasskeyRequest]) (*connect.Response[management.DeletePasskeyResponse], error) {
+	return c.deletePasskey.CallUnary(ctx, req)
+}
+
+// EnrollTotp calls v1.manager.management.ManagementService.EnrollTotp.
+func (c *managementServiceClient) EnrollTotp(ctx context.Context, req *connect.Request[management.EnrollTotpRequest]) (*connect.Response[management.EnrollTotpResponse], error) {
+	return c.enrollTotp.CallUnary(ctx, req)
+}
+
+// ConfirmTotp calls v1.manager.management.ManagementService.ConfirmTotp.
+func (c *managementServiceClient) ConfirmTotp(ctx context.Context, req *connect.Request[management.ConfirmTotpRequest]) (*connect.Response[management.ConfirmTotpResponse], error) {
+	return c.confirmTotp.CallUnary(ctx, req)
+}
+
+// DisableTotp calls v1.manager.management.ManagementService.DisableTotp.
+func (c *managementServiceClient) DisableTotp(ctx context.Context, req *connect.Request[management.DisableTotpRequest]) (*connect.Response[management.DisableTotpResponse], error) {
+	return c.disableTotp.CallUnary(ctx, req)
+}
+
+// CreatePersonalToken calls v1.manager.management.ManagementService.CreatePersonalToken.
+func (c *managementServiceClient) CreatePersonalToken(ctx context.Context, req *connect.Request[management.CreatePersonalTokenRequest]) (*connect.Response[management.CreatePersonalTokenResponse], error) {
+	return c.createPersonalToken.CallUnary(ctx, req)
+}
+
+// ListPersonalTokens calls v1.manager.management.ManagementService.ListPersonalTokens.
+func (c *managementServiceClient) ListPersonalTokens(ctx context.Context, req *connect.Request[management.ListPersonalTokensRequest]) (*connect.Response[management.ListPersonalTokensResponse], error) {
+	return c.listPersonalTokens.CallUnary(ctx, req)
+}
+
+// RevokePersonalToken calls v1.manager.management.ManagementService.RevokePersonalToken.
+func (c *managementServiceClient) RevokePersonalToken(ctx context.Context, req *connect.Request[management.RevokePersonalTokenRequest]) (*connect.Response[management.RevokePersonalTokenResponse], error) {
+	return c.revokePersonalToken.CallUnary(ctx, req)
+}
+
 // ManagementServiceHandler is an implementation of the v1.manager.management.ManagementService
 // service.
 type ManagementServiceHandler interface {
@@ -127,6 +368,21 @@ type ManagementServiceHandler interface {
 	Get(context.Context, *connect.Request[management.GetRequest]) (*connect.Response[management.GetResponse], error)
 	Update(context.Context, *connect.Request[management.UpdateRequest]) (*connect.Response[management.UpdateResponse], error)
 	Delete(context.Context, *connect.Request[management.DeleteRequest]) (*connect.Response[management.DeleteResponse], error)
+	ChangeEmail(context.Context, *connect.Request[management.ChangeEmailRequest]) (*connect.Response[management.ChangeEmailResponse], error)
+	Export(context.Context, *connect.Request[management.ExportRequest]) (*connect.Response[management.ExportResponse], error)
+	ListSessions(context.Context, *connect.Request[management.ListSessionsRequest]) (*connect.Response[management.ListSessionsResponse], error)
+	RevokeSession(context.Context, *connect.Request[management.RevokeSessionRequest]) (*connect.Response[management.RevokeSessionResponse], error)
+	ListAuditEvents(context.Context, *connect.Request[management.ListAuditEventsRequest]) (*connect.Response[management.ListAuditEventsResponse], error)
+	BeginPasskeyRegistration(context.Context, *connect.Request[management.BeginPasskeyRegistrationRequest]) (*connect.Response[management.BeginPasskeyRegistrationResponse], error)
+	FinishPasskeyRegistration(context.Context, *connect.Request[management.FinishPasskeyRegistrationRequest]) (*connect.Response[management.FinishPasskeyRegistrationResponse], error)
+	ListPasskeys(context.Context, *connect.Request[management.ListPasskeysRequest]) (*connect.Response[management.ListPasskeysResponse], error)
+	DeletePasskey(context.Context, *connect.Request[management.DeletePasskeyRequest]) (*connect.Response[management.DeletePasskeyResponse], error)
+	EnrollTotp(context.Context, *connect.Request[management.EnrollTotpRequest]) (*connect.Response[management.EnrollTotpResponse], error)
+	ConfirmTotp(context.Context, *connect.Request[management.ConfirmTotpRequest]) (*connect.Response[management.ConfirmTotpResponse], error)
+	DisableTotp(context.Context, *connect.Request[management.DisableTotpRequest]) (*connect.Response[management.DisableTotpResponse], error)
+	CreatePersonalToken(context.Context, *connect.Request[management.CreatePersonalTokenRequest]) (*connect.Response[management.CreatePersonalTokenResponse], error)
+	ListPersonalTokens(context.Context, *connect.Request[management.ListPersonalTokensRequest]) (*connect.Response[management.ListPersonalTokensResponse], error)
+	RevokePersonalToken(context.Context, *connect.Request[management.RevokePersonalTokenRequest]) (*connect.Response[management.RevokePersonalTokenResponse], error)
 }
 
 // NewManagementServiceHandler builds an HTTP handler from the service implementation. It returns
@@ -160,6 +416,96 @@ func NewManagementServiceHandler(svc ManagementServiceHandler, opts ...connect.H
 		connect.WithSchema(managementServiceMethods.ByName("Delete")),
 		connect.WithHandlerOptions(opts...),
 	)
+	managementServiceChangeEmailHandler := connect.NewUnaryHandler(
+		ManagementServiceChangeEmailProcedure,
+		svc.ChangeEmail,
+		connect.WithSchema(managementServiceMethods.ByName("ChangeEmail")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceExportHandler := connect.NewUnaryHandler(
+		ManagementServiceExportProcedure,
+		svc.Export,
+		connect.WithSchema(managementServiceMethods.ByName("Export")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceListSessionsHandler := connect.NewUnaryHandler(
+		ManagementServiceListSessionsProcedure,
+		svc.ListSessions,
+		connect.WithSchema(managementServiceMethods.ByName("ListSessions")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceRevokeSessionHandler := connect.NewUnaryHandler(
+		ManagementServiceRevokeSessionProcedure,
+		svc.RevokeSession,
+		connect.WithSchema(managementServiceMethods.ByName("RevokeSession")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceListAuditEventsHandler := connect.NewUnaryHandler(
+		ManagementServiceListAuditEventsProcedure,
+		svc.ListAuditEvents,
+		connect.WithSchema(managementServiceMethods.ByName("ListAuditEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceBeginPasskeyRegistrationHandler := connect.NewUnaryHandler(
+		ManagementServiceBeginPasskeyRegistrationProcedure,
+		svc.BeginPasskeyRegistration,
+		connect.WithSchema(managementServiceMethods.ByName("BeginPasskeyRegistration")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceFinishPasskeyRegistrationHandler := connect.NewUnaryHandler(
+		ManagementServiceFinishPasskeyRegistrationProcedure,
+		svc.FinishPasskeyRegistration,
+		connect.WithSchema(managementServiceMethods.ByName("FinishPasskeyRegistration")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceListPasskeysHandler := connect.NewUnaryHandler(
+		ManagementServiceListPasskeysProcedure,
+		svc.ListPasskeys,
+		connect.WithSchema(managementServiceMethods.ByName("ListPasskeys")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceDeletePasskeyHandler := connect.NewUnaryHandler(
+		ManagementServiceDeletePasskeyProcedure,
+		svc.DeletePasskey,
+		connect.WithSchema(managementServiceMethods.ByName("DeletePasskey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceEnrollTotpHandler := connect.NewUnaryHandler(
+		ManagementServiceEnrollTotpProcedure,
+		svc.EnrollTotp,
+		connect.WithSchema(managementServiceMethods.ByName("EnrollTotp")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceConfirmTotpHandler := connect.NewUnaryHandler(
+		ManagementServiceConfirmTotpProcedure,
+		svc.ConfirmTotp,
+		connect.WithSchema(managementServiceMethods.ByName("ConfirmTotp")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceDisableTotpHandler := connect.NewUnaryHandler(
+		ManagementServiceDisableTotpProcedure,
+		svc.DisableTotp,
+		connect.WithSchema(managementServiceMethods.ByName("DisableTotp")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceCreatePersonalTokenHandler := connect.NewUnaryHandler(
+		ManagementServiceCreatePersonalTokenProcedure,
+		svc.CreatePersonalToken,
+		connect.WithSchema(managementServiceMethods.ByName("CreatePersonalToken")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceListPersonalTokensHandler := connect.NewUnaryHandler(
+		ManagementServiceListPersonalTokensProcedure,
+		svc.ListPersonalTokens,
+		connect.WithSchema(managementServiceMethods.ByName("ListPersonalTokens")),
+		connect.WithHandlerOptions(opts...),
+	)
+	managementServiceRevokePersonalTokenHandler := connect.NewUnaryHandler(
+		ManagementServiceRevokePersonalTokenProcedure,
+		svc.RevokePersonalToken,
+		connect.WithSchema(managementServiceMethods.ByName("RevokePersonalToken")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/v1.manager.management.ManagementService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case ManagementServiceRegisterProcedure:
@@ -170,6 +516,36 @@ func NewManagementServiceHandler(svc ManagementServiceHandler, opts ...connect.H
 			managementServiceUpdateHandler.ServeHTTP(w, r)
 		case ManagementServiceDeleteProcedure:
 			managementServiceDeleteHandler.ServeHTTP(w, r)
+		case ManagementServiceChangeEmailProcedure:
+			managementServiceChangeEmailHandler.ServeHTTP(w, r)
+		case ManagementServiceExportProcedure:
+			managementServiceExportHandler.ServeHTTP(w, r)
+		case ManagementServiceListSessionsProcedure:
+			managementServiceListSessionsHandler.ServeHTTP(w, r)
+		case ManagementServiceRevokeSessionProcedure:
+			managementServiceRevokeSessionHandler.ServeHTTP(w, r)
+		case ManagementServiceListAuditEventsProcedure:
+			managementServiceListAuditEventsHandler.ServeHTTP(w, r)
+		case ManagementServiceBeginPasskeyRegistrationProcedure:
+			managementServiceBeginPasskeyRegistrationHandler.ServeHTTP(w, r)
+		case ManagementServiceFinishPasskeyRegistrationProcedure:
+			managementServiceFinishPasskeyRegistrationHandler.ServeHTTP(w, r)
+		case ManagementServiceListPasskeysProcedure:
+			managementServiceListPasskeysHandler.ServeHTTP(w, r)
+		case ManagementServiceDeletePasskeyProcedure:
+			managementServiceDeletePasskeyHandler.ServeHTTP(w, r)
+		case ManagementServiceEnrollTotpProcedure:
+			managementServiceEnrollTotpHandler.ServeHTTP(w, r)
+		case ManagementServiceConfirmTotpProcedure:
+			managementServiceConfirmTotpHandler.ServeHTTP(w, r)
+		case ManagementServiceDisableTotpProcedure:
+			managementServiceDisableTotpHandler.ServeHTTP(w, r)
+		case ManagementServiceCreatePersonalTokenProcedure:
+			managementServiceCreatePersonalTokenHandler.ServeHTTP(w, r)
+		case ManagementServiceListPersonalTokensProcedure:
+			managementServiceListPersonalTokensHandler.ServeHTTP(w, r)
+		case ManagementServiceRevokePersonalTokenProcedure:
+			managementServiceRevokePersonalTokenHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -194,3 +570,63 @@ func (UnimplementedManagementServiceHandler) Update(context.Context, *connect.Re
 func (UnimplementedManagementServiceHandler) Delete(context.Context, *connect.Request[management.DeleteRequest]) (*connect.Response[management.DeleteResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.Delete is not implemented"))
 }
+
+func (UnimplementedManagementServiceHandler) ChangeEmail(context.Context, *connect.Request[management.ChangeEmailRequest]) (*connect.Response[management.ChangeEmailResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.ChangeEmail is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) Export(context.Context, *connect.Request[management.ExportRequest]) (*connect.Response[management.ExportResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.Export is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) ListSessions(context.Context, *connect.Request[management.ListSessionsRequest]) (*connect.Response[management.ListSessionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.ListSessions is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) RevokeSession(context.Context, *connect.Request[management.RevokeSessionRequest]) (*connect.Response[management.RevokeSessionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.RevokeSession is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) ListAuditEvents(context.Context, *connect.Request[management.ListAuditEventsRequest]) (*connect.Response[management.ListAuditEventsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.ListAuditEvents is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) BeginPasskeyRegistration(context.Context, *connect.Request[management.BeginPasskeyRegistrationRequest]) (*connect.Response[management.BeginPasskeyRegistrationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.BeginPasskeyRegistration is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) FinishPasskeyRegistration(context.Context, *connect.Request[management.FinishPasskeyRegistrationRequest]) (*connect.Response[management.FinishPasskeyRegistrationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.FinishPasskeyRegistration is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) ListPasskeys(context.Context, *connect.Request[management.ListPasskeysRequest]) (*connect.Response[management.ListPasskeysResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.ListPasskeys is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) DeletePasskey(context.Context, *connect.Request[management.DeletePasskeyRequest]) (*connect.Response[management.DeletePasskeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.DeletePasskey is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) EnrollTotp(context.Context, *connect.Request[management.EnrollTotpRequest]) (*connect.Response[management.EnrollTotpResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.EnrollTotp is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) ConfirmTotp(context.Context, *connect.Request[management.ConfirmTotpRequest]) (*connect.Response[management.ConfirmTotpResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.ConfirmTotp is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) DisableTotp(context.Context, *connect.Request[management.DisableTotpRequest]) (*connect.Response[management.DisableTotpResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.DisableTotp is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) CreatePersonalToken(context.Context, *connect.Request[management.CreatePersonalTokenRequest]) (*connect.Response[management.CreatePersonalTokenResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.CreatePersonalToken is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) ListPersonalTokens(context.Context, *connect.Request[management.ListPersonalTokensRequest]) (*connect.Response[management.ListPersonalTokensResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.ListPersonalTokens is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) RevokePersonalToken(context.Context, *connect.Request[management.RevokePersonalTokenRequest]) (*connect.Response[management.RevokePersonalTokenResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("v1.manager.management.ManagementService.RevokePersonalToken is not implemented"))
+}
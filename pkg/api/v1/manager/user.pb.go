@@ -32,6 +32,7 @@ type User struct {
 	Streak        int64                  `protobuf:"varint,7,opt,name=streak,proto3" json:"streak,omitempty"`
 	Score         float64                `protobuf:"fixed64,8,opt,name=score,proto3" json:"score,omitempty"`
 	MaxStreak     int64                  `protobuf:"varint,9,opt,name=max_streak,json=maxStreak,proto3" json:"max_streak,omitempty"`
+	TotpEnabled   bool                   `protobuf:"varint,10,opt,name=totp_enabled,json=totpEnabled,proto3" json:"totp_enabled,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -129,12 +130,19 @@ func (x *User) GetMaxStreak() int64 {
 	return 0
 }
 
+func (x *User) GetTotpEnabled() bool {
+	if x != nil {
+		return x.TotpEnabled
+	}
+	return false
+}
+
 var File_v1_manager_user_proto protoreflect.FileDescriptor
 
 const file_v1_manager_user_proto_rawDesc = "" +
 	"\n" +
 	"\x15v1/manager/user.proto\x12\n" +
-	"v1.manager\"\xf8\x01\n" +
+	"v1.manager\"\x9b\x02\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12 \n" +
@@ -146,7 +154,9 @@ const file_v1_manager_user_proto_rawDesc = "" +
 	"\x06streak\x18\a \x01(\x03R\x06streak\x12\x14\n" +
 	"\x05score\x18\b \x01(\x01R\x05score\x12\x1d\n" +
 	"\n" +
-	"max_streak\x18\t \x01(\x03R\tmaxStreakB,Z*github.com/megakuul/zen/pkg/api/v1/managerb\x06proto3"
+	"max_streak\x18\t \x01(\x03R\tmaxStreak\x12!\n" +
+	"\ftotp_enabled\x18\n" +
+	" \x01(\bR\vtotpEnabledB,Z*github.com/megakuul/zen/pkg/api/v1/managerb\x06proto3"
 
 var (
 	file_v1_manager_user_proto_rawDescOnce sync.Once
@@ -0,0 +1,62 @@
+// @generated by protoc-gen-es v2.7.0 with parameter "target=ts"
+// @generated from file v1/manager/audit.proto (package v1.manager, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file v1/manager/audit.proto.
+ */
+export const file_v1_manager_audit: GenFile = /*@__PURE__*/
+  fileDesc("ChZ2MS9tYW5hZ2VyL2F1ZGl0LnByb3RvEgp2MS5tYW5hZ2VyIm4KCkF1ZGl0RXZlbnQSCgoCaWQYASABKAkSDAoEdHlwZRgCIAEoCRIOCgZkZXRhaWwYAyABKAkSEgoKdXNlcl9hZ2VudBgEIAEoCRIOCgZvcmlnaW4YBSABKAkSEgoKY3JlYXRlZF9hdBgGIAEoA0IsWipnaXRodWIuY29tL21lZ2FrdXVsL3plbi9wa2cvYXBpL3YxL21hbmFnZXJiBnByb3RvMw");
+
+/**
+ * @generated from message v1.manager.AuditEvent
+ */
+export type AuditEvent = Message<"v1.manager.AuditEvent"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * kind of the event (e.g. "login", "logout", "profile_update").
+   *
+   * @generated from field: string type = 2;
+   */
+  type: string;
+
+  /**
+   * additional information about the event (e.g. the login method).
+   *
+   * @generated from field: string detail = 3;
+   */
+  detail: string;
+
+  /**
+   * @generated from field: string user_agent = 4;
+   */
+  userAgent: string;
+
+  /**
+   * @generated from field: string origin = 5;
+   */
+  origin: string;
+
+  /**
+   * unix milliseconds.
+   *
+   * @generated from field: int64 created_at = 6;
+   */
+  createdAt: bigint;
+};
+
+/**
+ * Describes the message v1.manager.AuditEvent.
+ * Use `create(AuditEventSchema)` to create a new message.
+ */
+export const AuditEventSchema: GenMessage<AuditEvent> = /*@__PURE__*/
+  messageDesc(file_v1_manager_audit, 0);
+
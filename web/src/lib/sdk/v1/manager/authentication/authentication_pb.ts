@@ -12,7 +12,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file v1/manager/authentication/authentication.proto.
  */
 export const file_v1_manager_authentication_authentication: GenFile = /*@__PURE__*/
-  fileDesc("Ci52MS9tYW5hZ2VyL2F1dGhlbnRpY2F0aW9uL2F1dGhlbnRpY2F0aW9uLnByb3RvEhl2MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uIkwKDExvZ2luUmVxdWVzdBImCgh2ZXJpZmllchgBIAEoCzIULnYxLm1hbmFnZXIuVmVyaWZpZXISFAoMYXV0b19yZWZyZXNoGAIgASgIIh4KDUxvZ2luUmVzcG9uc2USDQoFdG9rZW4YASABKAkiDwoNTG9nb3V0UmVxdWVzdCIQCg5Mb2dvdXRSZXNwb25zZTLWAQoVQXV0aGVudGljYXRpb25TZXJ2aWNlElwKBUxvZ2luEicudjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5Mb2dpblJlcXVlc3QaKC52MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uLkxvZ2luUmVzcG9uc2UiABJfCgZMb2dvdXQSKC52MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uLkxvZ291dFJlcXVlc3QaKS52MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uLkxvZ291dFJlc3BvbnNlIgBCO1o5Z2l0aHViLmNvbS9tZWdha3V1bC96ZW4vcGtnL2FwaS92MS9tYW5hZ2VyL2F1dGhlbnRpY2F0aW9uYgZwcm90bzM", [file_v1_manager_verifier]);
+  fileDesc("Ci52MS9tYW5hZ2VyL2F1dGhlbnRpY2F0aW9uL2F1dGhlbnRpY2F0aW9uLnByb3RvEhl2MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uInQKDExvZ2luUmVxdWVzdBImCgh2ZXJpZmllchgBIAEoCzIULnYxLm1hbmFnZXIuVmVyaWZpZXISFAoMYXV0b19yZWZyZXNoGAIgASgIEhIKCm1hZ2ljX2xpbmsYAyABKAgSEgoKbGlua190b2tlbhgEIAEoCSI4Cg1Mb2dpblJlc3BvbnNlEg0KBXRva2VuGAEgASgJEhgKEHRvdHBfY2VyZW1vbnlfaWQYAiABKAkiDwoNTG9nb3V0UmVxdWVzdCIQCg5Mb2dvdXRSZXNwb25zZSIaChhCZWdpblBhc3NrZXlMb2dpblJlcXVlc3QiQQoZQmVnaW5QYXNza2V5TG9naW5SZXNwb25zZRITCgtjZXJlbW9ueV9pZBgBIAEoCRIPCgdvcHRpb25zGAIgASgJIloKGUZpbmlzaFBhc3NrZXlMb2dpblJlcXVlc3QSEwoLY2VyZW1vbnlfaWQYASABKAkSEgoKY3JlZGVudGlhbBgCIAEoCRIUCgxhdXRvX3JlZnJlc2gYAyABKAgiKwoaRmluaXNoUGFzc2tleUxvZ2luUmVzcG9uc2USDQoFdG9rZW4YASABKAkiNgoRVmVyaWZ5VG90cFJlcXVlc3QSEwoLY2VyZW1vbnlfaWQYASABKAkSDAoEY29kZRgCIAEoCSIjChJWZXJpZnlUb3RwUmVzcG9uc2USDQoFdG9rZW4YASABKAkiLQoVQmVnaW5PaWRjTG9naW5SZXF1ZXN0EhQKDGF1dG9fcmVmcmVzaBgBIAEoCCIlChZCZWdpbk9pZGNMb2dpblJlc3BvbnNlEgsKA3VybBgBIAEoCSI1ChZGaW5pc2hPaWRjTG9naW5SZXF1ZXN0Eg0KBXN0YXRlGAEgASgJEgwKBGNvZGUYAiABKAkiQgoXRmluaXNoT2lkY0xvZ2luUmVzcG9uc2USDQoFdG9rZW4YASABKAkSGAoQdG90cF9jZXJlbW9ueV9pZBgCIAEoCTLBBgoVQXV0aGVudGljYXRpb25TZXJ2aWNlElwKBUxvZ2luEicudjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5Mb2dpblJlcXVlc3QaKC52MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uLkxvZ2luUmVzcG9uc2UiABJfCgZMb2dvdXQSKC52MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uLkxvZ291dFJlcXVlc3QaKS52MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uLkxvZ291dFJlc3BvbnNlIgASgAEKEUJlZ2luUGFzc2tleUxvZ2luEjMudjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5CZWdpblBhc3NrZXlMb2dpblJlcXVlc3QaNC52MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uLkJlZ2luUGFzc2tleUxvZ2luUmVzcG9uc2UiABKDAQoSRmluaXNoUGFzc2tleUxvZ2luEjQudjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5GaW5pc2hQYXNza2V5TG9naW5SZXF1ZXN0GjUudjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5GaW5pc2hQYXNza2V5TG9naW5SZXNwb25zZSIAEmsKClZlcmlmeVRvdHASLC52MS5tYW5hZ2VyLmF1dGhlbnRpY2F0aW9uLlZlcmlmeVRvdHBSZXF1ZXN0Gi0udjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5WZXJpZnlUb3RwUmVzcG9uc2UiABJ3Cg5CZWdpbk9pZGNMb2dpbhIwLnYxLm1hbmFnZXIuYXV0aGVudGljYXRpb24uQmVnaW5PaWRjTG9naW5SZXF1ZXN0GjEudjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5CZWdpbk9pZGNMb2dpblJlc3BvbnNlIgASegoPRmluaXNoT2lkY0xvZ2luEjEudjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5GaW5pc2hPaWRjTG9naW5SZXF1ZXN0GjIudjEubWFuYWdlci5hdXRoZW50aWNhdGlvbi5GaW5pc2hPaWRjTG9naW5SZXNwb25zZSIAQjtaOWdpdGh1Yi5jb20vbWVnYWt1dWwvemVuL3BrZy9hcGkvdjEvbWFuYWdlci9hdXRoZW50aWNhdGlvbmIGcHJvdG8z", [file_v1_manager_verifier]);
 
 /**
  * @generated from message v1.manager.authentication.LoginRequest
@@ -27,6 +27,20 @@ export type LoginRequest = Message<"v1.manager.authentication.LoginRequest"> & {
    * @generated from field: bool auto_refresh = 2;
    */
   autoRefresh: boolean;
+
+  /**
+   * sends a sign-in link along with the code (email stage), the link only works on the requesting device.
+   *
+   * @generated from field: bool magic_link = 3;
+   */
+  magicLink: boolean;
+
+  /**
+   * token of the opened sign-in link, completes the login instead of the verifier.
+   *
+   * @generated from field: string link_token = 4;
+   */
+  linkToken: string;
 };
 
 /**
@@ -44,6 +58,13 @@ export type LoginResponse = Message<"v1.manager.authentication.LoginResponse"> &
    * @generated from field: string token = 1;
    */
   token: string;
+
+  /**
+   * set instead of the token if the user has totp enabled, the login is completed with VerifyTotp.
+   *
+   * @generated from field: string totp_ceremony_id = 2;
+   */
+  totpCeremonyId: string;
 };
 
 /**
@@ -79,6 +100,215 @@ export type LogoutResponse = Message<"v1.manager.authentication.LogoutResponse">
 export const LogoutResponseSchema: GenMessage<LogoutResponse> = /*@__PURE__*/
   messageDesc(file_v1_manager_authentication_authentication, 3);
 
+/**
+ * @generated from message v1.manager.authentication.BeginPasskeyLoginRequest
+ */
+export type BeginPasskeyLoginRequest = Message<"v1.manager.authentication.BeginPasskeyLoginRequest"> & {
+};
+
+/**
+ * Describes the message v1.manager.authentication.BeginPasskeyLoginRequest.
+ * Use `create(BeginPasskeyLoginRequestSchema)` to create a new message.
+ */
+export const BeginPasskeyLoginRequestSchema: GenMessage<BeginPasskeyLoginRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 4);
+
+/**
+ * @generated from message v1.manager.authentication.BeginPasskeyLoginResponse
+ */
+export type BeginPasskeyLoginResponse = Message<"v1.manager.authentication.BeginPasskeyLoginResponse"> & {
+  /**
+   * @generated from field: string ceremony_id = 1;
+   */
+  ceremonyId: string;
+
+  /**
+   * json encoded credential request options for navigator.credentials.get().
+   *
+   * @generated from field: string options = 2;
+   */
+  options: string;
+};
+
+/**
+ * Describes the message v1.manager.authentication.BeginPasskeyLoginResponse.
+ * Use `create(BeginPasskeyLoginResponseSchema)` to create a new message.
+ */
+export const BeginPasskeyLoginResponseSchema: GenMessage<BeginPasskeyLoginResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 5);
+
+/**
+ * @generated from message v1.manager.authentication.FinishPasskeyLoginRequest
+ */
+export type FinishPasskeyLoginRequest = Message<"v1.manager.authentication.FinishPasskeyLoginRequest"> & {
+  /**
+   * @generated from field: string ceremony_id = 1;
+   */
+  ceremonyId: string;
+
+  /**
+   * json encoded public key credential returned by navigator.credentials.get().
+   *
+   * @generated from field: string credential = 2;
+   */
+  credential: string;
+
+  /**
+   * @generated from field: bool auto_refresh = 3;
+   */
+  autoRefresh: boolean;
+};
+
+/**
+ * Describes the message v1.manager.authentication.FinishPasskeyLoginRequest.
+ * Use `create(FinishPasskeyLoginRequestSchema)` to create a new message.
+ */
+export const FinishPasskeyLoginRequestSchema: GenMessage<FinishPasskeyLoginRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 6);
+
+/**
+ * @generated from message v1.manager.authentication.FinishPasskeyLoginResponse
+ */
+export type FinishPasskeyLoginResponse = Message<"v1.manager.authentication.FinishPasskeyLoginResponse"> & {
+  /**
+   * @generated from field: string token = 1;
+   */
+  token: string;
+};
+
+/**
+ * Describes the message v1.manager.authentication.FinishPasskeyLoginResponse.
+ * Use `create(FinishPasskeyLoginResponseSchema)` to create a new message.
+ */
+export const FinishPasskeyLoginResponseSchema: GenMessage<FinishPasskeyLoginResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 7);
+
+/**
+ * @generated from message v1.manager.authentication.VerifyTotpRequest
+ */
+export type VerifyTotpRequest = Message<"v1.manager.authentication.VerifyTotpRequest"> & {
+  /**
+   * @generated from field: string ceremony_id = 1;
+   */
+  ceremonyId: string;
+
+  /**
+   * totp code or one of the recovery codes.
+   *
+   * @generated from field: string code = 2;
+   */
+  code: string;
+};
+
+/**
+ * Describes the message v1.manager.authentication.VerifyTotpRequest.
+ * Use `create(VerifyTotpRequestSchema)` to create a new message.
+ */
+export const VerifyTotpRequestSchema: GenMessage<VerifyTotpRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 8);
+
+/**
+ * @generated from message v1.manager.authentication.VerifyTotpResponse
+ */
+export type VerifyTotpResponse = Message<"v1.manager.authentication.VerifyTotpResponse"> & {
+  /**
+   * @generated from field: string token = 1;
+   */
+  token: string;
+};
+
+/**
+ * Describes the message v1.manager.authentication.VerifyTotpResponse.
+ * Use `create(VerifyTotpResponseSchema)` to create a new message.
+ */
+export const VerifyTotpResponseSchema: GenMessage<VerifyTotpResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 9);
+
+/**
+ * @generated from message v1.manager.authentication.BeginOidcLoginRequest
+ */
+export type BeginOidcLoginRequest = Message<"v1.manager.authentication.BeginOidcLoginRequest"> & {
+  /**
+   * @generated from field: bool auto_refresh = 1;
+   */
+  autoRefresh: boolean;
+};
+
+/**
+ * Describes the message v1.manager.authentication.BeginOidcLoginRequest.
+ * Use `create(BeginOidcLoginRequestSchema)` to create a new message.
+ */
+export const BeginOidcLoginRequestSchema: GenMessage<BeginOidcLoginRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 10);
+
+/**
+ * @generated from message v1.manager.authentication.BeginOidcLoginResponse
+ */
+export type BeginOidcLoginResponse = Message<"v1.manager.authentication.BeginOidcLoginResponse"> & {
+  /**
+   * authorization url of the identity provider the user agent must be redirected to.
+   *
+   * @generated from field: string url = 1;
+   */
+  url: string;
+};
+
+/**
+ * Describes the message v1.manager.authentication.BeginOidcLoginResponse.
+ * Use `create(BeginOidcLoginResponseSchema)` to create a new message.
+ */
+export const BeginOidcLoginResponseSchema: GenMessage<BeginOidcLoginResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 11);
+
+/**
+ * @generated from message v1.manager.authentication.FinishOidcLoginRequest
+ */
+export type FinishOidcLoginRequest = Message<"v1.manager.authentication.FinishOidcLoginRequest"> & {
+  /**
+   * state and code query parameters the identity provider attached to the redirect url.
+   * the state is only accepted from the user agent that started the login (device cookie set by BeginOidcLogin).
+   *
+   * @generated from field: string state = 1;
+   */
+  state: string;
+
+  /**
+   * @generated from field: string code = 2;
+   */
+  code: string;
+};
+
+/**
+ * Describes the message v1.manager.authentication.FinishOidcLoginRequest.
+ * Use `create(FinishOidcLoginRequestSchema)` to create a new message.
+ */
+export const FinishOidcLoginRequestSchema: GenMessage<FinishOidcLoginRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 12);
+
+/**
+ * @generated from message v1.manager.authentication.FinishOidcLoginResponse
+ */
+export type FinishOidcLoginResponse = Message<"v1.manager.authentication.FinishOidcLoginResponse"> & {
+  /**
+   * @generated from field: string token = 1;
+   */
+  token: string;
+
+  /**
+   * set instead of the token if the user has totp enabled, the login is completed with VerifyTotp.
+   *
+   * @generated from field: string totp_ceremony_id = 2;
+   */
+  totpCeremonyId: string;
+};
+
+/**
+ * Describes the message v1.manager.authentication.FinishOidcLoginResponse.
+ * Use `create(FinishOidcLoginResponseSchema)` to create a new message.
+ */
+export const FinishOidcLoginResponseSchema: GenMessage<FinishOidcLoginResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_authentication_authentication, 13);
+
 /**
  * @generated from service v1.manager.authentication.AuthenticationService
  */
@@ -99,6 +329,46 @@ export const AuthenticationService: GenService<{
     input: typeof LogoutRequestSchema;
     output: typeof LogoutResponseSchema;
   },
+  /**
+   * @generated from rpc v1.manager.authentication.AuthenticationService.BeginPasskeyLogin
+   */
+  beginPasskeyLogin: {
+    methodKind: "unary";
+    input: typeof BeginPasskeyLoginRequestSchema;
+    output: typeof BeginPasskeyLoginResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.authentication.AuthenticationService.FinishPasskeyLogin
+   */
+  finishPasskeyLogin: {
+    methodKind: "unary";
+    input: typeof FinishPasskeyLoginRequestSchema;
+    output: typeof FinishPasskeyLoginResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.authentication.AuthenticationService.VerifyTotp
+   */
+  verifyTotp: {
+    methodKind: "unary";
+    input: typeof VerifyTotpRequestSchema;
+    output: typeof VerifyTotpResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.authentication.AuthenticationService.BeginOidcLogin
+   */
+  beginOidcLogin: {
+    methodKind: "unary";
+    input: typeof BeginOidcLoginRequestSchema;
+    output: typeof BeginOidcLoginResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.authentication.AuthenticationService.FinishOidcLogin
+   */
+  finishOidcLogin: {
+    methodKind: "unary";
+    input: typeof FinishOidcLoginRequestSchema;
+    output: typeof FinishOidcLoginResponseSchema;
+  },
 }> = /*@__PURE__*/
   serviceDesc(file_v1_manager_authentication_authentication, 0);
 
@@ -4,6 +4,14 @@
 
 import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
 import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { AuditEvent } from "../audit_pb";
+import { file_v1_manager_audit } from "../audit_pb";
+import type { Passkey } from "../passkey_pb";
+import { file_v1_manager_passkey } from "../passkey_pb";
+import type { PersonalToken } from "../personal_token_pb";
+import { file_v1_manager_personal_token } from "../personal_token_pb";
+import type { Session } from "../session_pb";
+import { file_v1_manager_session } from "../session_pb";
 import type { User } from "../user_pb";
 import { file_v1_manager_user } from "../user_pb";
 import type { Verifier } from "../verifier_pb";
@@ -14,7 +22,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file v1/manager/management/management.proto.
  */
 export const file_v1_manager_management_management: GenFile = /*@__PURE__*/
-  fileDesc("CiZ2MS9tYW5hZ2VyL21hbmFnZW1lbnQvbWFuYWdlbWVudC5wcm90bxIVdjEubWFuYWdlci5tYW5hZ2VtZW50IoUBCg9SZWdpc3RlclJlcXVlc3QSHgoEdXNlchgBIAEoCzIQLnYxLm1hbmFnZXIuVXNlchISCgpjYXB0Y2hhX2lkGAIgASgJEhYKDmNhcHRjaGFfZGlnaXRzGAMgASgJEiYKCHZlcmlmaWVyGAQgASgLMhQudjEubWFuYWdlci5WZXJpZmllciI8ChBSZWdpc3RlclJlc3BvbnNlEhIKCmNhcHRjaGFfaWQYASABKAkSFAoMY2FwdGNoYV9ibG9iGAIgASgMIgwKCkdldFJlcXVlc3QiLQoLR2V0UmVzcG9uc2USHgoEdXNlchgBIAEoCzIQLnYxLm1hbmFnZXIuVXNlciIvCg1VcGRhdGVSZXF1ZXN0Eh4KBHVzZXIYASABKAsyEC52MS5tYW5hZ2VyLlVzZXIiEAoOVXBkYXRlUmVzcG9uc2UiNwoNRGVsZXRlUmVxdWVzdBImCgh2ZXJpZmllchgBIAEoCzIULnYxLm1hbmFnZXIuVmVyaWZpZXIiEAoORGVsZXRlUmVzcG9uc2Uy9AIKEU1hbmFnZW1lbnRTZXJ2aWNlEl0KCFJlZ2lzdGVyEiYudjEubWFuYWdlci5tYW5hZ2VtZW50LlJlZ2lzdGVyUmVxdWVzdBonLnYxLm1hbmFnZXIubWFuYWdlbWVudC5SZWdpc3RlclJlc3BvbnNlIgASTgoDR2V0EiEudjEubWFuYWdlci5tYW5hZ2VtZW50LkdldFJlcXVlc3QaIi52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuR2V0UmVzcG9uc2UiABJXCgZVcGRhdGUSJC52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuVXBkYXRlUmVxdWVzdBolLnYxLm1hbmFnZXIubWFuYWdlbWVudC5VcGRhdGVSZXNwb25zZSIAElcKBkRlbGV0ZRIkLnYxLm1hbmFnZXIubWFuYWdlbWVudC5EZWxldGVSZXF1ZXN0GiUudjEubWFuYWdlci5tYW5hZ2VtZW50LkRlbGV0ZVJlc3BvbnNlIgBCN1o1Z2l0aHViLmNvbS9tZWdha3V1bC96ZW4vcGtnL2FwaS92MS9tYW5hZ2VyL21hbmFnZW1lbnRiBnByb3RvMw", [file_v1_manager_user, file_v1_manager_verifier]);
+  fileDesc("CiZ2MS9tYW5hZ2VyL21hbmFnZW1lbnQvbWFuYWdlbWVudC5wcm90bxIVdjEubWFuYWdlci5tYW5hZ2VtZW50IoUBCg9SZWdpc3RlclJlcXVlc3QSHgoEdXNlchgBIAEoCzIQLnYxLm1hbmFnZXIuVXNlchISCgpjYXB0Y2hhX2lkGAIgASgJEhYKDmNhcHRjaGFfZGlnaXRzGAMgASgJEiYKCHZlcmlmaWVyGAQgASgLMhQudjEubWFuYWdlci5WZXJpZmllciI8ChBSZWdpc3RlclJlc3BvbnNlEhIKCmNhcHRjaGFfaWQYASABKAkSFAoMY2FwdGNoYV9ibG9iGAIgASgMIgwKCkdldFJlcXVlc3QiLQoLR2V0UmVzcG9uc2USHgoEdXNlchgBIAEoCzIQLnYxLm1hbmFnZXIuVXNlciIvCg1VcGRhdGVSZXF1ZXN0Eh4KBHVzZXIYASABKAsyEC52MS5tYW5hZ2VyLlVzZXIiEAoOVXBkYXRlUmVzcG9uc2UiSgoNRGVsZXRlUmVxdWVzdBImCgh2ZXJpZmllchgBIAEoCzIULnYxLm1hbmFnZXIuVmVyaWZpZXISEQoJdG90cF9jb2RlGAMgASgJIhAKDkRlbGV0ZVJlc3BvbnNlIn8KEkNoYW5nZUVtYWlsUmVxdWVzdBImCgh2ZXJpZmllchgBIAEoCzIULnYxLm1hbmFnZXIuVmVyaWZpZXISEQoJdG90cF9jb2RlGAIgASgJEi4KEGN1cnJlbnRfdmVyaWZpZXIYAyABKAsyFC52MS5tYW5hZ2VyLlZlcmlmaWVyIhUKE0NoYW5nZUVtYWlsUmVzcG9uc2UiFQoTTGlzdFNlc3Npb25zUmVxdWVzdCI9ChRMaXN0U2Vzc2lvbnNSZXNwb25zZRIlCghzZXNzaW9ucxgBIAMoCzITLnYxLm1hbmFnZXIuU2Vzc2lvbiIvChRSZXZva2VTZXNzaW9uUmVxdWVzdBIKCgJpZBgBIAEoCRILCgNhbGwYAiABKAgiFwoVUmV2b2tlU2Vzc2lvblJlc3BvbnNlIg8KDUV4cG9ydFJlcXVlc3QiMwoORXhwb3J0UmVzcG9uc2USDwoHYXJjaGl2ZRgBIAEoDBIQCghmaWxlbmFtZRgCIAEoCSJHChZMaXN0QXVkaXRFdmVudHNSZXF1ZXN0Eg8KB3N1YmplY3QYASABKAkSDQoFc2luY2UYAiABKAMSDQoFdW50aWwYAyABKAMiQQoXTGlzdEF1ZGl0RXZlbnRzUmVzcG9uc2USJgoGZXZlbnRzGAEgAygLMhYudjEubWFuYWdlci5BdWRpdEV2ZW50IiEKH0JlZ2luUGFzc2tleVJlZ2lzdHJhdGlvblJlcXVlc3QiSAogQmVnaW5QYXNza2V5UmVnaXN0cmF0aW9uUmVzcG9uc2USEwoLY2VyZW1vbnlfaWQYASABKAkSDwoHb3B0aW9ucxgCIAEoCSJZCiBGaW5pc2hQYXNza2V5UmVnaXN0cmF0aW9uUmVxdWVzdBITCgtjZXJlbW9ueV9pZBgBIAEoCRIMCgRuYW1lGAIgASgJEhIKCmNyZWRlbnRpYWwYAyABKAkiSQohRmluaXNoUGFzc2tleVJlZ2lzdHJhdGlvblJlc3BvbnNlEiQKB3Bhc3NrZXkYASABKAsyEy52MS5tYW5hZ2VyLlBhc3NrZXkiFQoTTGlzdFBhc3NrZXlzUmVxdWVzdCI9ChRMaXN0UGFzc2tleXNSZXNwb25zZRIlCghwYXNza2V5cxgBIAMoCzITLnYxLm1hbmFnZXIuUGFzc2tleSIiChREZWxldGVQYXNza2V5UmVxdWVzdBIKCgJpZBgBIAEoCSIXChVEZWxldGVQYXNza2V5UmVzcG9uc2UiEwoRRW5yb2xsVG90cFJlcXVlc3QiPQoSRW5yb2xsVG90cFJlc3BvbnNlEgsKA3VyaRgBIAEoCRIOCgZzZWNyZXQYAiABKAkSCgoCcXIYAyABKAwiIgoSQ29uZmlybVRvdHBSZXF1ZXN0EgwKBGNvZGUYASABKAkiLQoTQ29uZmlybVRvdHBSZXNwb25zZRIWCg5yZWNvdmVyeV9jb2RlcxgBIAMoCSIiChJEaXNhYmxlVG90cFJlcXVlc3QSDAoEY29kZRgBIAEoCSIVChNEaXNhYmxlVG90cFJlc3BvbnNlIlMKGkNyZWF0ZVBlcnNvbmFsVG9rZW5SZXF1ZXN0EgwKBG5hbWUYASABKAkSDgoGc2NvcGVzGAIgAygJEhcKD2V4cGlyZXNfaW5fZGF5cxgDIAEoAyJfChtDcmVhdGVQZXJzb25hbFRva2VuUmVzcG9uc2USMQoOcGVyc29uYWxfdG9rZW4YASABKAsyGS52MS5tYW5hZ2VyLlBlcnNvbmFsVG9rZW4SDQoFdG9rZW4YAiABKAkiGwoZTGlzdFBlcnNvbmFsVG9rZW5zUmVxdWVzdCJQChpMaXN0UGVyc29uYWxUb2tlbnNSZXNwb25zZRIyCg9wZXJzb25hbF90b2tlbnMYASADKAsyGS52MS5tYW5hZ2VyLlBlcnNvbmFsVG9rZW4iKAoaUmV2b2tlUGVyc29uYWxUb2tlblJlcXVlc3QSCgoCaWQYASABKAkiHQobUmV2b2tlUGVyc29uYWxUb2tlblJlc3BvbnNlMrAQChFNYW5hZ2VtZW50U2VydmljZRJdCghSZWdpc3RlchImLnYxLm1hbmFnZXIubWFuYWdlbWVudC5SZWdpc3RlclJlcXVlc3QaJy52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuUmVnaXN0ZXJSZXNwb25zZSIAEk4KA0dldBIhLnYxLm1hbmFnZXIubWFuYWdlbWVudC5HZXRSZXF1ZXN0GiIudjEubWFuYWdlci5tYW5hZ2VtZW50LkdldFJlc3BvbnNlIgASVwoGVXBkYXRlEiQudjEubWFuYWdlci5tYW5hZ2VtZW50LlVwZGF0ZVJlcXVlc3QaJS52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuVXBkYXRlUmVzcG9uc2UiABJXCgZEZWxldGUSJC52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuRGVsZXRlUmVxdWVzdBolLnYxLm1hbmFnZXIubWFuYWdlbWVudC5EZWxldGVSZXNwb25zZSIAEmYKC0NoYW5nZUVtYWlsEikudjEubWFuYWdlci5tYW5hZ2VtZW50LkNoYW5nZUVtYWlsUmVxdWVzdBoqLnYxLm1hbmFnZXIubWFuYWdlbWVudC5DaGFuZ2VFbWFpbFJlc3BvbnNlIgASVwoGRXhwb3J0EiQudjEubWFuYWdlci5tYW5hZ2VtZW50LkV4cG9ydFJlcXVlc3QaJS52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuRXhwb3J0UmVzcG9uc2UiABJpCgxMaXN0U2Vzc2lvbnMSKi52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuTGlzdFNlc3Npb25zUmVxdWVzdBorLnYxLm1hbmFnZXIubWFuYWdlbWVudC5MaXN0U2Vzc2lvbnNSZXNwb25zZSIAEmwKDVJldm9rZVNlc3Npb24SKy52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuUmV2b2tlU2Vzc2lvblJlcXVlc3QaLC52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuUmV2b2tlU2Vzc2lvblJlc3BvbnNlIgAScgoPTGlzdEF1ZGl0RXZlbnRzEi0udjEubWFuYWdlci5tYW5hZ2VtZW50Lkxpc3RBdWRpdEV2ZW50c1JlcXVlc3QaLi52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuTGlzdEF1ZGl0RXZlbnRzUmVzcG9uc2UiABKNAQoYQmVnaW5QYXNza2V5UmVnaXN0cmF0aW9uEjYudjEubWFuYWdlci5tYW5hZ2VtZW50LkJlZ2luUGFzc2tleVJlZ2lzdHJhdGlvblJlcXVlc3QaNy52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuQmVnaW5QYXNza2V5UmVnaXN0cmF0aW9uUmVzcG9uc2UiABKQAQoZRmluaXNoUGFzc2tleVJlZ2lzdHJhdGlvbhI3LnYxLm1hbmFnZXIubWFuYWdlbWVudC5GaW5pc2hQYXNza2V5UmVnaXN0cmF0aW9uUmVxdWVzdBo4LnYxLm1hbmFnZXIubWFuYWdlbWVudC5GaW5pc2hQYXNza2V5UmVnaXN0cmF0aW9uUmVzcG9uc2UiABJpCgxMaXN0UGFzc2tleXMSKi52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuTGlzdFBhc3NrZXlzUmVxdWVzdBorLnYxLm1hbmFnZXIubWFuYWdlbWVudC5MaXN0UGFzc2tleXNSZXNwb25zZSIAEmwKDURlbGV0ZVBhc3NrZXkSKy52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuRGVsZXRlUGFzc2tleVJlcXVlc3QaLC52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuRGVsZXRlUGFzc2tleVJlc3BvbnNlIgASYwoKRW5yb2xsVG90cBIoLnYxLm1hbmFnZXIubWFuYWdlbWVudC5FbnJvbGxUb3RwUmVxdWVzdBopLnYxLm1hbmFnZXIubWFuYWdlbWVudC5FbnJvbGxUb3RwUmVzcG9uc2UiABJmCgtDb25maXJtVG90cBIpLnYxLm1hbmFnZXIubWFuYWdlbWVudC5Db25maXJtVG90cFJlcXVlc3QaKi52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuQ29uZmlybVRvdHBSZXNwb25zZSIAEmYKC0Rpc2FibGVUb3RwEikudjEubWFuYWdlci5tYW5hZ2VtZW50LkRpc2FibGVUb3RwUmVxdWVzdBoqLnYxLm1hbmFnZXIubWFuYWdlbWVudC5EaXNhYmxlVG90cFJlc3BvbnNlIgASfgoTQ3JlYXRlUGVyc29uYWxUb2tlbhIxLnYxLm1hbmFnZXIubWFuYWdlbWVudC5DcmVhdGVQZXJzb25hbFRva2VuUmVxdWVzdBoyLnYxLm1hbmFnZXIubWFuYWdlbWVudC5DcmVhdGVQZXJzb25hbFRva2VuUmVzcG9uc2UiABJ7ChJMaXN0UGVyc29uYWxUb2tlbnMSMC52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuTGlzdFBlcnNvbmFsVG9rZW5zUmVxdWVzdBoxLnYxLm1hbmFnZXIubWFuYWdlbWVudC5MaXN0UGVyc29uYWxUb2tlbnNSZXNwb25zZSIAEn4KE1Jldm9rZVBlcnNvbmFsVG9rZW4SMS52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuUmV2b2tlUGVyc29uYWxUb2tlblJlcXVlc3QaMi52MS5tYW5hZ2VyLm1hbmFnZW1lbnQuUmV2b2tlUGVyc29uYWxUb2tlblJlc3BvbnNlIgBCN1o1Z2l0aHViLmNvbS9tZWdha3V1bC96ZW4vcGtnL2FwaS92MS9tYW5hZ2VyL21hbmFnZW1lbnRiBnByb3RvMw", [file_v1_manager_audit, file_v1_manager_passkey, file_v1_manager_personal_token, file_v1_manager_session, file_v1_manager_user, file_v1_manager_verifier]);
 
 /**
  * @generated from message v1.manager.management.RegisterRequest
@@ -135,11 +143,17 @@ export const UpdateResponseSchema: GenMessage<UpdateResponse> = /*@__PURE__*/
  */
 export type DeleteRequest = Message<"v1.manager.management.DeleteRequest"> & {
   /**
-   * bool delete_history = 2;
-   *
    * @generated from field: v1.manager.Verifier verifier = 1;
    */
   verifier?: Verifier;
+
+  /**
+   * bool delete_history = 2;
+   * totp code or one of the recovery codes, required if the user has totp enabled.
+   *
+   * @generated from field: string totp_code = 3;
+   */
+  totpCode: string;
 };
 
 /**
@@ -162,6 +176,572 @@ export type DeleteResponse = Message<"v1.manager.management.DeleteResponse"> & {
 export const DeleteResponseSchema: GenMessage<DeleteResponse> = /*@__PURE__*/
   messageDesc(file_v1_manager_management_management, 7);
 
+/**
+ * @generated from message v1.manager.management.ChangeEmailRequest
+ */
+export type ChangeEmailRequest = Message<"v1.manager.management.ChangeEmailRequest"> & {
+  /**
+   * verifier of the new email.
+   *
+   * @generated from field: v1.manager.Verifier verifier = 1;
+   */
+  verifier?: Verifier;
+
+  /**
+   * totp code or one of the recovery codes, required if the user has totp enabled.
+   *
+   * @generated from field: string totp_code = 2;
+   */
+  totpCode: string;
+
+  /**
+   * verifier of the current email, must be in the same stage as the verifier of the new email.
+   *
+   * @generated from field: v1.manager.Verifier current_verifier = 3;
+   */
+  currentVerifier?: Verifier;
+};
+
+/**
+ * Describes the message v1.manager.management.ChangeEmailRequest.
+ * Use `create(ChangeEmailRequestSchema)` to create a new message.
+ */
+export const ChangeEmailRequestSchema: GenMessage<ChangeEmailRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 8);
+
+/**
+ * @generated from message v1.manager.management.ChangeEmailResponse
+ */
+export type ChangeEmailResponse = Message<"v1.manager.management.ChangeEmailResponse"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.ChangeEmailResponse.
+ * Use `create(ChangeEmailResponseSchema)` to create a new message.
+ */
+export const ChangeEmailResponseSchema: GenMessage<ChangeEmailResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 9);
+
+/**
+ * @generated from message v1.manager.management.ListSessionsRequest
+ */
+export type ListSessionsRequest = Message<"v1.manager.management.ListSessionsRequest"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.ListSessionsRequest.
+ * Use `create(ListSessionsRequestSchema)` to create a new message.
+ */
+export const ListSessionsRequestSchema: GenMessage<ListSessionsRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 10);
+
+/**
+ * @generated from message v1.manager.management.ListSessionsResponse
+ */
+export type ListSessionsResponse = Message<"v1.manager.management.ListSessionsResponse"> & {
+  /**
+   * @generated from field: repeated v1.manager.Session sessions = 1;
+   */
+  sessions: Session[];
+};
+
+/**
+ * Describes the message v1.manager.management.ListSessionsResponse.
+ * Use `create(ListSessionsResponseSchema)` to create a new message.
+ */
+export const ListSessionsResponseSchema: GenMessage<ListSessionsResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 11);
+
+/**
+ * @generated from message v1.manager.management.RevokeSessionRequest
+ */
+export type RevokeSessionRequest = Message<"v1.manager.management.RevokeSessionRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * revokes all sessions of the user (including the current one), id is ignored.
+   *
+   * @generated from field: bool all = 2;
+   */
+  all: boolean;
+};
+
+/**
+ * Describes the message v1.manager.management.RevokeSessionRequest.
+ * Use `create(RevokeSessionRequestSchema)` to create a new message.
+ */
+export const RevokeSessionRequestSchema: GenMessage<RevokeSessionRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 12);
+
+/**
+ * @generated from message v1.manager.management.RevokeSessionResponse
+ */
+export type RevokeSessionResponse = Message<"v1.manager.management.RevokeSessionResponse"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.RevokeSessionResponse.
+ * Use `create(RevokeSessionResponseSchema)` to create a new message.
+ */
+export const RevokeSessionResponseSchema: GenMessage<RevokeSessionResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 13);
+
+/**
+ * @generated from message v1.manager.management.ExportRequest
+ */
+export type ExportRequest = Message<"v1.manager.management.ExportRequest"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.ExportRequest.
+ * Use `create(ExportRequestSchema)` to create a new message.
+ */
+export const ExportRequestSchema: GenMessage<ExportRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 14);
+
+/**
+ * @generated from message v1.manager.management.ExportResponse
+ */
+export type ExportResponse = Message<"v1.manager.management.ExportResponse"> & {
+  /**
+   * zip archive containing the personal data (profile, events and leaderboard entries) as json and csv.
+   *
+   * @generated from field: bytes archive = 1;
+   */
+  archive: Uint8Array;
+
+  /**
+   * @generated from field: string filename = 2;
+   */
+  filename: string;
+};
+
+/**
+ * Describes the message v1.manager.management.ExportResponse.
+ * Use `create(ExportResponseSchema)` to create a new message.
+ */
+export const ExportResponseSchema: GenMessage<ExportResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 15);
+
+/**
+ * @generated from message v1.manager.management.ListAuditEventsRequest
+ */
+export type ListAuditEventsRequest = Message<"v1.manager.management.ListAuditEventsRequest"> & {
+  /**
+   * subject of the investigated user, defaults to the caller (other users require support permissions).
+   *
+   * @generated from field: string subject = 1;
+   */
+  subject: string;
+
+  /**
+   * time range in unix seconds (until defaults to now), the latest 100 events of the range are returned (newest first).
+   *
+   * @generated from field: int64 since = 2;
+   */
+  since: bigint;
+
+  /**
+   * @generated from field: int64 until = 3;
+   */
+  until: bigint;
+};
+
+/**
+ * Describes the message v1.manager.management.ListAuditEventsRequest.
+ * Use `create(ListAuditEventsRequestSchema)` to create a new message.
+ */
+export const ListAuditEventsRequestSchema: GenMessage<ListAuditEventsRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 16);
+
+/**
+ * @generated from message v1.manager.management.ListAuditEventsResponse
+ */
+export type ListAuditEventsResponse = Message<"v1.manager.management.ListAuditEventsResponse"> & {
+  /**
+   * @generated from field: repeated v1.manager.AuditEvent events = 1;
+   */
+  events: AuditEvent[];
+};
+
+/**
+ * Describes the message v1.manager.management.ListAuditEventsResponse.
+ * Use `create(ListAuditEventsResponseSchema)` to create a new message.
+ */
+export const ListAuditEventsResponseSchema: GenMessage<ListAuditEventsResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 17);
+
+/**
+ * @generated from message v1.manager.management.BeginPasskeyRegistrationRequest
+ */
+export type BeginPasskeyRegistrationRequest = Message<"v1.manager.management.BeginPasskeyRegistrationRequest"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.BeginPasskeyRegistrationRequest.
+ * Use `create(BeginPasskeyRegistrationRequestSchema)` to create a new message.
+ */
+export const BeginPasskeyRegistrationRequestSchema: GenMessage<BeginPasskeyRegistrationRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 18);
+
+/**
+ * @generated from message v1.manager.management.BeginPasskeyRegistrationResponse
+ */
+export type BeginPasskeyRegistrationResponse = Message<"v1.manager.management.BeginPasskeyRegistrationResponse"> & {
+  /**
+   * @generated from field: string ceremony_id = 1;
+   */
+  ceremonyId: string;
+
+  /**
+   * json encoded credential creation options for navigator.credentials.create().
+   *
+   * @generated from field: string options = 2;
+   */
+  options: string;
+};
+
+/**
+ * Describes the message v1.manager.management.BeginPasskeyRegistrationResponse.
+ * Use `create(BeginPasskeyRegistrationResponseSchema)` to create a new message.
+ */
+export const BeginPasskeyRegistrationResponseSchema: GenMessage<BeginPasskeyRegistrationResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 19);
+
+/**
+ * @generated from message v1.manager.management.FinishPasskeyRegistrationRequest
+ */
+export type FinishPasskeyRegistrationRequest = Message<"v1.manager.management.FinishPasskeyRegistrationRequest"> & {
+  /**
+   * @generated from field: string ceremony_id = 1;
+   */
+  ceremonyId: string;
+
+  /**
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * json encoded public key credential returned by navigator.credentials.create().
+   *
+   * @generated from field: string credential = 3;
+   */
+  credential: string;
+};
+
+/**
+ * Describes the message v1.manager.management.FinishPasskeyRegistrationRequest.
+ * Use `create(FinishPasskeyRegistrationRequestSchema)` to create a new message.
+ */
+export const FinishPasskeyRegistrationRequestSchema: GenMessage<FinishPasskeyRegistrationRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 20);
+
+/**
+ * @generated from message v1.manager.management.FinishPasskeyRegistrationResponse
+ */
+export type FinishPasskeyRegistrationResponse = Message<"v1.manager.management.FinishPasskeyRegistrationResponse"> & {
+  /**
+   * @generated from field: v1.manager.Passkey passkey = 1;
+   */
+  passkey?: Passkey;
+};
+
+/**
+ * Describes the message v1.manager.management.FinishPasskeyRegistrationResponse.
+ * Use `create(FinishPasskeyRegistrationResponseSchema)` to create a new message.
+ */
+export const FinishPasskeyRegistrationResponseSchema: GenMessage<FinishPasskeyRegistrationResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 21);
+
+/**
+ * @generated from message v1.manager.management.ListPasskeysRequest
+ */
+export type ListPasskeysRequest = Message<"v1.manager.management.ListPasskeysRequest"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.ListPasskeysRequest.
+ * Use `create(ListPasskeysRequestSchema)` to create a new message.
+ */
+export const ListPasskeysRequestSchema: GenMessage<ListPasskeysRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 22);
+
+/**
+ * @generated from message v1.manager.management.ListPasskeysResponse
+ */
+export type ListPasskeysResponse = Message<"v1.manager.management.ListPasskeysResponse"> & {
+  /**
+   * @generated from field: repeated v1.manager.Passkey passkeys = 1;
+   */
+  passkeys: Passkey[];
+};
+
+/**
+ * Describes the message v1.manager.management.ListPasskeysResponse.
+ * Use `create(ListPasskeysResponseSchema)` to create a new message.
+ */
+export const ListPasskeysResponseSchema: GenMessage<ListPasskeysResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 23);
+
+/**
+ * @generated from message v1.manager.management.DeletePasskeyRequest
+ */
+export type DeletePasskeyRequest = Message<"v1.manager.management.DeletePasskeyRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+};
+
+/**
+ * Describes the message v1.manager.management.DeletePasskeyRequest.
+ * Use `create(DeletePasskeyRequestSchema)` to create a new message.
+ */
+export const DeletePasskeyRequestSchema: GenMessage<DeletePasskeyRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 24);
+
+/**
+ * @generated from message v1.manager.management.DeletePasskeyResponse
+ */
+export type DeletePasskeyResponse = Message<"v1.manager.management.DeletePasskeyResponse"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.DeletePasskeyResponse.
+ * Use `create(DeletePasskeyResponseSchema)` to create a new message.
+ */
+export const DeletePasskeyResponseSchema: GenMessage<DeletePasskeyResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 25);
+
+/**
+ * @generated from message v1.manager.management.EnrollTotpRequest
+ */
+export type EnrollTotpRequest = Message<"v1.manager.management.EnrollTotpRequest"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.EnrollTotpRequest.
+ * Use `create(EnrollTotpRequestSchema)` to create a new message.
+ */
+export const EnrollTotpRequestSchema: GenMessage<EnrollTotpRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 26);
+
+/**
+ * @generated from message v1.manager.management.EnrollTotpResponse
+ */
+export type EnrollTotpResponse = Message<"v1.manager.management.EnrollTotpResponse"> & {
+  /**
+   * otpauth:// uri for authenticator apps.
+   *
+   * @generated from field: string uri = 1;
+   */
+  uri: string;
+
+  /**
+   * @generated from field: string secret = 2;
+   */
+  secret: string;
+
+  /**
+   * png encoded qr code of the uri.
+   *
+   * @generated from field: bytes qr = 3;
+   */
+  qr: Uint8Array;
+};
+
+/**
+ * Describes the message v1.manager.management.EnrollTotpResponse.
+ * Use `create(EnrollTotpResponseSchema)` to create a new message.
+ */
+export const EnrollTotpResponseSchema: GenMessage<EnrollTotpResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 27);
+
+/**
+ * @generated from message v1.manager.management.ConfirmTotpRequest
+ */
+export type ConfirmTotpRequest = Message<"v1.manager.management.ConfirmTotpRequest"> & {
+  /**
+   * @generated from field: string code = 1;
+   */
+  code: string;
+};
+
+/**
+ * Describes the message v1.manager.management.ConfirmTotpRequest.
+ * Use `create(ConfirmTotpRequestSchema)` to create a new message.
+ */
+export const ConfirmTotpRequestSchema: GenMessage<ConfirmTotpRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 28);
+
+/**
+ * @generated from message v1.manager.management.ConfirmTotpResponse
+ */
+export type ConfirmTotpResponse = Message<"v1.manager.management.ConfirmTotpResponse"> & {
+  /**
+   * single use recovery codes, they are only returned once.
+   *
+   * @generated from field: repeated string recovery_codes = 1;
+   */
+  recoveryCodes: string[];
+};
+
+/**
+ * Describes the message v1.manager.management.ConfirmTotpResponse.
+ * Use `create(ConfirmTotpResponseSchema)` to create a new message.
+ */
+export const ConfirmTotpResponseSchema: GenMessage<ConfirmTotpResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 29);
+
+/**
+ * @generated from message v1.manager.management.DisableTotpRequest
+ */
+export type DisableTotpRequest = Message<"v1.manager.management.DisableTotpRequest"> & {
+  /**
+   * totp code or one of the recovery codes.
+   *
+   * @generated from field: string code = 1;
+   */
+  code: string;
+};
+
+/**
+ * Describes the message v1.manager.management.DisableTotpRequest.
+ * Use `create(DisableTotpRequestSchema)` to create a new message.
+ */
+export const DisableTotpRequestSchema: GenMessage<DisableTotpRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 30);
+
+/**
+ * @generated from message v1.manager.management.DisableTotpResponse
+ */
+export type DisableTotpResponse = Message<"v1.manager.management.DisableTotpResponse"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.DisableTotpResponse.
+ * Use `create(DisableTotpResponseSchema)` to create a new message.
+ */
+export const DisableTotpResponseSchema: GenMessage<DisableTotpResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 31);
+
+/**
+ * @generated from message v1.manager.management.CreatePersonalTokenRequest
+ */
+export type CreatePersonalTokenRequest = Message<"v1.manager.management.CreatePersonalTokenRequest"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * @generated from field: repeated string scopes = 2;
+   */
+  scopes: string[];
+
+  /**
+   * lifetime of the token in days (defaults to 90, at most 365).
+   *
+   * @generated from field: int64 expires_in_days = 3;
+   */
+  expiresInDays: bigint;
+};
+
+/**
+ * Describes the message v1.manager.management.CreatePersonalTokenRequest.
+ * Use `create(CreatePersonalTokenRequestSchema)` to create a new message.
+ */
+export const CreatePersonalTokenRequestSchema: GenMessage<CreatePersonalTokenRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 32);
+
+/**
+ * @generated from message v1.manager.management.CreatePersonalTokenResponse
+ */
+export type CreatePersonalTokenResponse = Message<"v1.manager.management.CreatePersonalTokenResponse"> & {
+  /**
+   * @generated from field: v1.manager.PersonalToken personal_token = 1;
+   */
+  personalToken?: PersonalToken;
+
+  /**
+   * the signed token, it is only returned once.
+   *
+   * @generated from field: string token = 2;
+   */
+  token: string;
+};
+
+/**
+ * Describes the message v1.manager.management.CreatePersonalTokenResponse.
+ * Use `create(CreatePersonalTokenResponseSchema)` to create a new message.
+ */
+export const CreatePersonalTokenResponseSchema: GenMessage<CreatePersonalTokenResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 33);
+
+/**
+ * @generated from message v1.manager.management.ListPersonalTokensRequest
+ */
+export type ListPersonalTokensRequest = Message<"v1.manager.management.ListPersonalTokensRequest"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.ListPersonalTokensRequest.
+ * Use `create(ListPersonalTokensRequestSchema)` to create a new message.
+ */
+export const ListPersonalTokensRequestSchema: GenMessage<ListPersonalTokensRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 34);
+
+/**
+ * @generated from message v1.manager.management.ListPersonalTokensResponse
+ */
+export type ListPersonalTokensResponse = Message<"v1.manager.management.ListPersonalTokensResponse"> & {
+  /**
+   * @generated from field: repeated v1.manager.PersonalToken personal_tokens = 1;
+   */
+  personalTokens: PersonalToken[];
+};
+
+/**
+ * Describes the message v1.manager.management.ListPersonalTokensResponse.
+ * Use `create(ListPersonalTokensResponseSchema)` to create a new message.
+ */
+export const ListPersonalTokensResponseSchema: GenMessage<ListPersonalTokensResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 35);
+
+/**
+ * @generated from message v1.manager.management.RevokePersonalTokenRequest
+ */
+export type RevokePersonalTokenRequest = Message<"v1.manager.management.RevokePersonalTokenRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+};
+
+/**
+ * Describes the message v1.manager.management.RevokePersonalTokenRequest.
+ * Use `create(RevokePersonalTokenRequestSchema)` to create a new message.
+ */
+export const RevokePersonalTokenRequestSchema: GenMessage<RevokePersonalTokenRequest> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 36);
+
+/**
+ * @generated from message v1.manager.management.RevokePersonalTokenResponse
+ */
+export type RevokePersonalTokenResponse = Message<"v1.manager.management.RevokePersonalTokenResponse"> & {
+};
+
+/**
+ * Describes the message v1.manager.management.RevokePersonalTokenResponse.
+ * Use `create(RevokePersonalTokenResponseSchema)` to create a new message.
+ */
+export const RevokePersonalTokenResponseSchema: GenMessage<RevokePersonalTokenResponse> = /*@__PURE__*/
+  messageDesc(file_v1_manager_management_management, 37);
+
 /**
  * @generated from service v1.manager.management.ManagementService
  */
@@ -198,6 +778,126 @@ export const ManagementService: GenService<{
     input: typeof DeleteRequestSchema;
     output: typeof DeleteResponseSchema;
   },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.ChangeEmail
+   */
+  changeEmail: {
+    methodKind: "unary";
+    input: typeof ChangeEmailRequestSchema;
+    output: typeof ChangeEmailResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.Export
+   */
+  export: {
+    methodKind: "unary";
+    input: typeof ExportRequestSchema;
+    output: typeof ExportResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.ListSessions
+   */
+  listSessions: {
+    methodKind: "unary";
+    input: typeof ListSessionsRequestSchema;
+    output: typeof ListSessionsResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.RevokeSession
+   */
+  revokeSession: {
+    methodKind: "unary";
+    input: typeof RevokeSessionRequestSchema;
+    output: typeof RevokeSessionResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.ListAuditEvents
+   */
+  listAuditEvents: {
+    methodKind: "unary";
+    input: typeof ListAuditEventsRequestSchema;
+    output: typeof ListAuditEventsResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.BeginPasskeyRegistration
+   */
+  beginPasskeyRegistration: {
+    methodKind: "unary";
+    input: typeof BeginPasskeyRegistrationRequestSchema;
+    output: typeof BeginPasskeyRegistrationResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.FinishPasskeyRegistration
+   */
+  finishPasskeyRegistration: {
+    methodKind: "unary";
+    input: typeof FinishPasskeyRegistrationRequestSchema;
+    output: typeof FinishPasskeyRegistrationResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.ListPasskeys
+   */
+  listPasskeys: {
+    methodKind: "unary";
+    input: typeof ListPasskeysRequestSchema;
+    output: typeof ListPasskeysResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.DeletePasskey
+   */
+  deletePasskey: {
+    methodKind: "unary";
+    input: typeof DeletePasskeyRequestSchema;
+    output: typeof DeletePasskeyResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.EnrollTotp
+   */
+  enrollTotp: {
+    methodKind: "unary";
+    input: typeof EnrollTotpRequestSchema;
+    output: typeof EnrollTotpResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.ConfirmTotp
+   */
+  confirmTotp: {
+    methodKind: "unary";
+    input: typeof ConfirmTotpRequestSchema;
+    output: typeof ConfirmTotpResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.DisableTotp
+   */
+  disableTotp: {
+    methodKind: "unary";
+    input: typeof DisableTotpRequestSchema;
+    output: typeof DisableTotpResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.CreatePersonalToken
+   */
+  createPersonalToken: {
+    methodKind: "unary";
+    input: typeof CreatePersonalTokenRequestSchema;
+    output: typeof CreatePersonalTokenResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.ListPersonalTokens
+   */
+  listPersonalTokens: {
+    methodKind: "unary";
+    input: typeof ListPersonalTokensRequestSchema;
+    output: typeof ListPersonalTokensResponseSchema;
+  },
+  /**
+   * @generated from rpc v1.manager.management.ManagementService.RevokePersonalToken
+   */
+  revokePersonalToken: {
+    methodKind: "unary";
+    input: typeof RevokePersonalTokenRequestSchema;
+    output: typeof RevokePersonalTokenResponseSchema;
+  },
 }> = /*@__PURE__*/
   serviceDesc(file_v1_manager_management_management, 0);
 
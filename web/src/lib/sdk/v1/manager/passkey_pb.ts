@@ -0,0 +1,46 @@
+// @generated by protoc-gen-es v2.7.0 with parameter "target=ts"
+// @generated from file v1/manager/passkey.proto (package v1.manager, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file v1/manager/passkey.proto.
+ */
+export const file_v1_manager_passkey: GenFile = /*@__PURE__*/
+  fileDesc("Chh2MS9tYW5hZ2VyL3Bhc3NrZXkucHJvdG8SCnYxLm1hbmFnZXIiTQoHUGFzc2tleRIKCgJpZBgBIAEoCRIMCgRuYW1lGAIgASgJEhIKCmNyZWF0ZWRfYXQYAyABKAMSFAoMbGFzdF91c2VkX2F0GAQgASgDQixaKmdpdGh1Yi5jb20vbWVnYWt1dWwvemVuL3BrZy9hcGkvdjEvbWFuYWdlcmIGcHJvdG8z");
+
+/**
+ * @generated from message v1.manager.Passkey
+ */
+export type Passkey = Message<"v1.manager.Passkey"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * @generated from field: int64 created_at = 3;
+   */
+  createdAt: bigint;
+
+  /**
+   * @generated from field: int64 last_used_at = 4;
+   */
+  lastUsedAt: bigint;
+};
+
+/**
+ * Describes the message v1.manager.Passkey.
+ * Use `create(PasskeySchema)` to create a new message.
+ */
+export const PasskeySchema: GenMessage<Passkey> = /*@__PURE__*/
+  messageDesc(file_v1_manager_passkey, 0);
+
@@ -0,0 +1,53 @@
+// @generated by protoc-gen-es v2.7.0 with parameter "target=ts"
+// @generated from file v1/manager/personal_token.proto (package v1.manager, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file v1/manager/personal_token.proto.
+ */
+export const file_v1_manager_personal_token: GenFile = /*@__PURE__*/
+  fileDesc("Ch92MS9tYW5hZ2VyL3BlcnNvbmFsX3Rva2VuLnByb3RvEgp2MS5tYW5hZ2VyImEKDVBlcnNvbmFsVG9rZW4SCgoCaWQYASABKAkSDAoEbmFtZRgCIAEoCRIOCgZzY29wZXMYAyADKAkSEgoKY3JlYXRlZF9hdBgEIAEoAxISCgpleHBpcmVzX2F0GAUgASgDQixaKmdpdGh1Yi5jb20vbWVnYWt1dWwvemVuL3BrZy9hcGkvdjEvbWFuYWdlcmIGcHJvdG8z");
+
+/**
+ * @generated from message v1.manager.PersonalToken
+ */
+export type PersonalToken = Message<"v1.manager.PersonalToken"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * granted scopes: "planning:read", "planning:write", "timing" or "profile:read".
+   *
+   * @generated from field: repeated string scopes = 3;
+   */
+  scopes: string[];
+
+  /**
+   * @generated from field: int64 created_at = 4;
+   */
+  createdAt: bigint;
+
+  /**
+   * @generated from field: int64 expires_at = 5;
+   */
+  expiresAt: bigint;
+};
+
+/**
+ * Describes the message v1.manager.PersonalToken.
+ * Use `create(PersonalTokenSchema)` to create a new message.
+ */
+export const PersonalTokenSchema: GenMessage<PersonalToken> = /*@__PURE__*/
+  messageDesc(file_v1_manager_personal_token, 0);
+
@@ -0,0 +1,61 @@
+// @generated by protoc-gen-es v2.7.0 with parameter "target=ts"
+// @generated from file v1/manager/session.proto (package v1.manager, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file v1/manager/session.proto.
+ */
+export const file_v1_manager_session: GenFile = /*@__PURE__*/
+  fileDesc("Chh2MS9tYW5hZ2VyL3Nlc3Npb24ucHJvdG8SCnYxLm1hbmFnZXIiiAEKB1Nlc3Npb24SCgoCaWQYASABKAkSEgoKdXNlcl9hZ2VudBgCIAEoCRIOCgZvcmlnaW4YAyABKAkSEgoKY3JlYXRlZF9hdBgEIAEoAxIUCgxsYXN0X3VzZWRfYXQYBSABKAMSEgoKZXhwaXJlc19hdBgGIAEoAxIPCgdjdXJyZW50GAcgASgIQixaKmdpdGh1Yi5jb20vbWVnYWt1dWwvemVuL3BrZy9hcGkvdjEvbWFuYWdlcmIGcHJvdG8z");
+
+/**
+ * @generated from message v1.manager.Session
+ */
+export type Session = Message<"v1.manager.Session"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string user_agent = 2;
+   */
+  userAgent: string;
+
+  /**
+   * @generated from field: string origin = 3;
+   */
+  origin: string;
+
+  /**
+   * @generated from field: int64 created_at = 4;
+   */
+  createdAt: bigint;
+
+  /**
+   * @generated from field: int64 last_used_at = 5;
+   */
+  lastUsedAt: bigint;
+
+  /**
+   * @generated from field: int64 expires_at = 6;
+   */
+  expiresAt: bigint;
+
+  /**
+   * @generated from field: bool current = 7;
+   */
+  current: boolean;
+};
+
+/**
+ * Describes the message v1.manager.Session.
+ * Use `create(SessionSchema)` to create a new message.
+ */
+export const SessionSchema: GenMessage<Session> = /*@__PURE__*/
+  messageDesc(file_v1_manager_session, 0);
+